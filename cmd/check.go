@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+var checkJSON bool
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Reports generated files that are out of date without regenerating them",
+	Long: `Hashes every route file and compares it with the generation records saved by the
+last conduit generate, and compares every copied dependency with its source, to find
+generated files that are stale. Nothing is written.
+
+Exits with code 0 when everything is up to date, 1 when any generated file is stale
+and 2 when any route file has syntax errors, so it can gate merges in CI.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("check called")
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		report, err := generator.NewRouteGenerator(wd).CheckOutputs()
+		if err != nil {
+			return fmt.Errorf("failed to check generated files: %w", err)
+		}
+
+		if checkJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				return fmt.Errorf("failed to encode results: %w", err)
+			}
+		} else {
+			for _, sourceErr := range report.Errors {
+				logger.Error("%s: %s", sourceErr.Source, sourceErr.Message)
+			}
+			if len(report.Stale) > 0 {
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "OUTPUT\tSOURCE\tREASON")
+				for _, stale := range report.Stale {
+					source := stale.Source
+					if source == "" {
+						source = "-"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\n", stale.Output, source, stale.Reason)
+				}
+				w.Flush()
+			}
+			if len(report.Errors) == 0 && len(report.Stale) == 0 {
+				logger.Info("Generated files are up to date")
+			}
+		}
+
+		switch {
+		case len(report.Errors) > 0:
+			cmd.SilenceUsage = true
+			return &exitError{code: 2, err: fmt.Errorf("%d route file(s) have errors", len(report.Errors))}
+		case len(report.Stale) > 0:
+			cmd.SilenceUsage = true
+			return &exitError{code: 1, err: fmt.Errorf("%d generated file(s) are stale, run conduit generate", len(report.Stale))}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	checkCmd.Flags().BoolVar(&checkJSON, "json", false, "Print the results as JSON")
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/logger"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Prints the effective configuration",
+	Long: `Loads conduit.yaml (or conduit.json / conduit.toml), applies environment overrides
+and prints the resulting configuration as YAML.
+
+Any field can be overridden with an environment variable named CONDUIT_ followed by
+its dotted key in upper case with dots replaced by underscores. Lists are comma-separated.
+
+` + envOverrideHelp(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("config called")
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(cfg); err != nil {
+			return fmt.Errorf("failed to encode config: %w", err)
+		}
+		return encoder.Close()
+	},
+}
+
+// envOverrideHelp renders the override table shown in `conduit config --help`
+func envOverrideHelp() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VARIABLE\tKEY\tTYPE")
+	for _, override := range config.EnvOverrides() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", override.Name, override.Key, override.Type)
+	}
+	w.Flush()
+	return b.String()
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
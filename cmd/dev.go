@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/cache"
+	"github.com/tristendillon/conduit/core/config"
 	"github.com/tristendillon/conduit/core/generator"
 	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/metrics"
+	"github.com/tristendillon/conduit/core/server"
 	"github.com/tristendillon/conduit/core/watcher"
 )
 
@@ -23,6 +28,48 @@ var devCmd = &cobra.Command{
 			return fmt.Errorf("failed to get working directory: %w", err)
 		}
 
+		if devMetricsAddr != "" {
+			if err := metrics.StartMetricsServer(devMetricsAddr); err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+		}
+
+		if devDebugAddr != "" {
+			debugServer, err := metrics.StartDebugServer(devDebugAddr, wd)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			defer func() {
+				if err := debugServer.Stop(); err != nil {
+					logger.Debug("Failed to stop cache debug server: %v", err)
+				}
+			}()
+		}
+
+		// Stops the cache cleanup janitor once the watcher is done
+		defer cache.GetCacheManager().Close()
+
+		var process *server.Process
+		if devServe {
+			if process, err = newServerProcess(devServeAddr); err != nil {
+				return err
+			}
+			defer func() {
+				if err := process.Stop(); err != nil {
+					logger.Debug("Server exited with error: %v", err)
+				}
+			}()
+		}
+		// restartServer (re)starts the server after a successful generation
+		restartServer := func() error {
+			if process == nil {
+				return nil
+			}
+			return process.Restart(context.Background())
+		}
+
 		generator := generator.NewRouteGenerator(wd)
 		excludePaths := generator.Walker.Exclude
 
@@ -34,7 +81,10 @@ var devCmd = &cobra.Command{
 			logger.Info("File watcher started, watching directory: %s", wd)
 			logger.Info("Press Ctrl+C to stop...")
 
-			return generator.GenerateRouteTree(logger.DEBUG)
+			if err := generator.GenerateRouteTree(logger.DEBUG); err != nil {
+				return err
+			}
+			return restartServer()
 		})
 		fw.FileWatcher.AddOnChangeFunc(func() error {
 			startTime := time.Now()
@@ -45,19 +95,36 @@ var devCmd = &cobra.Command{
 				return err
 			}
 			logger.Info("Route tree generated successfully in %dms", time.Since(startTime).Milliseconds())
+			return restartServer()
+		})
+		fw.FileWatcher.AddOnConfigChangeFunc(func(cfg *config.Config) error {
+			generator.SetConfig(cfg)
+			logger.Info("Config reloaded, changes apply from the next generation")
 			return nil
 		})
 		fw.FileWatcher.AddOnCloseFunc(func() error {
 			logger.Info("File watcher closed")
 			return nil
 		})
-		if err := fw.Watch(); err != nil {
+		if err := watcher.ForMode(fw).Watch(); err != nil {
 			return fmt.Errorf("failed to watch directory: %w", err)
 		}
 		return nil
 	},
 }
 
+var (
+	devMetricsAddr string
+	devDebugAddr   string
+	devServe       bool
+	devServeAddr   string
+)
+
 func init() {
 	rootCmd.AddCommand(devCmd)
+
+	devCmd.Flags().StringVar(&devMetricsAddr, "metrics-addr", "", "Serve cache metrics for Prometheus at this address, e.g. :9123")
+	devCmd.Flags().StringVar(&devDebugAddr, "debug-addr", "", "Serve cache stats and dependency lookups as JSON at this address, e.g. :9124")
+	devCmd.Flags().BoolVar(&devServe, "serve", false, "Run the generated server like conduit serve and restart it after every regeneration")
+	devCmd.Flags().StringVar(&devServeAddr, "serve-addr", "", "Address for the server started by --serve to listen on")
 }
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/models"
+)
+
+var diffJSON bool
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Shows the routes added, removed or changed since the last generation",
+	Long: `Walks the route tree and compares it with the routes recorded by the last
+conduit generate. Added routes are marked +, removed routes - and routes whose
+methods changed ~. Nothing is written.
+
+Exits with code 0 when the routes are unchanged and 1 when any changed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("diff called")
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		diff, err := generator.NewRouteGenerator(wd).DiffRoutes()
+		if err != nil {
+			return fmt.Errorf("failed to diff routes: %w", err)
+		}
+
+		if diffJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(diff); err != nil {
+				return fmt.Errorf("failed to encode results: %w", err)
+			}
+		} else {
+			printRouteDiff(diff)
+		}
+
+		if !diff.Empty() {
+			cmd.SilenceUsage = true
+			changes := len(diff.Added) + len(diff.Removed) + len(diff.Modified)
+			return &exitError{code: 1, err: fmt.Errorf("%d route(s) changed since the last generation", changes)}
+		}
+		return nil
+	},
+}
+
+// printRouteDiff prints one line per changed route, marked and colored like a diff
+func printRouteDiff(diff models.RouteDiff) {
+	if diff.Empty() {
+		logger.Info("Routes are unchanged since the last generation")
+		return
+	}
+
+	for _, change := range diff.Added {
+		fmt.Println(logger.Colorize(logger.ColorGreen, fmt.Sprintf("+ /%s [%s]", change.APIPath, strings.Join(change.Methods, ", "))))
+	}
+	for _, change := range diff.Removed {
+		fmt.Println(logger.Colorize(logger.ColorRed, fmt.Sprintf("- /%s [%s]", change.APIPath, strings.Join(change.PreviousMethods, ", "))))
+	}
+	for _, change := range diff.Modified {
+		fmt.Println(logger.Colorize(logger.ColorYellow, fmt.Sprintf("~ /%s [%s] -> [%s]", change.APIPath,
+			strings.Join(change.PreviousMethods, ", "), strings.Join(change.Methods, ", "))))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().BoolVar(&diffJSON, "json", false, "Print the changes as JSON")
+}
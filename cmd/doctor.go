@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+var doctorJSON bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Reports dependency cycles, unparsable route files and routes without methods",
+	Long: `Warms the cache and builds the dependency graph like conduit generate, then reports
+what would break or surprise generation: every dependency cycle, printed to stderr as
+A -> B -> C -> A, route files that fail to parse and routes that declare no HTTP
+methods. Nothing is written.
+
+Exits with code 1 when any cycle or parse error is found; routes without methods are
+only warned about.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("doctor called")
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		report, err := generator.NewRouteGenerator(wd).Doctor()
+		if err != nil {
+			return fmt.Errorf("failed to diagnose project: %w", err)
+		}
+
+		if doctorJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				return fmt.Errorf("failed to encode results: %w", err)
+			}
+		} else {
+			for _, cycle := range report.Cycles {
+				fmt.Fprintf(os.Stderr, "cycle: %s\n", strings.Join(cycle, " -> "))
+			}
+			for _, sourceErr := range report.ParseErrors {
+				logger.Error("%s: %s", sourceErr.Source, sourceErr.Message)
+			}
+			for _, route := range report.EmptyRoutes {
+				logger.Warn("%s declares no HTTP methods", route)
+			}
+			if report.Healthy() && len(report.EmptyRoutes) == 0 {
+				logger.Info("No problems found")
+			}
+		}
+
+		if !report.Healthy() {
+			cmd.SilenceUsage = true
+			return &exitError{code: 1, err: fmt.Errorf("found %d dependency cycle(s) and %d route file(s) with parse errors", len(report.Cycles), len(report.ParseErrors))}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Print the results as JSON")
+}
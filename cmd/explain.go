@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+var explainJSON bool
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <path/to/route.go>",
+	Short: "Explains why a file would or wouldn't be regenerated",
+	Long: `Warms the cache like conduit generate and plans the regeneration of one file as
+if it changed. Prints the reason for each affected file, the chain of dependents
+leading from the file to it, and the stored and current hashes of the file.
+With --json the regeneration plan is printed as is. Nothing is written.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("explain called")
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		explanation, err := generator.NewRouteGenerator(wd).Explain(args[0])
+		if err != nil {
+			return err
+		}
+
+		if explainJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(explanation.Plan); err != nil {
+				return fmt.Errorf("failed to encode plan: %w", err)
+			}
+			return nil
+		}
+
+		printExplanation(explanation, models.CanonicalPath(wd))
+		return nil
+	},
+}
+
+// printExplanation prints an explanation with paths relative to root
+func printExplanation(explanation *models.RegenerationExplanation, root string) {
+	rel := func(path string) string {
+		if relPath, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(relPath, "..") {
+			return filepath.ToSlash(relPath)
+		}
+		return path
+	}
+	hash := func(hash string) string {
+		if hash == "" {
+			return "none"
+		}
+		return hash
+	}
+
+	fmt.Println(rel(explanation.File))
+	if generation := explanation.Generation; generation != nil {
+		fmt.Printf("  stored hash:  %s (generated %s to %s)\n", hash(generation.SourceHash),
+			generation.GeneratedAt.Format("2006-01-02 15:04:05"), generation.OutputPath)
+	} else {
+		fmt.Println("  stored hash:  none (no generation recorded)")
+	}
+	fmt.Printf("  current hash: %s\n", hash(explanation.CurrentHash))
+
+	plan := explanation.Plan
+	if len(plan.AffectedFiles) == 0 {
+		fmt.Println("  up to date, nothing would be regenerated")
+		return
+	}
+
+	fmt.Println("  would regenerate:")
+	for _, affected := range plan.AffectedFiles {
+		fmt.Printf("    %s: %s\n", rel(affected), plan.Reasons[affected])
+		if chain := explanation.Chains[affected]; len(chain) > 1 {
+			steps := make([]string, len(chain))
+			for i, step := range chain {
+				steps[i] = rel(step)
+			}
+			fmt.Printf("      via %s\n", strings.Join(steps, " -> "))
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	explainCmd.Flags().BoolVar(&explainJSON, "json", false, "Print the regeneration plan as JSON")
+}
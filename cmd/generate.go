@@ -6,8 +6,10 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/config"
 	"github.com/tristendillon/conduit/core/generator"
 	"github.com/tristendillon/conduit/core/logger"
 )
@@ -19,20 +21,68 @@ var generateCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.SetVerbose(verbose)
 		logger.Debug("generate called")
+		// Flags parsed fine, so failures from here on are runtime errors, not usage errors
+		cmd.SilenceUsage = true
 		wd, err := os.Getwd()
 		if err != nil {
 			return fmt.Errorf("failed to get working directory: %w", err)
 		}
 
+		// Every warning from here on counts against --strict
+		logger.CollectWarnings()
+
 		generator := generator.NewRouteGenerator(wd)
+		generator.Concurrency = concurrency
+		generator.TypeCheck = typeCheck
+		generator.EmitManifest = emitManifest
+
+		if manifestPath != "" {
+			if err := generator.GenerateRegistryFromManifest(manifestPath); err != nil {
+				return fmt.Errorf("failed to generate routes registry from manifest: %w", err)
+			}
+			return checkStrict()
+		}
+
 		if err := generator.GenerateRouteTree(logger.INFO); err != nil {
 			return fmt.Errorf("failed to generate route tree: %w", err)
 		}
 
-		return nil
+		return checkStrict()
 	},
 }
 
+// checkStrict fails with every collected warning when --strict or codegen.strict is set
+func checkStrict() error {
+	enabled := strict
+	if !enabled {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		enabled = cfg.Codegen.Strict
+	}
+
+	warnings := logger.Warnings()
+	if !enabled || len(warnings) == 0 {
+		return nil
+	}
+	return fmt.Errorf("strict mode: generation produced %d warning(s):\n  - %s", len(warnings), strings.Join(warnings, "\n  - "))
+}
+
+var (
+	concurrency  int
+	manifestPath string
+	typeCheck    bool
+	strict       bool
+	emitManifest bool
+)
+
 func init() {
 	rootCmd.AddCommand(generateCmd)
+
+	generateCmd.Flags().IntVar(&concurrency, "concurrency", 0, "Number of routes to generate in parallel (defaults to codegen.workers or the CPU count)")
+	generateCmd.Flags().StringVar(&manifestPath, "from", "", "Build the routes registry from a manifest file instead of walking the source tree")
+	generateCmd.Flags().BoolVar(&typeCheck, "typecheck", false, "Type check every route package with its real imports before generating (slower)")
+	generateCmd.Flags().BoolVar(&strict, "strict", false, "Fail when generation emits any warning (also codegen.strict)")
+	generateCmd.Flags().BoolVar(&emitManifest, "emit-manifest", false, "Skip routes unchanged since .conduit/generation_manifest.json and write it afterwards")
 }
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/cache"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+var (
+	graphFormat string
+	graphOutput string
+	graphSVG    bool
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Exports the route dependency graph",
+	Long: `Walks the project, builds the dependency graph and writes it to stdout or a file.
+Supported formats are "dot" (Graphviz), "mermaid" and "json". Edges that are part
+of a dependency cycle are highlighted in red in the DOT and Mermaid output.
+With --svg the DOT output is rendered through Graphviz's dot command.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("graph called")
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		if graphSVG && graphFormat != "dot" {
+			return fmt.Errorf("--svg requires --format dot")
+		}
+
+		generator := generator.NewRouteGenerator(wd)
+		if _, err := generator.WalkRouteTree(); err != nil {
+			return fmt.Errorf("failed to build route tree: %w", err)
+		}
+
+		var out io.Writer = os.Stdout
+		if graphOutput != "" {
+			file, err := os.Create(graphOutput)
+			if err != nil {
+				return fmt.Errorf("failed to create output file %s: %w", graphOutput, err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		graph := cache.GetCacheManager().GetDependencyGraph()
+		switch graphFormat {
+		case "dot":
+			if graphSVG {
+				err = renderSVG(graph.ExportDOT, out)
+			} else {
+				err = graph.ExportDOT(out)
+			}
+		case "mermaid":
+			err = graph.ExportMermaid(out)
+		case "json":
+			err = graph.ExportJSON(out)
+		default:
+			return fmt.Errorf("unsupported graph format: %s (expected dot, mermaid or json)", graphFormat)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export graph: %w", err)
+		}
+
+		if graphOutput != "" {
+			logger.Info("Wrote dependency graph to %s", graphOutput)
+		}
+		return nil
+	},
+}
+
+// renderSVG pipes the DOT output through `dot -Tsvg`
+func renderSVG(exportDOT func(io.Writer) error, out io.Writer) error {
+	if _, err := exec.LookPath("dot"); err != nil {
+		return fmt.Errorf("--svg needs Graphviz's dot command on PATH: %w", err)
+	}
+
+	var dot bytes.Buffer
+	if err := exportDOT(&dot); err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	render := exec.Command("dot", "-Tsvg")
+	render.Stdin = &dot
+	render.Stdout = out
+	render.Stderr = &stderr
+	if err := render.Run(); err != nil {
+		return fmt.Errorf("dot -Tsvg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+
+	graphCmd.Flags().StringVar(&graphFormat, "format", "dot", "Output format (dot, mermaid, json)")
+	graphCmd.Flags().BoolVar(&graphSVG, "svg", false, "Render the DOT output to SVG with Graphviz")
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "File to write the graph to (defaults to stdout)")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/models"
+)
+
+var graphWhyJSON bool
+
+var graphWhyCmd = &cobra.Command{
+	Use:   "why <from> <to>",
+	Short: "Explains why one route or package depends on another",
+	Long: `Prints the shortest chain of imports through which <from> depends on <to>, one hop
+per line with the import that creates it. Each may be a route folder, a file or a
+package directory relative to the project root, or an import path, for example:
+
+  conduit graph why api/v1/orders internal/billing
+
+Local packages are followed transitively, as they are copied into the generated tree.
+Exits with code 1 when <from> doesn't depend on <to>.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("graph why called")
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		hops, err := generator.NewRouteGenerator(wd).WhyDepends(args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		if graphWhyJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(hops); err != nil {
+				return fmt.Errorf("failed to encode path: %w", err)
+			}
+		} else {
+			for _, hop := range hops {
+				fmt.Printf("%s -> %s\n    %s\n", hop.From, hop.To, importLine(hop.Import))
+			}
+		}
+
+		if len(hops) == 0 {
+			cmd.SilenceUsage = true
+			return &exitError{code: 1, err: fmt.Errorf("%s does not depend on %s", args[0], args[1])}
+		}
+		return nil
+	},
+}
+
+// importLine returns the import declaration that creates a dependency, with its alias
+func importLine(dep models.LocalDependency) string {
+	if dep.Alias != "" {
+		return fmt.Sprintf("import %s %s", dep.Alias, strconv.Quote(dep.ImportPath))
+	}
+	return fmt.Sprintf("import %s", strconv.Quote(dep.ImportPath))
+}
+
+func init() {
+	graphCmd.AddCommand(graphWhyCmd)
+
+	graphWhyCmd.Flags().BoolVar(&graphWhyJSON, "json", false, "Print the chain as JSON")
+}
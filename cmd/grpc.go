@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+var (
+	grpcOutput  string
+	grpcPackage string
+)
+
+var grpcCmd = &cobra.Command{
+	Use:   "grpc",
+	Short: "Generates a .proto service definition from the route tree",
+	Long: `Walks the project and writes a .proto file with a service that has one rpc per route
+handler. GET api/users/:id becomes
+
+  rpc GetApiUsersById(GetApiUsersByIdRequest) returns (GetApiUsersByIdResponse);
+
+with the path parameters as string fields of the request message.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("grpc called")
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		opts := generator.ProtoOptions{Output: grpcOutput, Package: grpcPackage}
+		if _, err := generator.NewRouteGenerator(wd).GenerateProto(opts); err != nil {
+			return fmt.Errorf("failed to generate proto file: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(grpcCmd)
+
+	grpcCmd.Flags().StringVar(&grpcOutput, "output", "", "Path of the .proto file (defaults to <package>.proto)")
+	grpcCmd.Flags().StringVar(&grpcPackage, "package", "", "Proto package name (defaults to the app name)")
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+var (
+	listJSON   bool
+	listMethod string
+)
+
+// listRow is one method of one route as printed by `conduit list`
+type listRow struct {
+	Method     string   `json:"method"`
+	APIPath    string   `json:"api_path"`
+	Parameters []string `json:"parameters"`
+	SourceFile string   `json:"source_file"`
+	OutputFile string   `json:"output_file"`
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Prints a table of every route method with its source and generated file",
+	Long: `Walks the project and prints one row per route method with its API path, path
+parameters, the route.go it comes from and the file conduit generates for it,
+sorted by API path. No files are generated.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("list called")
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		rg := generator.NewRouteGenerator(wd)
+		tree, err := rg.WalkRouteTree()
+		if err != nil {
+			return fmt.Errorf("failed to build route tree: %w", err)
+		}
+		if err := tree.CalculateOutputPaths(cfg, rg.ModuleName()); err != nil {
+			return fmt.Errorf("failed to calculate output paths: %w", err)
+		}
+
+		method := strings.ToUpper(listMethod)
+		rows := []listRow{}
+		for _, route := range tree.Routes {
+			params := route.Parameters
+			if params == nil {
+				params = []string{}
+			}
+			for _, m := range route.Methods {
+				if method != "" && m != method {
+					continue
+				}
+				rows = append(rows, listRow{
+					Method:     m,
+					APIPath:    route.APIPath,
+					Parameters: params,
+					SourceFile: filepath.Join(route.FolderPath, "route.go"),
+					OutputFile: route.OutputPath,
+				})
+			}
+		}
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].APIPath < rows[j].APIPath
+		})
+
+		if listJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(rows); err != nil {
+				return fmt.Errorf("failed to encode routes: %w", err)
+			}
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "METHOD\tAPI PATH\tPARAMETERS\tSOURCE FILE\tOUTPUT FILE")
+		for _, row := range rows {
+			params := strings.Join(row.Parameters, ",")
+			if params == "" {
+				params = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", row.Method, row.APIPath, params, row.SourceFile, row.OutputFile)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Print the routes as a JSON array")
+	listCmd.Flags().StringVar(&listMethod, "method", "", "Only show routes that handle this HTTP method")
+}
@@ -4,9 +4,12 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/logger"
 )
 
 var rootCmd = &cobra.Command{
@@ -15,19 +18,60 @@ var rootCmd = &cobra.Command{
 	Long: `Conduit is the go tool for connecting your go APIs with your frontend.
 Utilizing Codegen to create solid RPC for your frontend and other services.
 The REST version of gRPC.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetJSONMode(logJSON)
+		level, err := logger.ParseLevel(logLevel)
+		if err != nil {
+			return err
+		}
+		logger.SetLevel(level)
+		return openLogfile()
+	},
 }
 
 var logfile string
 var verbose bool
+var logJSON bool
+var logLevel string
+
+// openLogfile adds the --logfile file as a plain-text writer for every log level
+func openLogfile() error {
+	if logfile == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(logfile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	logger.AddWriterForAll(logger.NewPlainWriter(file))
+	return nil
+}
+
+// exitError makes Execute exit with a specific code instead of 1
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
 
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&logfile, "logfile", "", "File to write logs to")
-	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Verbose output")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Verbose output (same as --log-level debug)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Minimum level to log: debug, info, warn, error or fatal")
+	rootCmd.PersistentFlags().BoolVar(&logJSON, "log-json", false, "Write logs as JSON lines")
 }
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+var routesJSON bool
+
+// routeListing is the serializable subset of a route printed by `conduit routes --json`
+type routeListing struct {
+	APIPath    string   `json:"api_path"`
+	Pattern    string   `json:"pattern"`
+	Methods    []string `json:"methods"`
+	Parameters []string `json:"parameters"`
+	FolderPath string   `json:"folder_path"`
+}
+
+var routesCmd = &cobra.Command{
+	Use:   "routes",
+	Short: "Lists the routes discovered in the project",
+	Long: `Walks the project and prints every discovered route with its methods and source folder,
+sorted by API path. No files are generated.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("routes called")
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		tree, err := generator.NewRouteGenerator(wd).WalkRouteTree()
+		if err != nil {
+			return fmt.Errorf("failed to build route tree: %w", err)
+		}
+
+		listings := make([]routeListing, 0, len(tree.Routes))
+		for _, route := range tree.Routes {
+			listing := routeListing{
+				APIPath:    route.APIPath,
+				Pattern:    route.Pattern,
+				Methods:    route.Methods,
+				Parameters: route.Parameters,
+				FolderPath: route.FolderPath,
+			}
+			if listing.Methods == nil {
+				listing.Methods = []string{}
+			}
+			if listing.Parameters == nil {
+				listing.Parameters = []string{}
+			}
+			listings = append(listings, listing)
+		}
+		sort.Slice(listings, func(i, j int) bool {
+			return listings[i].APIPath < listings[j].APIPath
+		})
+
+		if routesJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(listings); err != nil {
+				return fmt.Errorf("failed to encode routes: %w", err)
+			}
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PATH\tMETHODS\tFOLDER")
+		for _, listing := range listings {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", listing.APIPath, strings.Join(listing.Methods, ","), listing.FolderPath)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(routesCmd)
+
+	routesCmd.Flags().BoolVar(&routesJSON, "json", false, "Print the routes as JSON")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/server"
+)
+
+var serveAddr string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Runs the generated HTTP server",
+	Long: `Runs go run on the main.go in codegen.go.output/server, forwarding stdin, stdout and
+stderr, until the server exits or conduit is interrupted. The main.go is generated in
+single-file mode (codegen.go.mode: single-file). With conduit dev --serve the server is
+restarted after every regeneration instead.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("serve called")
+
+		process, err := newServerProcess(serveAddr)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := process.Start(ctx); err != nil {
+			return err
+		}
+		if err := process.Wait(); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("server exited: %w", err)
+		}
+		return nil
+	},
+}
+
+// newServerProcess returns the process running the generated server, listening on addr
+// when it isn't empty
+func newServerProcess(addr string) (*server.Process, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	if cfg.Codegen.Go.Mode != config.GoModeSingleFile {
+		logger.Warn("conduit serve runs the main.go generated in %s mode, but codegen.go.mode is %q", config.GoModeSingleFile, cfg.Codegen.Go.Mode)
+	}
+
+	var env []string
+	if addr != "" {
+		env = append(env, generator.ServerAddrEnv+"="+addr)
+	}
+	return server.NewProcess(generator.SingleFileServerPath(cfg), env...), nil
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "", "Address for the server to listen on, overriding server.host and server.port")
+}
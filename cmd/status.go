@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+var statusJSON bool
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Summarizes the config, routes, cache and generated output of the project",
+	Long: `Prints the config file in use with its key values, the output directories, the
+number of routes, the cache statistics after walking them and the time of the last
+generation, followed by any dependency cycles, route conflicts and orphaned generated
+files. Nothing is generated.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("status called")
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		report, err := generator.NewRouteGenerator(wd).Status()
+		if err != nil {
+			return fmt.Errorf("failed to get project status: %w", err)
+		}
+
+		if statusJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				return fmt.Errorf("failed to encode results: %w", err)
+			}
+			return nil
+		}
+
+		printStatus(report)
+		return nil
+	},
+}
+
+func printStatus(report *generator.StatusReport) {
+	configFile := report.ConfigFile
+	if configFile == "" {
+		configFile = "none (using defaults)"
+	}
+	lastGenerated := "never"
+	if report.LastGeneratedAt != nil {
+		lastGenerated = report.LastGeneratedAt.Local().Format(time.RFC3339)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Config:\t%s\n", configFile)
+	fmt.Fprintf(w, "App name:\t%s\n", report.AppName)
+	fmt.Fprintf(w, "Address:\t%s\n", report.Address)
+	fmt.Fprintf(w, "Mode:\t%s\n", report.Mode)
+	fmt.Fprintf(w, "Router:\t%s\n", report.Router)
+	fmt.Fprintf(w, "Go output:\t%s\n", strings.Join(report.GoOutputs, ", "))
+	fmt.Fprintf(w, "TypeScript output:\t%s\n", report.TypescriptOutput)
+	fmt.Fprintf(w, "Routes:\t%d\n", report.Routes)
+	fmt.Fprintf(w, "Last generated:\t%s\n", lastGenerated)
+	w.Flush()
+
+	layers := make([]string, 0, len(report.CacheStats))
+	for layer := range report.CacheStats {
+		layers = append(layers, layer)
+	}
+	sort.Strings(layers)
+	fmt.Println("\nCache:")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  LAYER\tFILES\tHITS\tMISSES\tHIT RATE")
+	for _, layer := range layers {
+		stats := report.CacheStats[layer]
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%d\t%.1f%%\n", layer, stats.TotalFiles, stats.CacheHits, stats.CacheMisses, stats.HitRate)
+	}
+	w.Flush()
+
+	if report.Healthy() {
+		fmt.Println("\nNo issues found")
+		return
+	}
+	fmt.Println("\nIssues:")
+	for _, cycle := range report.Cycles {
+		fmt.Printf("  cycle: %s\n", strings.Join(cycle, " -> "))
+	}
+	for _, conflict := range report.Conflicts {
+		fmt.Printf("  conflict: %s\n", conflict)
+	}
+	for _, orphan := range report.OrphanedOutputs {
+		fmt.Printf("  orphaned output: %s\n", orphan)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Print the status as JSON")
+}
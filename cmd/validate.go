@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/validator"
+)
+
+var validateJSON bool
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Checks the project for routing errors",
+	Long: `Walks the project and reports route files with syntax errors, conflicting routes,
+local dependencies that don't exist on disk, handlers whose signature isn't
+func(http.ResponseWriter, *http.Request) and @middleware annotations naming middleware
+that can't be found. Exits with a non-zero code when any issue is found.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.SetVerbose(verbose)
+		logger.Debug("validate called")
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		issues, err := validator.Validate(wd)
+		if err != nil {
+			return fmt.Errorf("failed to validate project: %w", err)
+		}
+
+		if validateJSON {
+			result := struct {
+				Valid  bool              `json:"valid"`
+				Issues []validator.Issue `json:"issues"`
+			}{
+				Valid:  len(issues) == 0,
+				Issues: issues,
+			}
+			if result.Issues == nil {
+				result.Issues = []validator.Issue{}
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(result); err != nil {
+				return fmt.Errorf("failed to encode results: %w", err)
+			}
+		} else if len(issues) == 0 {
+			logger.Info("No issues found")
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "CHECK\tFILE\tMESSAGE")
+			for _, issue := range issues {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", issue.Check, issue.File, issue.Message)
+			}
+			w.Flush()
+		}
+
+		if len(issues) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("validation failed with %d issue(s)", len(issues))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().BoolVar(&validateJSON, "json", false, "Print the results as JSON")
+}
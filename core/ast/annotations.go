@@ -0,0 +1,50 @@
+package ast
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+// conduitAnnotation marks a doc comment line holding key=value metadata for a handler
+const conduitAnnotation = "@conduit"
+
+// extractAnnotations returns the key=value pairs from `// @conduit key=value` lines in a
+// handler's doc comment. A line may hold several pairs separated by spaces; a later pair
+// overrides an earlier one with the same key. The map is empty, not nil, when there are none.
+func extractAnnotations(name string, doc *ast.CommentGroup) map[string]string {
+	annotations := make(map[string]string)
+	if doc == nil {
+		return annotations
+	}
+
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		rest, found := strings.CutPrefix(strings.TrimSpace(line), conduitAnnotation)
+		if !found || rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue
+		}
+		for _, pair := range strings.Fields(rest) {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok || key == "" {
+				logger.Debug("Ignoring malformed @conduit annotation %q on %s, expected key=value", pair, name)
+				continue
+			}
+			annotations[key] = value
+		}
+	}
+	return annotations
+}
+
+// extractDescription returns a handler's doc comment without its @conduit and @middleware
+// annotation lines, for the descriptions of generated code
+func extractDescription(doc *ast.CommentGroup) string {
+	var lines []string
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		if first, _, _ := strings.Cut(strings.TrimSpace(line), " "); first == conduitAnnotation || first == middlewareAnnotation {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
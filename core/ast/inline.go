@@ -0,0 +1,127 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// InlinedRoute is a route file rewritten to share a package with other route files
+type InlinedRoute struct {
+	// Source is the whole rewritten file, for parsing its handlers
+	Source []byte
+	// Declarations are the rewritten top-level declarations other than imports and handlers
+	Declarations string
+}
+
+// noImporter fails every import, leaving the identifiers they declare unresolved; only the
+// objects declared in the file itself are needed
+type noImporter struct{}
+
+func (noImporter) Import(path string) (*types.Package, error) {
+	return nil, fmt.Errorf("imports are not resolved")
+}
+
+// InlineRouteSource rewrites a route file so its declarations can live in one package with
+// other route files: every package-level identifier it declares is prefixed with prefix,
+// except handlers and init functions, and references to a handler use handlerName(method).
+func InlineRouteSource(path string, src []byte, prefix string, handlerName func(method string) string) (*InlinedRoute, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// Type errors are expected since imports aren't resolved, so they're ignored
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	checker := &types.Config{Importer: noImporter{}, Error: func(error) {}}
+	pkg, _ := checker.Check(f.Name.Name, fset, []*ast.File{f}, info)
+
+	handlers := make(map[types.Object]string)
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			if method, isHandler := handlerMethod(fn); isHandler {
+				handlers[info.Defs[fn.Name]] = method
+			}
+		}
+	}
+
+	rename := func(ident *ast.Ident, obj types.Object, declaration bool) {
+		if obj == nil || obj.Parent() != pkg.Scope() || ident.Name == "_" {
+			return
+		}
+		if method, isHandler := handlers[obj]; isHandler {
+			if !declaration {
+				ident.Name = handlerName(method)
+			}
+			return
+		}
+		if _, isFunc := obj.(*types.Func); isFunc && obj.Name() == "init" {
+			return
+		}
+		ident.Name = prefix + ident.Name
+	}
+	for ident, obj := range info.Defs {
+		rename(ident, obj, true)
+	}
+	for ident, obj := range info.Uses {
+		rename(ident, obj, false)
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return nil, fmt.Errorf("failed to print %s: %w", path, err)
+	}
+	rewritten := buf.Bytes()
+
+	// The declarations are cut from the printed file so their comments come along
+	fset = token.NewFileSet()
+	f, err = parser.ParseFile(fset, path, rewritten, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rewritten %s: %w", path, err)
+	}
+	var declarations []string
+	for _, decl := range f.Decls {
+		start := decl.Pos()
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok == token.IMPORT {
+				continue
+			}
+			if decl.Doc != nil {
+				start = decl.Doc.Pos()
+			}
+		case *ast.FuncDecl:
+			if _, isHandler := handlerMethod(decl); isHandler {
+				continue
+			}
+			if decl.Doc != nil {
+				start = decl.Doc.Pos()
+			}
+		}
+		declarations = append(declarations, string(rewritten[fset.Position(start).Offset:fset.Position(decl.End()).Offset]))
+	}
+
+	return &InlinedRoute{
+		Source:       rewritten,
+		Declarations: strings.Join(declarations, "\n\n"),
+	}, nil
+}
+
+// handlerMethod returns the HTTP method a function handles when it's a route handler
+func handlerMethod(fn *ast.FuncDecl) (string, bool) {
+	if fn.Recv != nil {
+		return "", false
+	}
+	method := strings.ToUpper(fn.Name.Name)
+	return method, slices.Contains(httpMethodNames, method)
+}
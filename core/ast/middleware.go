@@ -0,0 +1,275 @@
+package ast
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/models"
+)
+
+// MiddlewareFileName is the file in a route folder whose middleware wraps the routes in
+// that folder and below it
+const MiddlewareFileName = "_middleware.go"
+
+// middlewareAnnotation marks a doc comment line naming middleware to wrap a handler with
+const middlewareAnnotation = "@middleware"
+
+// extractMiddlewares returns the middleware named by `// @middleware <name>` lines in a
+// handler's doc comment, in the order they appear. A line may name several, separated by
+// spaces or commas; the first is the outermost.
+func extractMiddlewares(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+
+	var middlewares []string
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		rest, found := strings.CutPrefix(strings.TrimSpace(line), middlewareAnnotation)
+		if !found || rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue
+		}
+		for _, name := range strings.FieldsFunc(rest, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		}) {
+			middlewares = append(middlewares, name)
+		}
+	}
+	return middlewares
+}
+
+// ParseMiddlewareSource parses a _middleware.go file from src. Its exported functions
+// shaped func(http.Handler) http.Handler are returned as Functions in source order; other
+// exported functions are reported and skipped.
+func ParseMiddlewareSource(path, relPath, moduleName string, src []byte) (*models.ParsedFile, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	httpPkg := ""
+	for _, imp := range f.Imports {
+		if strings.Trim(imp.Path.Value, "\"") != "net/http" {
+			continue
+		}
+		httpPkg = "http"
+		if imp.Name != nil {
+			httpPkg = imp.Name.Name
+		}
+	}
+
+	functions := []models.ExtractedFunction{}
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !fn.Name.IsExported() {
+			continue
+		}
+		if !isMiddlewareSignature(fn.Type, httpPkg) {
+			logger.Warn("%s: %s is not middleware, expected func(http.Handler) http.Handler",
+				filepath.Join(relPath, MiddlewareFileName), fn.Name.Name)
+			continue
+		}
+
+		body, err := extractFunctionBody(fset, fn, src)
+		if err != nil {
+			logger.Debug("Failed to extract body for %s: %v", fn.Name.Name, err)
+			continue
+		}
+		functions = append(functions, models.ExtractedFunction{
+			Name:       fn.Name.Name,
+			Signature:  extractFunctionSignature(fset, fn, src),
+			Body:       body,
+			DocComment: strings.TrimSpace(fn.Doc.Text()),
+		})
+	}
+
+	dependencies, err := AnalyzeDependencies(fset, f, moduleName)
+	if err != nil {
+		logger.Debug("Failed to analyze dependencies for %s: %v", path, err)
+		dependencies = &models.DependencyAnalysis{}
+	}
+
+	return &models.ParsedFile{
+		Path:         path,
+		RelPath:      relPath,
+		PackageName:  f.Name.Name,
+		Methods:      []string{},
+		Functions:    functions,
+		Imports:      extractImportsFromFile(f),
+		Dependencies: dependencies,
+	}, nil
+}
+
+// isMiddlewareSignature reports whether fnType is func(http.Handler) http.Handler
+func isMiddlewareSignature(fnType *ast.FuncType, httpPkg string) bool {
+	if httpPkg == "" || fnType.TypeParams != nil || fnType.Results == nil {
+		return false
+	}
+	isHandler := func(expr ast.Expr) bool {
+		sel, ok := expr.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		return ok && pkg.Name == httpPkg && sel.Sel.Name == "Handler"
+	}
+
+	params, results := fnType.Params.List, fnType.Results.List
+	return len(params) == 1 && len(params[0].Names) <= 1 && isHandler(params[0].Type) &&
+		len(results) == 1 && len(results[0].Names) <= 1 && isHandler(results[0].Type)
+}
+
+// CheckMiddlewares reports @middleware annotations in a route file that can't be resolved.
+// Each must be a package-qualified identifier of a package the route file imports, since
+// only imports are carried into the generated code. Packages inside the project (rooted
+// at wd) must also declare the identifier at the top level.
+func CheckMiddlewares(wd string, parsed *models.ParsedFile) ([]string, error) {
+	var annotated []models.ExtractedFunction
+	for _, fn := range parsed.Functions {
+		if len(fn.Middlewares) > 0 {
+			annotated = append(annotated, fn)
+		}
+	}
+	if len(annotated) == 0 {
+		return nil, nil
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, parsed.Path, nil, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	localDirs := make(map[string]string)
+	if parsed.Dependencies != nil {
+		for _, dep := range parsed.Dependencies.LocalImports {
+			localDirs[dep.ImportPath] = filepath.Join(wd, dep.RelativePath)
+		}
+	}
+
+	// Map each import's name in the route file to the directory of local packages, or ""
+	imports := make(map[string]string)
+	for _, imp := range f.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		dir := localDirs[importPath]
+
+		name := ""
+		switch {
+		case imp.Name != nil:
+			name = imp.Name.Name
+		case dir != "":
+			name = packageNameInDir(dir)
+		}
+		if name == "" {
+			name = guessPackageName(importPath)
+		}
+		imports[name] = dir
+	}
+
+	var problems []string
+	for _, fn := range annotated {
+		for _, middleware := range fn.Middlewares {
+			if problem := resolveMiddleware(middleware, imports); problem != "" {
+				problems = append(problems, fmt.Sprintf("%s: middleware %s %s", fn.Name, middleware, problem))
+			}
+		}
+	}
+	return problems, nil
+}
+
+// resolveMiddleware returns why middleware can't be resolved, or "" if it can
+func resolveMiddleware(middleware string, imports map[string]string) string {
+	pkg, name, qualified := strings.Cut(middleware, ".")
+	if !qualified || !token.IsIdentifier(pkg) || !token.IsIdentifier(name) {
+		return "must be a package-qualified identifier such as auth.RequireUser"
+	}
+	dir, imported := imports[pkg]
+	if !imported {
+		return fmt.Sprintf("refers to package %s, which the route file doesn't import", pkg)
+	}
+	if !token.IsExported(name) {
+		return "is not exported"
+	}
+	if dir != "" && !declaresTopLevel(dir, name) {
+		return fmt.Sprintf("is not declared in package %s", pkg)
+	}
+	return ""
+}
+
+// packageNameInDir returns the package clause of the first Go file in dir, or ""
+func packageNameInDir(dir string) string {
+	for _, file := range goFilesInDir(dir) {
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly)
+		if err == nil {
+			return f.Name.Name
+		}
+	}
+	return ""
+}
+
+// guessPackageName returns the conventional package name for an import path: its last
+// element, skipping a major version suffix
+func guessPackageName(importPath string) string {
+	name := path.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = path.Base(path.Dir(importPath))
+	}
+	return strings.TrimPrefix(name, "go-")
+}
+
+// declaresTopLevel reports whether a package in dir declares a function or variable name
+func declaresTopLevel(dir, name string) bool {
+	for _, file := range goFilesInDir(dir) {
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil && decl.Name.Name == name {
+					return true
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					for _, ident := range spec.(*ast.ValueSpec).Names {
+						if ident.Name == name {
+							return true
+						}
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// goFilesInDir lists the non-test Go files directly in dir
+func goFilesInDir(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	return files
+}
@@ -75,11 +75,14 @@ func extractImportsFromFile(f *ast.File) []string {
 	return imports
 }
 
-func AnalyzeDependencies(f *ast.File, moduleName string) (*models.DependencyAnalysis, error) {
+// AnalyzeDependencies sorts the imports of a file into standard library, external and local
+// ones, recording the alias and line each local and external import is declared with
+func AnalyzeDependencies(fset *token.FileSet, f *ast.File, moduleName string) (*models.DependencyAnalysis, error) {
 	analysis := &models.DependencyAnalysis{
 		StandardLibImports: []string{},
 		ExternalImports:    []string{},
 		LocalImports:       []models.LocalDependency{},
+		ExternalDecls:      map[string]models.ImportDecl{},
 	}
 
 	for _, imp := range f.Imports {
@@ -90,6 +93,12 @@ func AnalyzeDependencies(f *ast.File, moduleName string) (*models.DependencyAnal
 			continue
 		}
 
+		alias := ""
+		if imp.Name != nil {
+			alias = imp.Name.Name
+		}
+		line := fset.Position(imp.Pos()).Line
+
 		if isStandardLibrary(importPath) {
 			analysis.StandardLibImports = append(analysis.StandardLibImports, importPath)
 		} else if strings.HasPrefix(importPath, moduleName+"/") {
@@ -97,15 +106,14 @@ func AnalyzeDependencies(f *ast.File, moduleName string) (*models.DependencyAnal
 			localDep := models.LocalDependency{
 				ImportPath:    importPath,
 				RelativePath:  strings.TrimPrefix(importPath, moduleName+"/"),
-				Alias:         "",
-			}
-			if imp.Name != nil {
-				localDep.Alias = imp.Name.Name
+				Alias:         alias,
+				Line:          line,
 			}
 			analysis.LocalImports = append(analysis.LocalImports, localDep)
 		} else {
 			// External dependency (third-party)
 			analysis.ExternalImports = append(analysis.ExternalImports, importPath)
+			analysis.ExternalDecls[importPath] = models.ImportDecl{Alias: alias, Line: line}
 		}
 	}
 
@@ -159,12 +167,18 @@ func extractFunctionSignature(fset *token.FileSet, fn *ast.FuncDecl, src []byte)
 }
 
 func ParseRouteWithFunctions(path, relPath, moduleName string) (*models.ParsedFile, error) {
-	fset := token.NewFileSet()
-
 	src, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	return ParseRouteSource(path, relPath, moduleName, src)
+}
+
+// ParseRouteSource is ParseRouteWithFunctions for a route whose source is already in
+// memory, such as a route conduit generates itself. path is only used for positions.
+func ParseRouteSource(path, relPath, moduleName string, src []byte) (*models.ParsedFile, error) {
+	fset := token.NewFileSet()
+
 	srcStr := strings.TrimSpace(string(src))
 	if srcStr == "" {
 		logger.Debug("Empty route file %s, skipping parsing", relPath)
@@ -233,11 +247,19 @@ func ParseRouteWithFunctions(path, relPath, moduleName string) (*models.ParsedFi
 				continue
 			}
 
+			requestType, responseType := extractPayloadTypes(f, fn)
+
 			functions = append(functions, models.ExtractedFunction{
-				Name:      name,
-				Method:    upper,
-				Signature: signature,
-				Body:      body,
+				Name:         name,
+				Method:       upper,
+				Signature:    signature,
+				Body:         body,
+				RequestType:  requestType,
+				ResponseType: responseType,
+				DocComment:   strings.TrimSpace(fn.Doc.Text()),
+				Description:  extractDescription(fn.Doc),
+				Middlewares:  extractMiddlewares(fn.Doc),
+				Annotations:  extractAnnotations(name, fn.Doc),
 			})
 		}
 	}
@@ -248,7 +270,7 @@ func ParseRouteWithFunctions(path, relPath, moduleName string) (*models.ParsedFi
 	}
 
 	// Perform dependency analysis
-	dependencies, err := AnalyzeDependencies(f, moduleName)
+	dependencies, err := AnalyzeDependencies(fset, f, moduleName)
 	if err != nil {
 		logger.Debug("Failed to analyze dependencies for %s: %v", relPath, err)
 		dependencies = &models.DependencyAnalysis{}
@@ -257,11 +279,13 @@ func ParseRouteWithFunctions(path, relPath, moduleName string) (*models.ParsedFi
 	parsed := &models.ParsedFile{
 		Path:         path,
 		PackageName:  packageName,
+		DocComment:   strings.TrimSpace(f.Doc.Text()),
 		Methods:      methods,
 		RelPath:      relPath,
 		Functions:    functions,
 		Imports:      imports,
 		Dependencies: dependencies,
+		Structs:      extractStructs(f),
 	}
 
 	return parsed, nil
@@ -278,20 +302,22 @@ func ParseRoute(path, relPath string) (*models.ParsedFile, error) {
 	if srcStr == "" {
 		logger.Debug("Empty route file %s, skipping parsing", relPath)
 		return &models.ParsedFile{
-			Path:        path,
-			PackageName: "",
-			Methods:     []string{},
-			RelPath:     relPath,
+			Path:         path,
+			PackageName:  "",
+			Methods:      []string{},
+			RelPath:      relPath,
+			Dependencies: &models.DependencyAnalysis{},
 		}, nil
 	}
 
 	if !strings.Contains(srcStr, "package ") {
 		logger.Debug("Route file %s missing package declaration, skipping parsing", relPath)
 		return &models.ParsedFile{
-			Path:        path,
-			PackageName: "",
-			Methods:     []string{},
-			RelPath:     relPath,
+			Path:         path,
+			PackageName:  "",
+			Methods:      []string{},
+			RelPath:      relPath,
+			Dependencies: &models.DependencyAnalysis{},
 		}, nil
 	}
 
@@ -299,10 +325,11 @@ func ParseRoute(path, relPath string) (*models.ParsedFile, error) {
 	if err != nil {
 		logger.Debug("Failed to parse route file %s: %v - treating as empty", relPath, err)
 		return &models.ParsedFile{
-			Path:        path,
-			PackageName: "",
-			Methods:     []string{},
-			RelPath:     relPath,
+			Path:         path,
+			PackageName:  "",
+			Methods:      []string{},
+			RelPath:      relPath,
+			Dependencies: &models.DependencyAnalysis{},
 		}, nil
 	}
 
@@ -331,11 +358,13 @@ func ParseRoute(path, relPath string) (*models.ParsedFile, error) {
 		packageName = f.Name.Name
 	}
 
+	// Without the module name imports can't be told apart, but callers expect the analysis
 	parsed := &models.ParsedFile{
-		Path:        path,
-		PackageName: packageName,
-		Methods:     methods,
-		RelPath:     relPath,
+		Path:         path,
+		PackageName:  packageName,
+		Methods:      methods,
+		RelPath:      relPath,
+		Dependencies: &models.DependencyAnalysis{},
 	}
 
 	return parsed, nil
@@ -0,0 +1,215 @@
+package ast
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// jsonPackageName returns the name encoding/json is imported under, or "" if it isn't imported
+func jsonPackageName(f *ast.File) string {
+	for _, imp := range f.Imports {
+		if strings.Trim(imp.Path.Value, "\"") != "encoding/json" {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return "json"
+	}
+	return ""
+}
+
+// extractPayloadTypes infers the request and response types of a handler from the
+// values it decodes from and encodes to JSON. Types that can't be inferred are left empty.
+func extractPayloadTypes(f *ast.File, fn *ast.FuncDecl) (string, string) {
+	jsonPkg := jsonPackageName(f)
+	if jsonPkg == "" || fn.Body == nil {
+		return "", ""
+	}
+
+	localTypes := collectPackageTypes(f)
+	for name, typ := range collectLocalTypes(fn.Body) {
+		localTypes[name] = typ
+	}
+	decoders := make(map[string]bool)
+	encoders := make(map[string]bool)
+	var requestType, responseType string
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			// Track variables holding json decoders/encoders: dec := json.NewDecoder(r.Body)
+			for i, rhs := range node.Rhs {
+				if i >= len(node.Lhs) {
+					break
+				}
+				ident, ok := node.Lhs[i].(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if isPackageCall(rhs, jsonPkg, "NewDecoder") {
+					decoders[ident.Name] = true
+				} else if isPackageCall(rhs, jsonPkg, "NewEncoder") {
+					encoders[ident.Name] = true
+				}
+			}
+
+		case *ast.CallExpr:
+			switch {
+			case requestType == "" && isPackageCall(node, jsonPkg, "Unmarshal") && len(node.Args) == 2:
+				requestType = resolveValueType(node.Args[1], localTypes)
+			case requestType == "" && isMethodCall(node, "Decode", jsonPkg, "NewDecoder", decoders) && len(node.Args) == 1:
+				requestType = resolveValueType(node.Args[0], localTypes)
+			case responseType == "" && (isPackageCall(node, jsonPkg, "Marshal") || isPackageCall(node, jsonPkg, "MarshalIndent")) && len(node.Args) > 0:
+				responseType = resolveValueType(node.Args[0], localTypes)
+			case responseType == "" && isMethodCall(node, "Encode", jsonPkg, "NewEncoder", encoders) && len(node.Args) == 1:
+				responseType = resolveValueType(node.Args[0], localTypes)
+			}
+		}
+		return true
+	})
+
+	return requestType, responseType
+}
+
+// collectPackageTypes maps package-level variables to their type expressions, so
+// handlers decoding into or encoding shared values still resolve a type
+func collectPackageTypes(f *ast.File) map[string]ast.Expr {
+	packageTypes := make(map[string]ast.Expr)
+	for _, decl := range f.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok {
+			collectVarTypes(genDecl, packageTypes)
+		}
+	}
+	return packageTypes
+}
+
+// collectVarTypes records the types of variables declared by a var declaration
+func collectVarTypes(decl *ast.GenDecl, varTypes map[string]ast.Expr) {
+	if decl.Tok != token.VAR {
+		return
+	}
+	for _, spec := range decl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for i, name := range valueSpec.Names {
+			if valueSpec.Type != nil {
+				varTypes[name.Name] = valueSpec.Type
+			} else if i < len(valueSpec.Values) {
+				if typ := typeOfValue(valueSpec.Values[i]); typ != nil {
+					varTypes[name.Name] = typ
+				}
+			}
+		}
+	}
+}
+
+// collectLocalTypes maps variables declared in a function body to their type expressions
+func collectLocalTypes(body *ast.BlockStmt) map[string]ast.Expr {
+	localTypes := make(map[string]ast.Expr)
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GenDecl:
+			collectVarTypes(node, localTypes)
+
+		case *ast.AssignStmt:
+			if node.Tok != token.DEFINE || len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if typ := typeOfValue(node.Rhs[i]); typ != nil {
+					localTypes[ident.Name] = typ
+				}
+			}
+		}
+		return true
+	})
+
+	return localTypes
+}
+
+// typeOfValue returns the type expression of values like T{}, &T{} and new(T)
+func typeOfValue(expr ast.Expr) ast.Expr {
+	switch value := expr.(type) {
+	case *ast.CompositeLit:
+		return value.Type
+	case *ast.UnaryExpr:
+		if value.Op == token.AND {
+			if lit, ok := value.X.(*ast.CompositeLit); ok && lit.Type != nil {
+				return &ast.StarExpr{X: lit.Type}
+			}
+		}
+	case *ast.CallExpr:
+		if ident, ok := value.Fun.(*ast.Ident); ok && ident.Name == "new" && len(value.Args) == 1 {
+			return &ast.StarExpr{X: value.Args[0]}
+		}
+	}
+	return nil
+}
+
+// resolveValueType returns the type name of a decode target or encode source.
+// Pointer and value targets resolve to the same name.
+func resolveValueType(expr ast.Expr, localTypes map[string]ast.Expr) string {
+	var typ ast.Expr
+
+	switch value := expr.(type) {
+	case *ast.ParenExpr:
+		return resolveValueType(value.X, localTypes)
+	case *ast.StarExpr:
+		return resolveValueType(value.X, localTypes)
+	case *ast.Ident:
+		typ = localTypes[value.Name]
+	case *ast.UnaryExpr:
+		if value.Op != token.AND {
+			return ""
+		}
+		if ident, ok := value.X.(*ast.Ident); ok {
+			typ = localTypes[ident.Name]
+		} else {
+			typ = typeOfValue(value.X)
+		}
+	default:
+		typ = typeOfValue(expr)
+	}
+
+	if typ == nil {
+		return ""
+	}
+	return strings.TrimPrefix(types.ExprString(typ), "*")
+}
+
+// isPackageCall reports whether expr is a call of pkg.name(...)
+func isPackageCall(expr ast.Expr, pkg, name string) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == pkg
+}
+
+// isMethodCall reports whether call is recv.method(...) where recv is either a
+// direct pkg.constructor(...) call or a variable known to hold its result
+func isMethodCall(call *ast.CallExpr, method, pkg, constructor string, vars map[string]bool) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != method {
+		return false
+	}
+	if ident, ok := sel.X.(*ast.Ident); ok {
+		return vars[ident.Name]
+	}
+	return isPackageCall(sel.X, pkg, constructor)
+}
@@ -0,0 +1,239 @@
+package ast
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tristendillon/conduit/core/models"
+)
+
+// structResolver resolves field types against the type declarations of a file
+type structResolver struct {
+	specs map[string]*ast.TypeSpec
+	// visiting holds the unexported types being inlined, so recursive ones terminate
+	visiting map[string]bool
+}
+
+// extractStructs returns the exported, non-generic struct types declared in a file with
+// their fields as encoding/json sees them
+func extractStructs(f *ast.File) []models.StructDecl {
+	resolver := &structResolver{
+		specs:    make(map[string]*ast.TypeSpec),
+		visiting: make(map[string]bool),
+	}
+	var exported []*ast.TypeSpec
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			resolver.specs[typeSpec.Name.Name] = typeSpec
+			if resolver.isExportedStruct(typeSpec) {
+				exported = append(exported, typeSpec)
+			}
+		}
+	}
+
+	structs := make([]models.StructDecl, 0, len(exported))
+	for _, typeSpec := range exported {
+		structs = append(structs, models.StructDecl{
+			Name:   typeSpec.Name.Name,
+			Fields: resolver.fields(typeSpec.Type.(*ast.StructType)),
+		})
+	}
+	return structs
+}
+
+// isExportedStruct reports whether a type declaration is extracted as a StructDecl
+func (r *structResolver) isExportedStruct(typeSpec *ast.TypeSpec) bool {
+	_, isStruct := typeSpec.Type.(*ast.StructType)
+	return isStruct && typeSpec.Name.IsExported() && typeSpec.TypeParams == nil && !typeSpec.Assign.IsValid()
+}
+
+// fields returns the fields of a struct that are encoded to JSON, in declaration order.
+// Fields of embedded structs declared in the file are promoted like encoding/json does.
+func (r *structResolver) fields(structType *ast.StructType) []models.StructField {
+	var fields []models.StructField
+	for _, field := range structType.Fields.List {
+		name, options := jsonTag(field.Tag)
+		if name == "-" && len(options) == 0 {
+			continue
+		}
+		omitEmpty := slices.Contains(options, "omitempty") || slices.Contains(options, "omitzero")
+
+		if len(field.Names) == 0 {
+			typeName, local := embeddedTypeName(field.Type)
+			if name == "" && local {
+				if embedded := r.embeddedStruct(typeName); embedded != nil {
+					r.visiting[typeName] = true
+					fields = append(fields, r.fields(embedded)...)
+					delete(r.visiting, typeName)
+					continue
+				}
+			}
+			if !ast.IsExported(typeName) {
+				continue
+			}
+			fields = append(fields, r.field(typeName, name, options, omitEmpty, field.Type))
+			continue
+		}
+
+		for _, ident := range field.Names {
+			if ident.IsExported() {
+				fields = append(fields, r.field(ident.Name, name, options, omitEmpty, field.Type))
+			}
+		}
+	}
+	return fields
+}
+
+// field builds a struct field, named by its json tag when it sets one
+func (r *structResolver) field(goName, tagName string, options []string, omitEmpty bool, expr ast.Expr) models.StructField {
+	field := models.StructField{
+		Name:      goName,
+		JSONName:  goName,
+		Type:      r.fieldType(expr),
+		OmitEmpty: omitEmpty,
+	}
+	if tagName != "" {
+		field.JSONName = tagName
+	}
+	// The string option encodes numbers and booleans as JSON strings
+	if slices.Contains(options, "string") && (field.Type.Kind == models.FieldNumber || field.Type.Kind == models.FieldBool) {
+		field.Type.Kind = models.FieldString
+	}
+	return field
+}
+
+// embeddedStruct returns the struct type of an embedded field declared in the file
+func (r *structResolver) embeddedStruct(typeName string) *ast.StructType {
+	typeSpec, ok := r.specs[typeName]
+	if !ok || typeSpec.TypeParams != nil || r.visiting[typeName] {
+		return nil
+	}
+	structType, _ := typeSpec.Type.(*ast.StructType)
+	return structType
+}
+
+// fieldType describes a field's Go type. Exported structs of the file are referenced by
+// name and other types declared in it are resolved to what they're declared as.
+func (r *structResolver) fieldType(expr ast.Expr) models.FieldType {
+	fieldType := models.FieldType{Kind: models.FieldUnsupported, GoType: types.ExprString(expr)}
+
+	switch typ := expr.(type) {
+	case *ast.ParenExpr:
+		return r.fieldType(typ.X)
+
+	case *ast.Ident:
+		if kind, ok := basicFieldKind(typ.Name); ok {
+			fieldType.Kind = kind
+			return fieldType
+		}
+		typeSpec, declared := r.specs[typ.Name]
+		if !declared || typeSpec.TypeParams != nil || r.visiting[typ.Name] {
+			return fieldType
+		}
+		if r.isExportedStruct(typeSpec) {
+			fieldType.Kind = models.FieldStruct
+			fieldType.Name = typ.Name
+			return fieldType
+		}
+		r.visiting[typ.Name] = true
+		defer delete(r.visiting, typ.Name)
+		resolved := r.fieldType(typeSpec.Type)
+		resolved.GoType = fieldType.GoType
+		return resolved
+
+	case *ast.StarExpr:
+		elem := r.fieldType(typ.X)
+		fieldType.Kind = models.FieldPointer
+		fieldType.Elem = &elem
+
+	case *ast.ArrayType:
+		// Byte slices are encoded as base64 strings
+		if ident, ok := typ.Elt.(*ast.Ident); ok && ident.Name == "byte" && typ.Len == nil {
+			fieldType.Kind = models.FieldString
+			return fieldType
+		}
+		elem := r.fieldType(typ.Elt)
+		fieldType.Kind = models.FieldSlice
+		fieldType.Elem = &elem
+
+	case *ast.MapType:
+		key := r.fieldType(typ.Key)
+		if key.Kind != models.FieldString && key.Kind != models.FieldNumber {
+			return fieldType
+		}
+		elem := r.fieldType(typ.Value)
+		fieldType.Kind = models.FieldMap
+		fieldType.Elem = &elem
+
+	case *ast.StructType:
+		fieldType.Kind = models.FieldObject
+		fieldType.Fields = r.fields(typ)
+
+	case *ast.InterfaceType:
+		fieldType.Kind = models.FieldAny
+
+	case *ast.SelectorExpr:
+		switch fieldType.GoType {
+		case "time.Time":
+			fieldType.Kind = models.FieldString
+		case "json.RawMessage":
+			fieldType.Kind = models.FieldAny
+		}
+	}
+	return fieldType
+}
+
+// basicFieldKind maps predeclared types to their JSON shape
+func basicFieldKind(name string) (models.FieldKind, bool) {
+	switch name {
+	case "string":
+		return models.FieldString, true
+	case "bool":
+		return models.FieldBool, true
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+		"uintptr", "float32", "float64", "byte", "rune":
+		return models.FieldNumber, true
+	case "any":
+		return models.FieldAny, true
+	}
+	return "", false
+}
+
+// embeddedTypeName returns the type name of an embedded field such as T, *T or pkg.T, and
+// whether it's declared in the same package
+func embeddedTypeName(expr ast.Expr) (string, bool) {
+	switch typ := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedTypeName(typ.X)
+	case *ast.Ident:
+		return typ.Name, true
+	case *ast.SelectorExpr:
+		return typ.Sel.Name, false
+	}
+	return "", false
+}
+
+// jsonTag returns the name and options of a field's json struct tag
+func jsonTag(tag *ast.BasicLit) (string, []string) {
+	if tag == nil {
+		return "", nil
+	}
+	value, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return "", nil
+	}
+	parts := strings.Split(reflect.StructTag(value).Get("json"), ",")
+	return parts[0], parts[1:]
+}
@@ -0,0 +1,76 @@
+package ast
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// TypeCheckError lists the type errors found in a single route package
+type TypeCheckError struct {
+	Dir    string   // route directory, relative to the project root
+	Errors []string // "file:line:col: message" for each error
+}
+
+func (e *TypeCheckError) Error() string {
+	return fmt.Sprintf("route %s does not type check:\n  %s", e.Dir, strings.Join(e.Errors, "\n  "))
+}
+
+// TypeCheckRoutes loads each route directory as a package with its real imports and
+// type checks it. dirs are relative to wd; one *TypeCheckError is returned per failing route.
+func TypeCheckRoutes(wd string, dirs []string) ([]*TypeCheckError, error) {
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+
+	patterns := make([]string, len(dirs))
+	for i, dir := range dirs {
+		patterns[i] = "./" + filepath.ToSlash(dir)
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir: wd,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load route packages: %w", err)
+	}
+
+	var failures []*TypeCheckError
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) == 0 {
+			continue
+		}
+
+		dir := pkg.PkgPath
+		if len(pkg.GoFiles) > 0 {
+			if rel, err := filepath.Rel(wd, filepath.Dir(pkg.GoFiles[0])); err == nil {
+				dir = rel
+			}
+		}
+
+		failure := &TypeCheckError{Dir: dir}
+		for _, pkgErr := range pkg.Errors {
+			pos := pkgErr.Pos
+			if file, rest, found := strings.Cut(pos, ":"); found {
+				if rel, err := filepath.Rel(wd, file); err == nil {
+					pos = rel + ":" + rest
+				}
+			}
+			if pos == "" || pos == "-" {
+				failure.Errors = append(failure.Errors, pkgErr.Msg)
+			} else {
+				failure.Errors = append(failure.Errors, pos+": "+pkgErr.Msg)
+			}
+		}
+		failures = append(failures, failure)
+	}
+
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Dir < failures[j].Dir })
+	return failures, nil
+}
@@ -0,0 +1,179 @@
+package ast
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// CheckSyntax parses a route file and returns every syntax error it contains
+func CheckSyntax(path string) error {
+	fset := token.NewFileSet()
+	_, err := parser.ParseFile(fset, path, nil, parser.AllErrors)
+	return err
+}
+
+// CheckHandlerSignatures reports HTTP method handlers in a route file whose signature
+// isn't func(http.ResponseWriter, *http.Request)
+func CheckHandlerSignatures(path string) ([]string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	httpPkg := httpPackageName(f)
+
+	var problems []string
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil {
+			continue
+		}
+
+		switch strings.ToUpper(fn.Name.Name) {
+		case "GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD":
+		default:
+			continue
+		}
+
+		if !isHandlerSignature(fn.Type, httpPkg) {
+			problems = append(problems, fmt.Sprintf("%s (line %d) must have signature func(http.ResponseWriter, *http.Request)",
+				fn.Name.Name, fset.Position(fn.Pos()).Line))
+		}
+	}
+
+	return problems, nil
+}
+
+// httpPackageName returns the name net/http is imported under in a file, or "" when it
+// isn't imported
+func httpPackageName(f *ast.File) string {
+	httpPkg := ""
+	for _, imp := range f.Imports {
+		if strings.Trim(imp.Path.Value, "\"") != "net/http" {
+			continue
+		}
+		httpPkg = "http"
+		if imp.Name != nil {
+			httpPkg = imp.Name.Name
+		}
+	}
+	return httpPkg
+}
+
+func isHandlerSignature(fnType *ast.FuncType, httpPkg string) bool {
+	if httpPkg == "" || fnType.TypeParams != nil || fnType.Results != nil && len(fnType.Results.List) > 0 {
+		return false
+	}
+
+	var params []ast.Expr
+	for _, field := range fnType.Params.List {
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for range count {
+			params = append(params, field.Type)
+		}
+	}
+	if len(params) != 2 {
+		return false
+	}
+
+	star, ok := params[1].(*ast.StarExpr)
+	return isQualified(params[0], httpPkg, "ResponseWriter") && ok && isQualified(star.X, httpPkg, "Request")
+}
+
+func isQualified(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == pkg
+}
+
+// httpMethodNames are the exact handler names conduit registers
+var httpMethodNames = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD"}
+
+// NameViolation is a function whose name looks like a mistyped HTTP method handler
+type NameViolation struct {
+	Name  string // function name as declared
+	Issue string // what is wrong with it
+}
+
+// ValidateHandlerNames flags top-level functions shaped like handlers, func(http.ResponseWriter,
+// *http.Request), whose names are close to, but not exactly, an HTTP method: wrong casing
+// (Get, get) or one edit away (GETT, Post_). Helpers such as host() are left alone.
+func ValidateHandlerNames(f *ast.File) []NameViolation {
+	httpPkg := httpPackageName(f)
+
+	var violations []NameViolation
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil {
+			continue
+		}
+
+		name := fn.Name.Name
+		if slices.Contains(httpMethodNames, name) || !isHandlerSignature(fn.Type, httpPkg) {
+			continue
+		}
+
+		upper := strings.ToUpper(name)
+		for _, method := range httpMethodNames {
+			if upper == method {
+				violations = append(violations, NameViolation{
+					Name:  name,
+					Issue: fmt.Sprintf("handler names must be uppercase: rename %s to %s", name, method),
+				})
+				break
+			}
+			if levenshtein(upper, method) <= 1 {
+				violations = append(violations, NameViolation{
+					Name:  name,
+					Issue: fmt.Sprintf("%s looks like a misspelled %s handler and will not be registered", name, method),
+				})
+				break
+			}
+		}
+	}
+	return violations
+}
+
+// CheckHandlerNames parses a route file and returns its handler naming violations
+func CheckHandlerNames(path string) ([]NameViolation, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateHandlerNames(f), nil
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
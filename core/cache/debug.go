@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"sort"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+)
+
+// DependencyReport is the /cache/deps response for one file
+type DependencyReport struct {
+	File         string   `json:"file"`
+	Dependencies []string `json:"dependencies"` // files it imports
+	Dependents   []string `json:"dependents"`   // files importing it
+}
+
+// DebugHandler serves the state of cm as JSON for debugging regeneration decisions:
+//
+//	/cache/stats             GetStats() by layer
+//	/cache/deps?file=<path>  dependencies and dependents of a file (relative to root or
+//	                         absolute) or of a package by import path
+func DebugHandler(cm models.CacheManagerInterface, root string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /cache/stats", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, cm.GetStats())
+	})
+	mux.HandleFunc("GET /cache/deps", func(w http.ResponseWriter, r *http.Request) {
+		file := r.URL.Query().Get("file")
+		if file == "" {
+			http.Error(w, "missing file query parameter", http.StatusBadRequest)
+			return
+		}
+		// Packages are graph nodes under their import path, files under their absolute path
+		graph := cm.GetDependencyGraph()
+		if _, exists := graph.GetNode(file); !exists && !filepath.IsAbs(file) {
+			file = filepath.Join(root, file)
+		}
+		if _, exists := graph.GetNode(file); !exists {
+			http.Error(w, "no dependency information for "+file, http.StatusNotFound)
+			return
+		}
+		dependencies, err := graph.GetDependencies(file)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		dependents, err := graph.GetDependents(file)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		report := DependencyReport{
+			File:         file,
+			Dependencies: append([]string{}, dependencies...),
+			Dependents:   append([]string{}, dependents...),
+		}
+		sort.Strings(report.Dependencies)
+		sort.Strings(report.Dependents)
+		writeJSON(w, report)
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
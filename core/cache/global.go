@@ -2,9 +2,11 @@ package cache
 
 import (
 	"sync"
+	"time"
 
 	"github.com/tristendillon/conduit/core/cache/manager"
 	"github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/config"
 	"github.com/tristendillon/conduit/core/logger"
 )
 
@@ -17,7 +19,23 @@ var (
 // This provides backward compatibility with the old cache.GetCache() pattern
 func GetCacheManager() models.CacheManagerInterface {
 	cacheOnce.Do(func() {
-		globalCacheManager = manager.NewCacheManager()
+		var cacheConfig models.CacheConfig
+		if cfg, err := config.Load(); err != nil {
+			logger.Debug("Failed to load cache limits from config: %v", err)
+		} else {
+			cacheConfig.Content.MaxFileBytes = cfg.Cache.ContentMaxFileBytes
+			cacheConfig.Content.MaxBytes = cfg.Cache.ContentMaxBytes
+			cacheConfig.Content.MaxEntries = cfg.Cache.ContentMaxEntries
+			cacheConfig.Content.CleanupInterval = time.Duration(cfg.Cache.ContentCleanupIntervalMs) * time.Millisecond
+			cacheConfig.Parse.MaxEntries = cfg.Cache.ParseMaxEntries
+			cacheConfig.Parse.MaxBytes = cfg.Cache.ParseMaxBytes
+			if hasher, err := models.NewHasher(cfg.Cache.HashAlgorithm); err != nil {
+				logger.Warn("Using %s hashes: %v", models.CurrentHasher().Name(), err)
+			} else {
+				models.SetHasher(hasher)
+			}
+		}
+		globalCacheManager = manager.NewCacheManagerWithConfig(cacheConfig)
 		logger.Debug("Initialized global cache manager")
 	})
 	return globalCacheManager
@@ -46,9 +64,9 @@ func GetGlobalCacheStats() map[string]*models.CacheStats {
 }
 
 // ValidateGlobalCacheIntegrity checks cache consistency
-func ValidateGlobalCacheIntegrity() error {
+func ValidateGlobalCacheIntegrity() (*models.IntegrityReport, error) {
 	if globalCacheManager != nil {
 		return globalCacheManager.ValidateIntegrity()
 	}
-	return nil
-}
\ No newline at end of file
+	return &models.IntegrityReport{}, nil
+}
@@ -0,0 +1,109 @@
+package layers
+
+import (
+	"container/list"
+	"os"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+// contentBuffers keeps the raw contents of recently hashed files so they can be parsed
+// without reading them again. It isn't thread-safe; the content cache locks around it.
+type contentBuffers struct {
+	entries   map[string]*list.Element // values are *contentBuffer
+	lru       *list.List               // front is the most recently used
+	bytes     int64
+	evictions int64
+}
+
+type contentBuffer struct {
+	path string
+	hash string
+	data []byte
+}
+
+func newContentBuffers() contentBuffers {
+	return contentBuffers{
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// put stores data as the contents of path at hash, then evicts the least recently used
+// buffers until no more than maxBytes are kept (zero means no limit)
+func (cb *contentBuffers) put(path, hash string, data []byte, maxBytes int64) {
+	cb.remove(path)
+	cb.entries[path] = cb.lru.PushFront(&contentBuffer{path: path, hash: hash, data: data})
+	cb.bytes += int64(len(data))
+
+	for maxBytes > 0 && cb.bytes > maxBytes && cb.lru.Len() > 0 {
+		buffer := cb.lru.Back().Value.(*contentBuffer)
+		cb.remove(buffer.path)
+		cb.evictions++
+		logger.Debug("ContentCache: Dropped contents of %s (%d bytes kept)", buffer.path, cb.bytes)
+	}
+}
+
+// get returns the contents of path if they were kept for the given hash
+func (cb *contentBuffers) get(path, hash string) ([]byte, bool) {
+	element, exists := cb.entries[path]
+	if !exists {
+		return nil, false
+	}
+	buffer := element.Value.(*contentBuffer)
+	if buffer.hash != hash {
+		return nil, false
+	}
+	cb.lru.MoveToFront(element)
+	return buffer.data, true
+}
+
+// remove drops the contents of path if present
+func (cb *contentBuffers) remove(path string) {
+	element, exists := cb.entries[path]
+	if !exists {
+		return
+	}
+	cb.bytes -= int64(len(element.Value.(*contentBuffer).data))
+	cb.lru.Remove(element)
+	delete(cb.entries, path)
+}
+
+// clear drops every buffer
+func (cb *contentBuffers) clear() {
+	cb.entries = make(map[string]*list.Element)
+	cb.lru.Init()
+	cb.bytes = 0
+}
+
+// hashFile hashes a file, keeping its contents when they're small enough to retain
+// (not thread-safe, caller must lock)
+func (cc *ContentCache) hashFile(filePath string, size int64) (string, error) {
+	if cc.config.MaxFileBytes <= 0 || size > cc.config.MaxFileBytes {
+		return models.HashFile(filePath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	hash := models.HashBytes(data)
+	cc.buffers.put(filePath, hash, data, cc.config.MaxBytes)
+	return hash, nil
+}
+
+// GetContentBytes returns the contents of a file read when it was last hashed, if they
+// were kept and still match its current entry
+func (cc *ContentCache) GetContentBytes(filePath string) ([]byte, bool) {
+	key := models.CanonicalPath(filePath)
+	// A hit reorders the LRU list, so lookups take the write lock
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	entry, exists := cc.entries[key]
+	if !exists {
+		return nil, false
+	}
+	return cc.buffers.get(key, entry.ContentHash)
+}
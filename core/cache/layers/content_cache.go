@@ -1,10 +1,10 @@
 package layers
 
 import (
-	"crypto/md5"
+	"container/list"
 	"fmt"
-	"io"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -12,74 +12,163 @@ import (
 	"github.com/tristendillon/conduit/core/logger"
 )
 
-// ContentCache implements Layer 1: File content tracking
+// MissingTTL is how long a file found missing is assumed to stay missing before it is
+// checked on disk again. Create events clear the entry sooner.
+var MissingTTL = 5 * time.Second
+
+// ContentCache implements Layer 1: File content tracking. When configured it also keeps
+// the contents of small files so they aren't read again to be parsed, and bounds the
+// number of entries by evicting the least recently used; an evicted file is hashed again
+// on its next lookup and reported as new.
 type ContentCache struct {
-	entries map[string]*models.ContentEntry
-	mutex   sync.RWMutex
-	stats   struct {
-		hits   int64
-		misses int64
-	}
+	entries   map[string]*models.ContentEntry
+	mutex     sync.RWMutex
+	stats     hitCounter
+	config    models.ContentCacheConfig
+	buffers   contentBuffers
+	recency   *list.List               // file paths, front is the most recently used
+	elements  map[string]*list.Element // file path -> its element in recency
+	evictions int64
+	expired   int64
+	stop      chan struct{} // closed by Close to stop the janitor
+	closeOnce sync.Once
 }
 
-// NewContentCache creates a new content cache
+// NewContentCache creates a new content cache that keeps no file contents
 func NewContentCache() *ContentCache {
-	return &ContentCache{
-		entries: make(map[string]*models.ContentEntry),
-		mutex:   sync.RWMutex{},
+	return NewContentCacheWithConfig(models.ContentCacheConfig{})
+}
+
+// NewContentCacheWithConfig creates a content cache that keeps file contents within the given
+// limits. With a CleanupInterval, a janitor drops expired entries in the background until
+// Close is called.
+func NewContentCacheWithConfig(config models.ContentCacheConfig) *ContentCache {
+	cc := &ContentCache{
+		entries:  make(map[string]*models.ContentEntry),
+		mutex:    sync.RWMutex{},
+		config:   config,
+		buffers:  newContentBuffers(),
+		recency:  list.New(),
+		elements: make(map[string]*list.Element),
+		stop:     make(chan struct{}),
+	}
+	if config.CleanupInterval > 0 {
+		go cc.runJanitor(config.CleanupInterval)
+	}
+	return cc
+}
+
+// runJanitor removes expired entries every interval until the cache is closed
+func (cc *ContentCache) runJanitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-cc.stop:
+			return
+		case <-ticker.C:
+			if removed := cc.RemoveExpired(); removed > 0 {
+				logger.Debug("ContentCache: Cleanup removed %d expired entries (%d tracked)", removed, cc.len())
+			}
+		}
+	}
+}
+
+// RemoveExpired drops the entries of files found missing more than MissingTTL ago, which
+// would otherwise stay until they're looked up again, and returns how many were removed
+func (cc *ContentCache) RemoveExpired() int {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	removed := 0
+	for filePath, entry := range cc.entries {
+		if entry.Exists || time.Since(entry.CheckedAt) < MissingTTL {
+			continue
+		}
+		delete(cc.entries, filePath)
+		cc.buffers.remove(filePath)
+		cc.forget(filePath)
+		removed++
 	}
+	cc.expired += int64(removed)
+	return removed
+}
+
+// Close stops the cleanup janitor; the cache stays usable
+func (cc *ContentCache) Close() error {
+	cc.closeOnce.Do(func() { close(cc.stop) })
+	return nil
+}
+
+func (cc *ContentCache) len() int {
+	cc.mutex.RLock()
+	defer cc.mutex.RUnlock()
+	return len(cc.entries)
 }
 
-// UpdateContent checks if file content has changed and updates entry
+// UpdateContent checks if file content has changed and updates entry. A missing file
+// gets an entry with Exists false, which is returned without touching the disk again
+// until MissingTTL has passed. Entries are stored under the file's canonical path, so
+// every spelling of it reaches the same one; a new entry keeps filePath for display.
 func (cc *ContentCache) UpdateContent(filePath string) (*models.ContentEntry, bool, error) {
+	key := models.CanonicalPath(filePath)
 	cc.mutex.Lock()
 	defer cc.mutex.Unlock()
 
+	existing, exists := cc.entries[key]
+	if exists && !existing.Exists && time.Since(existing.CheckedAt) < MissingTTL {
+		cc.stats.hit()
+		cc.touch(key)
+		return existing, false, nil
+	}
+	// Whatever is stored below becomes the most recently used entry
+	defer cc.touch(key)
+
 	// Get file info
-	stat, err := os.Stat(filePath)
+	stat, err := os.Stat(key)
 	if err != nil {
 		if os.IsNotExist(err) {
-			// File was deleted
-			if existing, exists := cc.entries[filePath]; exists {
+			missing := &models.ContentEntry{FilePath: filePath, Exists: false, CheckedAt: time.Now()}
+			cc.entries[key] = missing
+			cc.buffers.remove(key)
+			if exists && existing.Exists {
 				logger.Debug("ContentCache: File deleted: %s", filePath)
-				delete(cc.entries, filePath)
-				return existing, true, nil // changed = true because file was deleted
+				return missing, true, nil // changed = true because file was deleted
 			}
-			return nil, false, nil // file doesn't exist and wasn't cached
+			return missing, false, nil // file doesn't exist and wasn't cached
 		}
 		return nil, false, fmt.Errorf("failed to stat file %s: %w", filePath, err)
 	}
 
-	existing, exists := cc.entries[filePath]
-
-	// If we don't have an entry, create one
-	if !exists {
+	// If we don't have an entry for the file as it exists now, create one
+	if !exists || !existing.Exists {
 		logger.Debug("ContentCache: New file detected: %s", filePath)
-		cc.stats.misses++
-		entry, err := cc.createContentEntry(filePath, stat)
+		cc.stats.miss()
+		entry, err := cc.createContentEntry(filePath, key, stat)
 		if err != nil {
 			return nil, false, err
 		}
-		cc.entries[filePath] = entry
+		cc.entries[key] = entry
 		return entry, true, nil // changed = true because it's new
 	}
 
 	// Quick check: if size and modtime haven't changed, assume content is same
 	if stat.Size() == existing.Size && stat.ModTime().Equal(existing.ModTime) {
 		logger.Debug("ContentCache: Quick hit for %s (size and modtime unchanged)", filePath)
-		cc.stats.hits++
+		cc.stats.hit()
 		return existing, false, nil
 	}
 
 	// Size or modtime changed, need to check content hash
-	newHash, err := calculateFileHash(filePath)
+	newHash, err := cc.hashFile(key, stat.Size())
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to calculate hash for %s: %w", filePath, err)
 	}
 
 	// Content actually changed
 	if newHash != existing.ContentHash {
-		logger.Debug("ContentCache: Content changed for %s (hash: %s -> %s)", filePath, existing.ContentHash[:8], newHash[:8])
+		logger.Debug("ContentCache: Content changed for %s (hash: %s -> %s)", filePath, models.ShortHash(existing.ContentHash), models.ShortHash(newHash))
 		entry := &models.ContentEntry{
 			FilePath:    filePath,
 			ContentHash: newHash,
@@ -87,7 +176,7 @@ func (cc *ContentCache) UpdateContent(filePath string) (*models.ContentEntry, bo
 			Size:        stat.Size(),
 			Exists:      true,
 		}
-		cc.entries[filePath] = entry
+		cc.entries[key] = entry
 		return entry, true, nil
 	}
 
@@ -95,64 +184,113 @@ func (cc *ContentCache) UpdateContent(filePath string) (*models.ContentEntry, bo
 	logger.Debug("ContentCache: Metadata changed but content same for %s", filePath)
 	existing.ModTime = stat.ModTime()
 	existing.Size = stat.Size()
-	cc.stats.hits++
+	cc.stats.hit()
 	return existing, false, nil
 }
 
-// GetContent retrieves current content entry
+// GetContent retrieves current content entry. Files recently found missing return an
+// entry with Exists false, so callers can skip them; once MissingTTL has passed they
+// are reported as unknown.
 func (cc *ContentCache) GetContent(filePath string) (*models.ContentEntry, bool) {
-	cc.mutex.RLock()
-	defer cc.mutex.RUnlock()
+	key := models.CanonicalPath(filePath)
+	// A hit reorders the LRU list, so lookups take the write lock
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
 
-	entry, exists := cc.entries[filePath]
+	entry, exists := cc.entries[key]
+	if exists && !entry.Exists && time.Since(entry.CheckedAt) >= MissingTTL {
+		entry, exists = nil, false
+	}
 	if exists {
-		cc.stats.hits++
+		cc.stats.hit()
+		cc.touch(key)
 	} else {
-		cc.stats.misses++
+		cc.stats.miss()
 	}
 	return entry, exists
 }
 
 // SetContent manually sets content entry (for testing)
 func (cc *ContentCache) SetContent(filePath string, entry *models.ContentEntry) error {
+	key := models.CanonicalPath(filePath)
 	cc.mutex.Lock()
 	defer cc.mutex.Unlock()
 
-	cc.entries[filePath] = entry
+	cc.entries[key] = entry
+	cc.buffers.remove(key)
+	cc.touch(key)
 	logger.Debug("ContentCache: Manually set entry for %s", filePath)
 	return nil
 }
 
 // RemoveContent removes entry for deleted files
 func (cc *ContentCache) RemoveContent(filePath string) error {
+	key := models.CanonicalPath(filePath)
 	cc.mutex.Lock()
 	defer cc.mutex.Unlock()
 
-	if _, exists := cc.entries[filePath]; exists {
-		delete(cc.entries, filePath)
+	if _, exists := cc.entries[key]; exists {
+		delete(cc.entries, key)
 		logger.Debug("ContentCache: Removed entry for %s", filePath)
 	}
+	cc.buffers.remove(key)
+	cc.forget(key)
 	return nil
 }
 
+// ListFiles returns the canonical paths of all tracked files that exist in sorted order
+func (cc *ContentCache) ListFiles() []string {
+	cc.mutex.RLock()
+	defer cc.mutex.RUnlock()
+
+	files := make([]string, 0, len(cc.entries))
+	for filePath, entry := range cc.entries {
+		if entry.Exists {
+			files = append(files, filePath)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// GetAllContent returns a copy of every entry, including files recently found missing
+func (cc *ContentCache) GetAllContent() map[string]*models.ContentEntry {
+	cc.mutex.RLock()
+	defer cc.mutex.RUnlock()
+
+	entries := make(map[string]*models.ContentEntry, len(cc.entries))
+	for filePath, entry := range cc.entries {
+		entryCopy := *entry
+		entries[filePath] = &entryCopy
+	}
+	return entries
+}
+
 // GetStats returns cache statistics
 func (cc *ContentCache) GetStats() *models.CacheStats {
 	cc.mutex.RLock()
 	defer cc.mutex.RUnlock()
 
-	total := cc.stats.hits + cc.stats.misses
-	hitRate := 0.0
-	if total > 0 {
-		hitRate = float64(cc.stats.hits) / float64(total) * 100
+	totalFiles := 0
+	for _, entry := range cc.entries {
+		if entry.Exists {
+			totalFiles++
+		}
 	}
 
-	return &models.CacheStats{
-		TotalFiles:  len(cc.entries),
-		CacheHits:   cc.stats.hits,
-		CacheMisses: cc.stats.misses,
-		HitRate:     hitRate,
-		LastUpdate:  time.Now(),
+	stats := &models.CacheStats{
+		TotalFiles: totalFiles,
+		LastUpdate: time.Now(),
+		Evictions:  cc.evictions + cc.buffers.evictions,
+		Expired:    cc.expired,
 	}
+	cc.stats.fill(stats)
+	return stats
+}
+
+// MarkWarmed starts counting lookups toward the warm hit rate
+func (cc *ContentCache) MarkWarmed() {
+	cc.stats.markWarmed()
 }
 
 // Clear removes all entries
@@ -161,15 +299,47 @@ func (cc *ContentCache) Clear() error {
 	defer cc.mutex.Unlock()
 
 	cc.entries = make(map[string]*models.ContentEntry)
-	cc.stats.hits = 0
-	cc.stats.misses = 0
+	cc.buffers.clear()
+	cc.recency.Init()
+	cc.elements = make(map[string]*list.Element)
+	cc.stats.reset()
 	logger.Debug("ContentCache: Cleared all entries")
 	return nil
 }
 
-// createContentEntry creates a new content entry for a file
-func (cc *ContentCache) createContentEntry(filePath string, stat os.FileInfo) (*models.ContentEntry, error) {
-	hash, err := calculateFileHash(filePath)
+// touch marks filePath as the most recently used entry, then evicts the least recently
+// used entries beyond MaxEntries (not thread-safe, caller must lock)
+func (cc *ContentCache) touch(filePath string) {
+	if _, exists := cc.entries[filePath]; !exists {
+		return
+	}
+	if element, exists := cc.elements[filePath]; exists {
+		cc.recency.MoveToFront(element)
+	} else {
+		cc.elements[filePath] = cc.recency.PushFront(filePath)
+	}
+
+	for cc.config.MaxEntries > 0 && cc.recency.Len() > cc.config.MaxEntries {
+		oldest := cc.recency.Back().Value.(string)
+		delete(cc.entries, oldest)
+		cc.buffers.remove(oldest)
+		cc.forget(oldest)
+		cc.evictions++
+		logger.Debug("ContentCache: Evicted %s (%d entries left)", oldest, len(cc.entries))
+	}
+}
+
+// forget drops filePath from the recency list (not thread-safe, caller must lock)
+func (cc *ContentCache) forget(filePath string) {
+	if element, exists := cc.elements[filePath]; exists {
+		cc.recency.Remove(element)
+		delete(cc.elements, filePath)
+	}
+}
+
+// createContentEntry creates a new content entry for the file stored under key
+func (cc *ContentCache) createContentEntry(filePath, key string, stat os.FileInfo) (*models.ContentEntry, error) {
+	hash, err := cc.hashFile(key, stat.Size())
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate hash for %s: %w", filePath, err)
 	}
@@ -182,19 +352,3 @@ func (cc *ContentCache) createContentEntry(filePath string, stat os.FileInfo) (*
 		Exists:      true,
 	}, nil
 }
-
-// calculateFileHash computes MD5 hash of file content
-func calculateFileHash(filePath string) (string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-
-	hash := md5.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", err
-	}
-
-	return fmt.Sprintf("%x", hash.Sum(nil)), nil
-}
\ No newline at end of file
@@ -1,7 +1,9 @@
 package layers
 
 import (
-	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,6 +34,13 @@ func (dg *DependencyGraph) BuildGraph(parsedFiles map[string]*coreModels.ParsedF
 	// Clear existing graph
 	dg.nodes = make(map[string]*models.DependencyNode)
 
+	// Nodes are keyed like every other lookup, so equivalent spellings of a path meet
+	keyed := make(map[string]*coreModels.ParsedFile, len(parsedFiles))
+	for filePath, parsed := range parsedFiles {
+		keyed[models.NodeKey(filePath)] = parsed
+	}
+	parsedFiles = keyed
+
 	// First pass: create all nodes
 	for filePath := range parsedFiles {
 		node := &models.DependencyNode{
@@ -47,18 +56,23 @@ func (dg *DependencyGraph) BuildGraph(parsedFiles map[string]*coreModels.ParsedF
 	// Second pass: build dependency relationships
 	for filePath, parsed := range parsedFiles {
 		if parsed.Dependencies != nil {
-			var dependencies []string
+			var edges []models.Edge
 
 			// Add local imports as dependencies
-			for _, localImport := range parsed.Dependencies.LocalImports {
-				dependencies = append(dependencies, localImport.ImportPath)
+			for _, edge := range models.ImportEdges(parsed) {
+				if edge.Kind != models.EdgeLocal {
+					continue
+				}
+				edge.Target = models.NodeKey(edge.Target)
+				edges = append(edges, edge)
 				// Add this file as a dependent of the imported file
-				dg.addDependentRelationship(localImport.ImportPath, filePath)
+				dg.addDependentRelationship(edge.Target, filePath)
 			}
 
 			// Update node with dependencies
 			if node, exists := dg.nodes[filePath]; exists {
-				node.Dependencies = dependencies
+				node.Edges = edges
+				node.Dependencies = models.EdgeTargets(edges)
 			}
 		}
 	}
@@ -69,6 +83,12 @@ func (dg *DependencyGraph) BuildGraph(parsedFiles map[string]*coreModels.ParsedF
 
 // UpdateNode updates a single node in the graph
 func (dg *DependencyGraph) UpdateNode(filePath string, dependencies []string) error {
+	return dg.UpdateNodeEdges(filePath, models.EdgesTo(dependencies))
+}
+
+// UpdateNodeEdges updates a single node in the graph with the imports behind its dependencies
+func (dg *DependencyGraph) UpdateNodeEdges(filePath string, edges []models.Edge) error {
+	filePath = models.NodeKey(filePath)
 	dg.mutex.Lock()
 	defer dg.mutex.Unlock()
 
@@ -90,32 +110,103 @@ func (dg *DependencyGraph) UpdateNode(filePath string, dependencies []string) er
 	}
 
 	// Add new dependency relationships
-	node.Dependencies = dependencies
-	for _, newDep := range dependencies {
+	edges = slices.Clone(edges)
+	for i := range edges {
+		edges[i].Target = models.NodeKey(edges[i].Target)
+	}
+	node.Edges = edges
+	node.Dependencies = models.EdgeTargets(edges)
+	for _, newDep := range node.Dependencies {
 		dg.addDependentRelationship(newDep, filePath)
 	}
 
-	logger.Debug("DependencyGraph: Updated node %s with %d dependencies", filePath, len(dependencies))
+	logger.Debug("DependencyGraph: Updated node %s with %d dependencies", filePath, len(edges))
 	return nil
 }
 
 // GetAffectedFiles returns all files affected by a change
 func (dg *DependencyGraph) GetAffectedFiles(changedFile string) ([]string, error) {
+	changedFile = models.NodeKey(changedFile)
 	dg.mutex.RLock()
 	defer dg.mutex.RUnlock()
 
-	visited := make(map[string]bool)
-	var affected []string
-
-	// Use DFS to find all dependents
-	dg.dfsVisitDependents(changedFile, visited, &affected)
+	affected := dg.collectDependents(changedFile, 0)
 
 	logger.Debug("DependencyGraph: File %s affects %d files: %v", changedFile, len(affected), affected)
 	return affected, nil
 }
 
+// GetAffectedFilesOrdered returns the files affected by a change in regeneration order:
+// each file comes after the affected files it depends on, ties broken by path. Only
+// dependents up to maxDepth edges away are included; zero or less means no limit. Files
+// in a dependency cycle can't be ordered and come last, sorted.
+func (dg *DependencyGraph) GetAffectedFilesOrdered(changedFile string, maxDepth int) ([]string, error) {
+	changedFile = models.NodeKey(changedFile)
+	dg.mutex.RLock()
+	defer dg.mutex.RUnlock()
+
+	affected := dg.collectDependents(changedFile, maxDepth)
+	included := make(map[string]bool, len(affected))
+	for _, filePath := range affected {
+		included[filePath] = true
+	}
+
+	// Kahn's algorithm restricted to the affected files
+	inDegree := make(map[string]int, len(affected))
+	var queue []string
+	for _, filePath := range sortedCopy(affected) {
+		node, exists := dg.nodes[filePath]
+		if !exists {
+			continue
+		}
+		for _, dependency := range uniqueStrings(node.Dependencies) {
+			if included[dependency] {
+				inDegree[filePath]++
+			}
+		}
+		if inDegree[filePath] == 0 {
+			queue = append(queue, filePath)
+		}
+	}
+
+	ordered := make([]string, 0, len(affected))
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		ordered = append(ordered, current)
+
+		node, exists := dg.nodes[current]
+		if !exists {
+			continue
+		}
+		for _, dependent := range uniqueStrings(sortedCopy(node.Dependents)) {
+			if !included[dependent] {
+				continue
+			}
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	if len(ordered) < len(affected) {
+		var remaining []string
+		for _, filePath := range sortedCopy(affected) {
+			if inDegree[filePath] > 0 {
+				remaining = append(remaining, filePath)
+			}
+		}
+		logger.Debug("DependencyGraph: %d files affected by %s are in a cycle and left unordered", len(remaining), changedFile)
+		ordered = append(ordered, remaining...)
+	}
+
+	return ordered, nil
+}
+
 // GetDependencies returns direct dependencies of a file
 func (dg *DependencyGraph) GetDependencies(filePath string) ([]string, error) {
+	filePath = models.NodeKey(filePath)
 	dg.mutex.RLock()
 	defer dg.mutex.RUnlock()
 
@@ -130,8 +221,45 @@ func (dg *DependencyGraph) GetDependencies(filePath string) ([]string, error) {
 	return dependencies, nil
 }
 
+// SetNodeType records what kind of file a node is, adding the node if it's missing
+func (dg *DependencyGraph) SetNodeType(filePath string, nodeType models.NodeType) error {
+	filePath = models.NodeKey(filePath)
+	dg.mutex.Lock()
+	defer dg.mutex.Unlock()
+
+	node, exists := dg.nodes[filePath]
+	if !exists {
+		node = &models.DependencyNode{
+			FilePath:     filePath,
+			Dependencies: []string{},
+			Dependents:   []string{},
+		}
+		dg.nodes[filePath] = node
+	}
+	node.NodeType = nodeType
+	return nil
+}
+
+// GetEdges returns the direct dependencies of a file with the imports that create them
+func (dg *DependencyGraph) GetEdges(filePath string) ([]models.Edge, error) {
+	filePath = models.NodeKey(filePath)
+	dg.mutex.RLock()
+	defer dg.mutex.RUnlock()
+
+	node, exists := dg.nodes[filePath]
+	if !exists {
+		return []models.Edge{}, nil
+	}
+
+	// Return a copy to avoid concurrent modification
+	edges := make([]models.Edge, len(node.Edges))
+	copy(edges, node.Edges)
+	return edges, nil
+}
+
 // GetDependents returns files that depend on this file
 func (dg *DependencyGraph) GetDependents(filePath string) ([]string, error) {
+	filePath = models.NodeKey(filePath)
 	dg.mutex.RLock()
 	defer dg.mutex.RUnlock()
 
@@ -146,8 +274,84 @@ func (dg *DependencyGraph) GetDependents(filePath string) ([]string, error) {
 	return dependents, nil
 }
 
+// GetDependentsTransitive returns the files that depend on filePath directly or through
+// other files, nearest first and then by path. Only dependents up to maxDepth edges away
+// are included; zero or less means no limit.
+func (dg *DependencyGraph) GetDependentsTransitive(filePath string, maxDepth int) ([]string, error) {
+	filePath = models.NodeKey(filePath)
+	dg.mutex.RLock()
+	defer dg.mutex.RUnlock()
+
+	dependents := dg.collectDependents(filePath, maxDepth)
+	if dependents == nil {
+		dependents = []string{}
+	}
+	return dependents, nil
+}
+
+// GetTransitiveDependencies returns everything filePath depends on directly or through its
+// dependencies, nearest first and then by path
+func (dg *DependencyGraph) GetTransitiveDependencies(filePath string) ([]string, error) {
+	filePath = models.NodeKey(filePath)
+	dg.mutex.RLock()
+	defer dg.mutex.RUnlock()
+
+	dependencies := dg.collectDependencies(filePath)
+	if dependencies == nil {
+		dependencies = []string{}
+	}
+	return dependencies, nil
+}
+
+// FindPath returns the shortest chain of dependencies leading from one file to another,
+// starting with from and ending with to, or an empty slice when to isn't reachable.
+// Among chains of equal length the one through the smallest paths wins.
+func (dg *DependencyGraph) FindPath(from, to string) ([]string, error) {
+	from = models.NodeKey(from)
+	to = models.NodeKey(to)
+	dg.mutex.RLock()
+	defer dg.mutex.RUnlock()
+
+	if _, exists := dg.nodes[from]; !exists {
+		return []string{}, nil
+	}
+	if from == to {
+		return []string{from}, nil
+	}
+
+	// Breadth-first search over dependency edges, remembering how each file was reached
+	parent := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		node, exists := dg.nodes[current]
+		if !exists {
+			continue
+		}
+		for _, dep := range sortedCopy(node.Dependencies) {
+			if _, seen := parent[dep]; seen {
+				continue
+			}
+			parent[dep] = current
+			if dep == to {
+				var path []string
+				for at := to; at != ""; at = parent[at] {
+					path = append([]string{at}, path...)
+				}
+				return path, nil
+			}
+			queue = append(queue, dep)
+		}
+	}
+
+	return []string{}, nil
+}
+
 // GetNode retrieves a dependency node
 func (dg *DependencyGraph) GetNode(filePath string) (*models.DependencyNode, bool) {
+	filePath = models.NodeKey(filePath)
 	dg.mutex.RLock()
 	defer dg.mutex.RUnlock()
 
@@ -157,21 +361,47 @@ func (dg *DependencyGraph) GetNode(filePath string) (*models.DependencyNode, boo
 	}
 
 	// Return a copy to avoid concurrent modification
-	nodeCopy := &models.DependencyNode{
-		FilePath:     node.FilePath,
-		NodeType:     node.NodeType,
-		Dependencies: make([]string, len(node.Dependencies)),
-		Dependents:   make([]string, len(node.Dependents)),
-		ContentHash:  node.ContentHash,
+	return copyNode(node), true
+}
+
+// GetAllNodes returns a copy of every node, keyed by file path
+func (dg *DependencyGraph) GetAllNodes() map[string]*models.DependencyNode {
+	dg.mutex.RLock()
+	defer dg.mutex.RUnlock()
+
+	nodes := make(map[string]*models.DependencyNode, len(dg.nodes))
+	for filePath, node := range dg.nodes {
+		nodes[filePath] = copyNode(node)
+	}
+	return nodes
+}
+
+// RestoreNodes replaces the graph with copies of nodes as they are, edges included. The
+// nodes must already be consistent, as those from GetAllNodes are.
+func (dg *DependencyGraph) RestoreNodes(nodes []*models.DependencyNode) error {
+	dg.mutex.Lock()
+	defer dg.mutex.Unlock()
+
+	dg.nodes = make(map[string]*models.DependencyNode, len(nodes))
+	for _, node := range nodes {
+		if node == nil || node.FilePath == "" {
+			continue
+		}
+		restored := copyNode(node)
+		// Nodes saved before edges were recorded only list their dependencies
+		if len(restored.Edges) == 0 && len(restored.Dependencies) > 0 {
+			restored.Edges = models.EdgesTo(restored.Dependencies)
+		}
+		dg.nodes[node.FilePath] = restored
 	}
-	copy(nodeCopy.Dependencies, node.Dependencies)
-	copy(nodeCopy.Dependents, node.Dependents)
 
-	return nodeCopy, true
+	logger.Debug("DependencyGraph: Restored %d nodes", len(dg.nodes))
+	return nil
 }
 
 // RemoveNode removes a node and updates dependent relationships
 func (dg *DependencyGraph) RemoveNode(filePath string) error {
+	filePath = models.NodeKey(filePath)
 	dg.mutex.Lock()
 	defer dg.mutex.Unlock()
 
@@ -189,6 +419,7 @@ func (dg *DependencyGraph) RemoveNode(filePath string) error {
 	for _, dependent := range node.Dependents {
 		if depNode, exists := dg.nodes[dependent]; exists {
 			depNode.Dependencies = removeFromSlice(depNode.Dependencies, filePath)
+			depNode.Edges = slices.DeleteFunc(depNode.Edges, func(edge models.Edge) bool { return edge.Target == filePath })
 		}
 	}
 
@@ -197,31 +428,37 @@ func (dg *DependencyGraph) RemoveNode(filePath string) error {
 	return nil
 }
 
-// DetectCycles finds circular dependencies
+// DetectCycles finds circular dependencies. Every strongly connected component that
+// contains a cycle is reported, so disjoint cycles are all found, but the list isn't
+// exhaustive: a component yields the cycles closed by the back edges of one depth-first
+// search, so an elementary cycle overlapping one already found can be left out, as
+// [A C] is when A -> B -> C -> A and A <-> C.
 func (dg *DependencyGraph) DetectCycles() ([][]string, error) {
 	dg.mutex.RLock()
 	defer dg.mutex.RUnlock()
 
+	return dg.detectCycles(), nil
+}
+
+// detectCycles reports the cycles of every strongly connected component that
+// contains one, so disjoint cycles are all found, ordered by the smallest file path
+// in each component (not thread-safe, caller must lock)
+func (dg *DependencyGraph) detectCycles() [][]string {
 	var cycles [][]string
-	visited := make(map[string]bool)
-	recursionStack := make(map[string]bool)
-	path := []string{}
-
-	for filePath := range dg.nodes {
-		if !visited[filePath] {
-			if cyclePath := dg.dfsFindCycles(filePath, visited, recursionStack, path); cyclePath != nil {
-				cycles = append(cycles, cyclePath)
-			}
-		}
+
+	for _, component := range dg.stronglyConnectedComponents() {
+		cycles = append(cycles, dg.cyclesIn(component)...)
 	}
 
 	if len(cycles) > 0 {
 		logger.Debug("DependencyGraph: Detected %d cycles", len(cycles))
 	}
-	return cycles, nil
+	return cycles
 }
 
-// GetTopologicalOrder returns files in dependency order
+// GetTopologicalOrder returns files in dependency order. The order is stable for a
+// given graph. If the graph contains cycles, the acyclic portion is returned along
+// with a *models.CycleError listing the files that could not be ordered.
 func (dg *DependencyGraph) GetTopologicalOrder() ([]string, error) {
 	dg.mutex.RLock()
 	defer dg.mutex.RUnlock()
@@ -231,10 +468,15 @@ func (dg *DependencyGraph) GetTopologicalOrder() ([]string, error) {
 	queue := []string{}
 	result := []string{}
 
-	// Calculate in-degrees
-	for filePath, node := range dg.nodes {
-		inDegree[filePath] = len(node.Dependencies)
-		if len(node.Dependencies) == 0 {
+	// Calculate in-degrees, seeding the queue in sorted order. Dependencies outside the
+	// graph (external packages, stdlib) are never processed, so they aren't counted.
+	for _, filePath := range dg.sortedPaths() {
+		for _, dependency := range uniqueStrings(dg.nodes[filePath].Dependencies) {
+			if _, exists := dg.nodes[dependency]; exists {
+				inDegree[filePath]++
+			}
+		}
+		if inDegree[filePath] == 0 {
 			queue = append(queue, filePath)
 		}
 	}
@@ -247,7 +489,7 @@ func (dg *DependencyGraph) GetTopologicalOrder() ([]string, error) {
 
 		// Reduce in-degree of dependents
 		if node, exists := dg.nodes[current]; exists {
-			for _, dependent := range node.Dependents {
+			for _, dependent := range sortedCopy(node.Dependents) {
 				inDegree[dependent]--
 				if inDegree[dependent] == 0 {
 					queue = append(queue, dependent)
@@ -258,7 +500,13 @@ func (dg *DependencyGraph) GetTopologicalOrder() ([]string, error) {
 
 	// Check for cycles
 	if len(result) != len(dg.nodes) {
-		return nil, fmt.Errorf("dependency graph contains cycles")
+		var remaining []string
+		for _, filePath := range dg.sortedPaths() {
+			if inDegree[filePath] > 0 {
+				remaining = append(remaining, filePath)
+			}
+		}
+		return result, &models.CycleError{Remaining: remaining}
 	}
 
 	return result, nil
@@ -288,6 +536,22 @@ func (dg *DependencyGraph) Clear() error {
 
 // Helper methods
 
+// copyNode returns a copy of node that shares no slices with it
+func copyNode(node *models.DependencyNode) *models.DependencyNode {
+	nodeCopy := &models.DependencyNode{
+		FilePath:     node.FilePath,
+		NodeType:     node.NodeType,
+		Edges:        make([]models.Edge, len(node.Edges)),
+		Dependencies: make([]string, len(node.Dependencies)),
+		Dependents:   make([]string, len(node.Dependents)),
+		ContentHash:  node.ContentHash,
+	}
+	copy(nodeCopy.Edges, node.Edges)
+	copy(nodeCopy.Dependencies, node.Dependencies)
+	copy(nodeCopy.Dependents, node.Dependents)
+	return nodeCopy
+}
+
 // addDependentRelationship adds a dependent relationship (not thread-safe, caller must lock)
 func (dg *DependencyGraph) addDependentRelationship(dependencyPath, dependentPath string) {
 	// Create dependency node if it doesn't exist
@@ -317,61 +581,271 @@ func (dg *DependencyGraph) removeDependentRelationship(dependencyPath, dependent
 	}
 }
 
-// dfsVisitDependents performs DFS to find all affected files
-func (dg *DependencyGraph) dfsVisitDependents(filePath string, visited map[string]bool, affected *[]string) {
-	if visited[filePath] {
-		return
-	}
-	visited[filePath] = true
+// collectDependents walks dependents breadth-first with an explicit queue, so deep
+// chains don't grow the stack. Each file is returned once, ordered by distance from
+// filePath and then by path. Files more than maxDepth edges away are left out unless
+// maxDepth is zero or less (not thread-safe, caller must lock)
+func (dg *DependencyGraph) collectDependents(filePath string, maxDepth int) []string {
+	visited := map[string]bool{filePath: true}
+	var affected []string
 
-	node, exists := dg.nodes[filePath]
-	if !exists {
-		return
+	level := []string{filePath}
+	for depth := 1; len(level) > 0 && (maxDepth <= 0 || depth <= maxDepth); depth++ {
+		var next []string
+		for _, current := range level {
+			node, exists := dg.nodes[current]
+			if !exists {
+				continue
+			}
+			for _, dependent := range node.Dependents {
+				if visited[dependent] {
+					continue
+				}
+				visited[dependent] = true
+				next = append(next, dependent)
+			}
+		}
+		sort.Strings(next)
+		affected = append(affected, next...)
+		level = next
 	}
 
-	// Visit all dependents
-	for _, dependent := range node.Dependents {
-		*affected = append(*affected, dependent)
-		dg.dfsVisitDependents(dependent, visited, affected)
-	}
+	return affected
 }
 
-// dfsFindCycles performs DFS to detect cycles
-func (dg *DependencyGraph) dfsFindCycles(filePath string, visited, recursionStack map[string]bool, path []string) []string {
-	visited[filePath] = true
-	recursionStack[filePath] = true
-	path = append(path, filePath)
+// collectDependencies is collectDependents in the other direction, following dependency
+// edges breadth-first with no depth limit (not thread-safe, caller must lock)
+func (dg *DependencyGraph) collectDependencies(filePath string) []string {
+	visited := map[string]bool{filePath: true}
+	var dependencies []string
+
+	level := []string{filePath}
+	for len(level) > 0 {
+		var next []string
+		for _, current := range level {
+			node, exists := dg.nodes[current]
+			if !exists {
+				continue
+			}
+			for _, dependency := range node.Dependencies {
+				if visited[dependency] {
+					continue
+				}
+				visited[dependency] = true
+				next = append(next, dependency)
+			}
+		}
+		sort.Strings(next)
+		dependencies = append(dependencies, next...)
+		level = next
+	}
 
-	node, exists := dg.nodes[filePath]
-	if !exists {
-		recursionStack[filePath] = false
-		return nil
+	return dependencies
+}
+
+// stronglyConnectedComponents runs Tarjan's algorithm and returns every component
+// that contains a cycle (more than one node, or a node depending on itself).
+// Members are sorted and components are ordered by their first member. The search
+// keeps its own stack of frames instead of recursing, so long dependency chains don't
+// grow the goroutine stack (not thread-safe, caller must lock)
+func (dg *DependencyGraph) stronglyConnectedComponents() [][]string {
+	index := 0
+	indices := make(map[string]int)
+	lowLinks := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	// A frame is a file being visited and the position of the next dependency to follow
+	type frame struct {
+		filePath string
+		deps     []string
+		next     int
+	}
+	var frames []frame
+	push := func(filePath string) {
+		indices[filePath] = index
+		lowLinks[filePath] = index
+		index++
+		stack = append(stack, filePath)
+		onStack[filePath] = true
+
+		var deps []string
+		if node, exists := dg.nodes[filePath]; exists {
+			deps = sortedCopy(node.Dependencies)
+		}
+		frames = append(frames, frame{filePath: filePath, deps: deps})
 	}
 
-	for _, dep := range node.Dependencies {
-		if !visited[dep] {
-			if cycle := dg.dfsFindCycles(dep, visited, recursionStack, path); cycle != nil {
-				return cycle
+	for _, root := range dg.sortedPaths() {
+		if _, visited := indices[root]; visited {
+			continue
+		}
+		push(root)
+
+		for len(frames) > 0 {
+			top := &frames[len(frames)-1]
+			if top.next < len(top.deps) {
+				dep := top.deps[top.next]
+				top.next++
+				if _, visited := indices[dep]; !visited {
+					push(dep)
+				} else if onStack[dep] {
+					lowLinks[top.filePath] = min(lowLinks[top.filePath], indices[dep])
+				}
+				continue
+			}
+
+			// Every dependency has been followed, so the file is done
+			filePath := top.filePath
+			frames = frames[:len(frames)-1]
+			if len(frames) > 0 {
+				parent := frames[len(frames)-1].filePath
+				lowLinks[parent] = min(lowLinks[parent], lowLinks[filePath])
 			}
-		} else if recursionStack[dep] {
-			// Found a cycle, extract the cycle path
-			cycleStart := -1
-			for i, p := range path {
-				if p == dep {
-					cycleStart = i
+			if lowLinks[filePath] != indices[filePath] {
+				continue
+			}
+
+			var component []string
+			for {
+				member := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[member] = false
+				component = append(component, member)
+				if member == filePath {
 					break
 				}
 			}
-			if cycleStart >= 0 {
-				cycle := make([]string, len(path)-cycleStart)
-				copy(cycle, path[cycleStart:])
-				return cycle
+
+			if len(component) > 1 || dg.dependsOn(filePath, filePath) {
+				sort.Strings(component)
+				components = append(components, component)
 			}
 		}
 	}
 
-	recursionStack[filePath] = false
-	return nil
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+	return components
+}
+
+// cyclesIn returns the cycle closed by each back edge of a depth-first search
+// through a strongly connected component, starting at its first member. Every cycle
+// starts at its smallest member, and one that is a rotation of a cycle already found
+// is dropped. Like stronglyConnectedComponents, the search keeps its own stack
+// (not thread-safe, caller must lock)
+func (dg *DependencyGraph) cyclesIn(component []string) [][]string {
+	if len(component) == 0 {
+		return nil
+	}
+
+	members := make(map[string]bool, len(component))
+	for _, member := range component {
+		members[member] = true
+	}
+
+	const (
+		onPath = iota + 1
+		done
+	)
+	state := make(map[string]int, len(component))
+	seen := make(map[string]bool)
+	var cycles [][]string
+
+	// path holds the files being visited, and frames the dependencies each has left
+	type frame struct {
+		deps []string
+		next int
+	}
+	var path []string
+	var frames []frame
+	push := func(filePath string) {
+		state[filePath] = onPath
+		path = append(path, filePath)
+		var deps []string
+		for _, dep := range sortedCopy(uniqueStrings(dg.nodes[filePath].Dependencies)) {
+			if members[dep] {
+				deps = append(deps, dep)
+			}
+		}
+		frames = append(frames, frame{deps: deps})
+	}
+
+	push(component[0])
+	for len(frames) > 0 {
+		top := &frames[len(frames)-1]
+		if top.next == len(top.deps) {
+			state[path[len(path)-1]] = done
+			path = path[:len(path)-1]
+			frames = frames[:len(frames)-1]
+			continue
+		}
+
+		dep := top.deps[top.next]
+		top.next++
+		switch state[dep] {
+		case onPath:
+			cycle := canonicalCycle(path[slices.Index(path, dep):])
+			key := strings.Join(cycle, "\x00")
+			if !seen[key] {
+				seen[key] = true
+				cycles = append(cycles, cycle)
+			}
+		case 0:
+			push(dep)
+		}
+	}
+
+	return cycles
+}
+
+// canonicalCycle returns a copy of cycle rotated to start at its smallest member, so
+// rotations of the same cycle compare equal
+func canonicalCycle(cycle []string) []string {
+	start := 0
+	for i, filePath := range cycle {
+		if filePath < cycle[start] {
+			start = i
+		}
+	}
+	return append(slices.Clone(cycle[start:]), cycle[:start]...)
+}
+
+// dependsOn reports whether from directly depends on to (not thread-safe, caller must lock)
+func (dg *DependencyGraph) dependsOn(from, to string) bool {
+	node, exists := dg.nodes[from]
+	if !exists {
+		return false
+	}
+	for _, dep := range node.Dependencies {
+		if dep == to {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueStrings returns the distinct values of a slice
+func uniqueStrings(slice []string) []string {
+	seen := make(map[string]bool, len(slice))
+	var result []string
+	for _, s := range slice {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// sortedCopy returns a sorted copy of a slice
+func sortedCopy(slice []string) []string {
+	result := make([]string, len(slice))
+	copy(result, slice)
+	sort.Strings(result)
+	return result
 }
 
 // removeFromSlice removes a string from a slice
@@ -0,0 +1,127 @@
+package layers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectCyclesFindsDisjointCycles(t *testing.T) {
+	dg := NewDependencyGraph()
+	for filePath, deps := range map[string][]string{
+		"a.go": {"b.go"},
+		"b.go": {"a.go"},
+		"c.go": {"d.go"},
+		"d.go": {"e.go"},
+		"e.go": {"c.go"},
+	} {
+		if err := dg.UpdateNode(filePath, deps); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cycles, err := dg.DetectCycles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"a.go", "b.go"}, {"c.go", "d.go", "e.go"}}
+	if !reflect.DeepEqual(cycles, want) {
+		t.Errorf("DetectCycles() = %v, want %v", cycles, want)
+	}
+}
+
+func TestDetectCyclesReportsOverlappingComponent(t *testing.T) {
+	// A -> B -> C -> A and A <-> C form one component; only the first cycle is listed
+	dg := NewDependencyGraph()
+	for filePath, deps := range map[string][]string{
+		"a.go": {"b.go", "c.go"},
+		"b.go": {"c.go"},
+		"c.go": {"a.go"},
+	} {
+		if err := dg.UpdateNode(filePath, deps); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cycles, err := dg.DetectCycles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"a.go", "b.go", "c.go"}}
+	if !reflect.DeepEqual(cycles, want) {
+		t.Errorf("DetectCycles() = %v, want %v", cycles, want)
+	}
+}
+
+func TestDetectCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph map[string][]string
+		want  [][]string
+	}{
+		{
+			name:  "chain",
+			graph: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {}},
+			want:  nil,
+		},
+		{
+			name:  "self dependency",
+			graph: map[string][]string{"a": {"a"}, "b": {"a"}},
+			want:  [][]string{{"a"}},
+		},
+		{
+			name:  "tail into a cycle",
+			graph: map[string][]string{"x": {"a"}, "a": {"b"}, "b": {"a"}},
+			want:  [][]string{{"a", "b"}},
+		},
+		{
+			name: "disjoint cycles ordered by smallest member",
+			graph: map[string][]string{
+				"m": {"n"}, "n": {"m"},
+				"a": {"b"}, "b": {"a"},
+				"x": {"y"}, "y": {"z"}, "z": {"x"},
+			},
+			want: [][]string{{"a", "b"}, {"m", "n"}, {"x", "y", "z"}},
+		},
+		{
+			name:  "cycles sharing a file",
+			graph: map[string][]string{"a": {"b"}, "b": {"a", "c"}, "c": {"b"}},
+			want:  [][]string{{"a", "b"}, {"b", "c"}},
+		},
+		{
+			name: "cycle nested in a larger one",
+			graph: map[string][]string{
+				"a": {"b"}, "b": {"a", "c"},
+				"c": {"d"}, "d": {"b"},
+			},
+			want: [][]string{{"a", "b"}, {"b", "c", "d"}},
+		},
+		{
+			name: "duplicate edges",
+			graph: map[string][]string{
+				"a": {"b", "b"}, "b": {"a", "a"},
+			},
+			want: [][]string{{"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dg := NewDependencyGraph()
+			for filePath, deps := range tt.graph {
+				if err := dg.UpdateNode(filePath, deps); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// Every run must report the same cycles in the same order
+			for range 10 {
+				cycles, err := dg.DetectCycles()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(cycles, tt.want) {
+					t.Fatalf("DetectCycles() = %v, want %v", cycles, tt.want)
+				}
+			}
+		})
+	}
+}
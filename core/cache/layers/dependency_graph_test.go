@@ -0,0 +1,166 @@
+package layers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+)
+
+// newGraph builds a graph from a map of each file to the files it depends on
+func newGraph(t *testing.T, dependencies map[string][]string) *DependencyGraph {
+	t.Helper()
+	dg := NewDependencyGraph()
+	for filePath, deps := range dependencies {
+		if err := dg.UpdateNode(filePath, deps); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dg
+}
+
+func TestDiamondHasNoDuplicates(t *testing.T) {
+	// top depends on left and right, which both depend on base
+	dg := newGraph(t, map[string][]string{
+		"top":   {"left", "right"},
+		"left":  {"base"},
+		"right": {"base"},
+		"base":  {},
+	})
+
+	affected, err := dg.GetAffectedFiles("base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"left", "right", "top"}; !slices.Equal(affected, want) {
+		t.Errorf("GetAffectedFiles(base) = %v, want %v", affected, want)
+	}
+
+	dependencies, err := dg.GetTransitiveDependencies("top")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"left", "right", "base"}; !slices.Equal(dependencies, want) {
+		t.Errorf("GetTransitiveDependencies(top) = %v, want %v", dependencies, want)
+	}
+
+	if cycles, _ := dg.DetectCycles(); len(cycles) != 0 {
+		t.Errorf("DetectCycles() = %v, want none", cycles)
+	}
+}
+
+// chainNode names the files of a long chain so they sort in chain order
+func chainNode(i int) string {
+	return fmt.Sprintf("file%05d.go", i)
+}
+
+func TestLongChain(t *testing.T) {
+	const length = 10000
+	dg := NewDependencyGraph()
+	// Each file depends on the one before it
+	for i := 1; i < length; i++ {
+		if err := dg.UpdateNode(chainNode(i), []string{chainNode(i - 1)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	affected, err := dg.GetAffectedFiles(chainNode(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(affected) != length-1 || affected[0] != chainNode(1) || affected[len(affected)-1] != chainNode(length-1) {
+		t.Fatalf("GetAffectedFiles of the chain's start returned %d files, want %d in chain order", len(affected), length-1)
+	}
+
+	if cycles, _ := dg.DetectCycles(); len(cycles) != 0 {
+		t.Fatalf("DetectCycles() on a chain found %d cycles, want none", len(cycles))
+	}
+
+	// Closing the chain makes one cycle through every file
+	if err := dg.UpdateNode(chainNode(0), []string{chainNode(length - 1)}); err != nil {
+		t.Fatal(err)
+	}
+	cycles, err := dg.DetectCycles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cycles) != 1 || len(cycles[0]) != length || cycles[0][0] != chainNode(0) {
+		t.Fatalf("DetectCycles() on a closed chain = %d cycles, want one through all %d files", len(cycles), length)
+	}
+}
+
+// topologicalFixture has several files free to come first and a dependency outside the graph
+var topologicalFixture = map[string][]string{
+	"routes/users.go":    {"repo/users.go", "shared/log.go", "net/http"},
+	"routes/orgs.go":     {"repo/orgs.go", "shared/log.go"},
+	"routes/health.go":   {"net/http"},
+	"repo/users.go":      {"db/conn.go"},
+	"repo/orgs.go":       {"db/conn.go", "repo/users.go"},
+	"db/conn.go":         {},
+	"shared/log.go":      {},
+	"shared/unused.go":   {},
+	"routes/profiles.go": {"repo/users.go"},
+}
+
+func TestGetTopologicalOrderIsStable(t *testing.T) {
+	var first []string
+	for run := range 100 {
+		// Each graph is built in a different map order
+		order, err := newGraph(t, topologicalFixture).GetTopologicalOrder()
+		if err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+		if first == nil {
+			first = order
+			continue
+		}
+		if !slices.Equal(order, first) {
+			t.Fatalf("run %d: GetTopologicalOrder() = %v, want %v as on the first run", run, order, first)
+		}
+	}
+
+	position := make(map[string]int, len(first))
+	for i, filePath := range first {
+		if _, repeated := position[filePath]; repeated {
+			t.Fatalf("%s is ordered twice in %v", filePath, first)
+		}
+		position[filePath] = i
+	}
+	for filePath := range topologicalFixture {
+		if _, ordered := position[filePath]; !ordered {
+			t.Errorf("%s is missing from %v", filePath, first)
+		}
+	}
+	for filePath, deps := range topologicalFixture {
+		for _, dep := range deps {
+			if at, inGraph := position[dep]; inGraph && at > position[filePath] {
+				t.Errorf("%s comes before its dependency %s in %v", filePath, dep, first)
+			}
+		}
+	}
+}
+
+func TestGetTopologicalOrderWithCycle(t *testing.T) {
+	// a and b depend on each other, c is behind the cycle and d is independent of it
+	dg := newGraph(t, map[string][]string{
+		"a.go":    {"b.go", "base.go"},
+		"b.go":    {"a.go"},
+		"c.go":    {"a.go"},
+		"d.go":    {"base.go"},
+		"base.go": {},
+	})
+
+	order, err := dg.GetTopologicalOrder()
+	var cycleErr *models.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("GetTopologicalOrder() error = %v, want a *models.CycleError", err)
+	}
+	if want := []string{"base.go", "d.go"}; !slices.Equal(order, want) {
+		t.Errorf("partial order = %v, want %v", order, want)
+	}
+	if want := []string{"a.go", "b.go", "c.go"}; !slices.Equal(cycleErr.Remaining, want) {
+		t.Errorf("remaining = %v, want %v", cycleErr.Remaining, want)
+	}
+}
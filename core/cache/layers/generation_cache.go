@@ -1,8 +1,8 @@
 package layers
 
 import (
-	"crypto/md5"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 	"sync"
@@ -12,7 +12,8 @@ import (
 	"github.com/tristendillon/conduit/core/logger"
 )
 
-// GenerationCache implements Layer 4: Generation state tracking
+// GenerationCache implements Layer 4: Generation state tracking, keyed by the canonical
+// path of each source
 type GenerationCache struct {
 	entries map[string]*models.GenerationInfo
 	mutex   sync.RWMutex
@@ -32,15 +33,21 @@ func (gc *GenerationCache) MarkGenerated(sourcePath, outputPath, sourceHash, tem
 		return fmt.Errorf("source path and output path cannot be empty")
 	}
 
-	gc.mutex.Lock()
-	defer gc.mutex.Unlock()
-
 	// Calculate combined dependency hash
 	depHash := gc.calculateDependencyHash(dependencies)
 
+	// Record the output hash so external edits or deletions can be detected later. The file
+	// is hashed before locking so other lookups aren't blocked on the read.
+	outputHash, err := models.HashFile(outputPath)
+	if err != nil {
+		logger.Debug("GenerationCache: Failed to hash output %s: %v", outputPath, err)
+		outputHash = ""
+	}
+
 	entry := &models.GenerationInfo{
 		SourcePath:     sourcePath,
 		OutputPath:     outputPath,
+		OutputHash:     outputHash,
 		SourceHash:     sourceHash,
 		TemplateHash:   templateHash,
 		DependencyHash: depHash,
@@ -48,13 +55,16 @@ func (gc *GenerationCache) MarkGenerated(sourcePath, outputPath, sourceHash, tem
 		ConfigHash:     configHash,
 	}
 
-	gc.entries[sourcePath] = entry
+	gc.mutex.Lock()
+	defer gc.mutex.Unlock()
+	gc.entries[models.CanonicalPath(sourcePath)] = entry
 	logger.Debug("GenerationCache: Marked %s as generated (output: %s)", sourcePath, outputPath)
 	return nil
 }
 
 // NeedsRegeneration checks if file needs regeneration
 func (gc *GenerationCache) NeedsRegeneration(sourcePath string, currentHash string, dependencies []string) (bool, string, error) {
+	sourcePath = models.CanonicalPath(sourcePath)
 	gc.mutex.RLock()
 	defer gc.mutex.RUnlock()
 
@@ -66,7 +76,7 @@ func (gc *GenerationCache) NeedsRegeneration(sourcePath string, currentHash stri
 	// Check if source content changed
 	if entry.SourceHash != currentHash {
 		return true, fmt.Sprintf("source content changed (hash: %s -> %s)",
-			entry.SourceHash[:8], currentHash[:8]), nil
+			models.ShortHash(entry.SourceHash), models.ShortHash(currentHash)), nil
 	}
 
 	// Check if dependencies changed
@@ -75,10 +85,14 @@ func (gc *GenerationCache) NeedsRegeneration(sourcePath string, currentHash stri
 		return true, "dependencies changed", nil
 	}
 
+	// Check if the output file was deleted or edited outside of conduit
+	if needs, reason := gc.checkOutput(entry); needs {
+		return true, reason, nil
+	}
+
 	// TODO: In a real implementation, you'd also check:
 	// - Template version changes
 	// - Config changes
-	// - Output file existence/modification
 
 	logger.Debug("GenerationCache: %s does not need regeneration", sourcePath)
 	return false, "", nil
@@ -86,6 +100,7 @@ func (gc *GenerationCache) NeedsRegeneration(sourcePath string, currentHash stri
 
 // GetGenerationInfo retrieves generation metadata
 func (gc *GenerationCache) GetGenerationInfo(sourcePath string) (*models.GenerationInfo, bool) {
+	sourcePath = models.CanonicalPath(sourcePath)
 	gc.mutex.RLock()
 	defer gc.mutex.RUnlock()
 
@@ -98,6 +113,7 @@ func (gc *GenerationCache) GetGenerationInfo(sourcePath string) (*models.Generat
 	entryCopy := &models.GenerationInfo{
 		SourcePath:     entry.SourcePath,
 		OutputPath:     entry.OutputPath,
+		OutputHash:     entry.OutputHash,
 		SourceHash:     entry.SourceHash,
 		TemplateHash:   entry.TemplateHash,
 		DependencyHash: entry.DependencyHash,
@@ -108,8 +124,40 @@ func (gc *GenerationCache) GetGenerationInfo(sourcePath string) (*models.Generat
 	return entryCopy, true
 }
 
+// GetAllGenerations returns a copy of every generation record, keyed by source path
+func (gc *GenerationCache) GetAllGenerations() map[string]*models.GenerationInfo {
+	gc.mutex.RLock()
+	defer gc.mutex.RUnlock()
+
+	entries := make(map[string]*models.GenerationInfo, len(gc.entries))
+	for sourcePath, entry := range gc.entries {
+		entryCopy := *entry
+		entries[sourcePath] = &entryCopy
+	}
+	return entries
+}
+
+// RestoreGeneration adds a record persisted by a previous run. Records made during this
+// run are newer, so an existing record for the source is kept.
+func (gc *GenerationCache) RestoreGeneration(info *models.GenerationInfo) {
+	if info == nil || info.SourcePath == "" {
+		return
+	}
+
+	gc.mutex.Lock()
+	defer gc.mutex.Unlock()
+
+	key := models.CanonicalPath(info.SourcePath)
+	if _, exists := gc.entries[key]; exists {
+		return
+	}
+	entryCopy := *info
+	gc.entries[key] = &entryCopy
+}
+
 // InvalidateGeneration marks file as needing regeneration
 func (gc *GenerationCache) InvalidateGeneration(sourcePath string) error {
+	sourcePath = models.CanonicalPath(sourcePath)
 	gc.mutex.Lock()
 	defer gc.mutex.Unlock()
 
@@ -180,6 +228,7 @@ func (gc *GenerationCache) GetGeneratedFiles() []string {
 
 // GetGenerationAge returns how long ago a file was generated
 func (gc *GenerationCache) GetGenerationAge(sourcePath string) (time.Duration, error) {
+	sourcePath = models.CanonicalPath(sourcePath)
 	gc.mutex.RLock()
 	defer gc.mutex.RUnlock()
 
@@ -193,6 +242,31 @@ func (gc *GenerationCache) GetGenerationAge(sourcePath string) (time.Duration, e
 
 // Helper methods
 
+// checkOutput verifies the recorded output file still exists with the content we wrote
+func (gc *GenerationCache) checkOutput(entry *models.GenerationInfo) (bool, string) {
+	if _, err := os.Stat(entry.OutputPath); err != nil {
+		if os.IsNotExist(err) {
+			return true, fmt.Sprintf("output missing: %s", entry.OutputPath)
+		}
+		return true, fmt.Sprintf("output not readable: %s (%v)", entry.OutputPath, err)
+	}
+
+	// Entries recorded without an output hash can only be checked for existence
+	if entry.OutputHash == "" {
+		return false, ""
+	}
+
+	currentHash, err := models.HashFile(entry.OutputPath)
+	if err != nil {
+		return true, fmt.Sprintf("output not readable: %s (%v)", entry.OutputPath, err)
+	}
+	if currentHash != entry.OutputHash {
+		return true, fmt.Sprintf("output modified externally: %s", entry.OutputPath)
+	}
+
+	return false, ""
+}
+
 // calculateDependencyHash creates a stable hash from dependency list
 func (gc *GenerationCache) calculateDependencyHash(dependencies []string) string {
 	if len(dependencies) == 0 {
@@ -206,8 +280,7 @@ func (gc *GenerationCache) calculateDependencyHash(dependencies []string) string
 
 	// Create hash from sorted dependencies
 	combined := strings.Join(sorted, "|")
-	hash := md5.Sum([]byte(combined))
-	return fmt.Sprintf("%x", hash)
+	return models.HashBytes([]byte(combined))
 }
 
 // UpdateTemplateHash updates the template hash for all entries
@@ -0,0 +1,49 @@
+package layers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNeedsRegenerationWithEmptyHashes(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "gen_route.go")
+	if err := os.WriteFile(output, []byte("package route\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		recorded   string
+		current    string
+		wantRegen  bool
+		wantReason string
+		unrecorded bool
+	}{
+		{name: "no record", current: "", unrecorded: true, wantRegen: true, wantReason: "no generation record found"},
+		{name: "empty recorded hash", recorded: "", current: "0123456789abcdef", wantRegen: true, wantReason: "source content changed (hash: none -> 01234567)"},
+		{name: "empty current hash", recorded: "0123456789abcdef", current: "", wantRegen: true, wantReason: "source content changed (hash: 01234567 -> none)"},
+		{name: "short hashes", recorded: "abc", current: "abd", wantRegen: true, wantReason: "source content changed (hash: abc -> abd)"},
+		{name: "both empty", recorded: "", current: "", wantRegen: false, wantReason: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc := NewGenerationCache()
+			source := filepath.Join(t.TempDir(), "route.go")
+			if !tt.unrecorded {
+				if err := gc.MarkGenerated(source, output, tt.recorded, "templates", "config", nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			needsRegen, reason, err := gc.NeedsRegeneration(source, tt.current, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if needsRegen != tt.wantRegen || reason != tt.wantReason {
+				t.Errorf("NeedsRegeneration() = %t, %q, want %t, %q", needsRegen, reason, tt.wantRegen, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package layers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+)
+
+// nodeColors maps node types to Graphviz fill colors
+var nodeColors = map[models.NodeType]string{
+	models.SourceFile:    "lightblue",
+	models.GeneratedFile: "lightgray",
+	models.TemplateFile:  "lightyellow",
+	models.ConfigFile:    "palegreen",
+}
+
+// ExportDOT writes the graph as a Graphviz digraph with edges from dependents to dependencies
+func (dg *DependencyGraph) ExportDOT(w io.Writer) error {
+	dg.mutex.RLock()
+	defer dg.mutex.RUnlock()
+
+	cycleEdges := dg.cycleEdges()
+
+	if _, err := fmt.Fprintln(w, "digraph dependencies {"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "\trankdir=LR;"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "\tnode [shape=box, style=filled];"); err != nil {
+		return err
+	}
+
+	paths := dg.sortedPaths()
+	labels := nodeLabels(paths)
+	for _, filePath := range paths {
+		node := dg.nodes[filePath]
+		color, ok := nodeColors[node.NodeType]
+		if !ok {
+			color = "white"
+		}
+		if _, err := fmt.Fprintf(w, "\t%q [label=%q, fillcolor=%q, tooltip=%q];\n",
+			filePath, labels[filePath], color, filePath+" ("+node.NodeType.String()+")"); err != nil {
+			return err
+		}
+	}
+
+	for _, filePath := range paths {
+		dependencies := make([]string, len(dg.nodes[filePath].Dependencies))
+		copy(dependencies, dg.nodes[filePath].Dependencies)
+		sort.Strings(dependencies)
+
+		for _, dep := range dependencies {
+			attrs := ""
+			if cycleEdges[filePath+"\x00"+dep] {
+				attrs = " [color=red, penwidth=2]"
+			}
+			if _, err := fmt.Fprintf(w, "\t%q -> %q%s;\n", filePath, dep, attrs); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// ExportMermaid writes the graph as a top-down Mermaid flowchart with edges from
+// dependents to dependencies; edges inside a cycle are drawn in red
+func (dg *DependencyGraph) ExportMermaid(w io.Writer) error {
+	dg.mutex.RLock()
+	defer dg.mutex.RUnlock()
+
+	cycleEdges := dg.cycleEdges()
+
+	if _, err := fmt.Fprintln(w, "graph TD"); err != nil {
+		return err
+	}
+
+	// Paths aren't valid Mermaid ids, so nodes are numbered in path order
+	paths := dg.sortedPaths()
+	labels := nodeLabels(paths)
+	ids := make(map[string]string, len(paths))
+	for i, filePath := range paths {
+		ids[filePath] = fmt.Sprintf("n%d", i)
+		if _, err := fmt.Fprintf(w, "    %s[\"%s\"]\n", ids[filePath], mermaidEscape(labels[filePath])); err != nil {
+			return err
+		}
+	}
+
+	var redLinks []string
+	link := 0
+	for _, filePath := range paths {
+		dependencies := make([]string, len(dg.nodes[filePath].Dependencies))
+		copy(dependencies, dg.nodes[filePath].Dependencies)
+		sort.Strings(dependencies)
+
+		for _, dep := range dependencies {
+			// Dependencies outside the graph still need a node to point at
+			if _, exists := ids[dep]; !exists {
+				ids[dep] = fmt.Sprintf("n%d", len(ids))
+				if _, err := fmt.Fprintf(w, "    %s[\"%s\"]\n", ids[dep], mermaidEscape(filepath.Base(dep))); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(w, "    %s --> %s\n", ids[filePath], ids[dep]); err != nil {
+				return err
+			}
+			if cycleEdges[filePath+"\x00"+dep] {
+				redLinks = append(redLinks, fmt.Sprint(link))
+			}
+			link++
+		}
+	}
+
+	if len(redLinks) > 0 {
+		if _, err := fmt.Fprintf(w, "    linkStyle %s stroke:red,stroke-width:2px\n", strings.Join(redLinks, ",")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mermaidEscape makes a label safe inside a quoted Mermaid node label
+func mermaidEscape(label string) string {
+	return strings.ReplaceAll(label, `"`, "#quot;")
+}
+
+// nodeLabels labels each path with its base name, adding parent directories until
+// labels are unique (every route file is named route.go)
+func nodeLabels(paths []string) map[string]string {
+	labels := make(map[string]string, len(paths))
+	for depth := 1; ; depth++ {
+		counts := make(map[string]int)
+		for _, filePath := range paths {
+			if _, done := labels[filePath]; !done {
+				counts[pathSuffix(filePath, depth)]++
+			}
+		}
+
+		remaining := false
+		for _, filePath := range paths {
+			if _, done := labels[filePath]; done {
+				continue
+			}
+			suffix := pathSuffix(filePath, depth)
+			if counts[suffix] == 1 || suffix == filePath {
+				labels[filePath] = suffix
+			} else {
+				remaining = true
+			}
+		}
+		if !remaining {
+			return labels
+		}
+	}
+}
+
+// pathSuffix returns the last n elements of a slash or OS separated path
+func pathSuffix(filePath string, n int) string {
+	parts := strings.Split(filepath.ToSlash(filePath), "/")
+	if n >= len(parts) {
+		return filePath
+	}
+	return strings.Join(parts[len(parts)-n:], "/")
+}
+
+// ExportJSON writes the graph as JSON for external tooling
+func (dg *DependencyGraph) ExportJSON(w io.Writer) error {
+	dg.mutex.RLock()
+	defer dg.mutex.RUnlock()
+
+	type jsonNode struct {
+		FilePath     string        `json:"file_path"`
+		NodeType     string        `json:"node_type"`
+		Dependencies []string      `json:"dependencies"`
+		Edges        []models.Edge `json:"edges,omitempty"`
+		Dependents   []string      `json:"dependents"`
+	}
+
+	export := struct {
+		Nodes  []jsonNode `json:"nodes"`
+		Cycles [][]string `json:"cycles"`
+	}{
+		Nodes:  []jsonNode{},
+		Cycles: dg.detectCycles(),
+	}
+	if export.Cycles == nil {
+		export.Cycles = [][]string{}
+	}
+
+	for _, filePath := range dg.sortedPaths() {
+		node := dg.nodes[filePath]
+		dependencies := append([]string{}, node.Dependencies...)
+		dependents := append([]string{}, node.Dependents...)
+		sort.Strings(dependencies)
+		sort.Strings(dependents)
+
+		export.Nodes = append(export.Nodes, jsonNode{
+			FilePath:     filePath,
+			NodeType:     node.NodeType.String(),
+			Dependencies: dependencies,
+			Edges:        node.Edges,
+			Dependents:   dependents,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(export)
+}
+
+// ToAdjacencyList returns a copy of the graph as dependent -> dependencies
+func (dg *DependencyGraph) ToAdjacencyList() map[string][]string {
+	dg.mutex.RLock()
+	defer dg.mutex.RUnlock()
+
+	result := make(map[string][]string, len(dg.nodes))
+	for filePath, node := range dg.nodes {
+		dependencies := make([]string, len(node.Dependencies))
+		copy(dependencies, node.Dependencies)
+		sort.Strings(dependencies)
+		result[filePath] = dependencies
+	}
+	return result
+}
+
+// sortedPaths returns node paths in stable order (not thread-safe, caller must lock)
+func (dg *DependencyGraph) sortedPaths() []string {
+	paths := make([]string, 0, len(dg.nodes))
+	for filePath := range dg.nodes {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// cycleEdges returns the set of edges between members of the same cyclic component (not thread-safe, caller must lock)
+func (dg *DependencyGraph) cycleEdges() map[string]bool {
+	edges := make(map[string]bool)
+	for _, component := range dg.stronglyConnectedComponents() {
+		members := make(map[string]bool, len(component))
+		for _, member := range component {
+			members[member] = true
+		}
+		for _, from := range component {
+			for _, to := range dg.nodes[from].Dependencies {
+				if members[to] {
+					edges[from+"\x00"+to] = true
+				}
+			}
+		}
+	}
+	return edges
+}
@@ -0,0 +1,56 @@
+package layers
+
+import (
+	"sync/atomic"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+)
+
+// hitCounter tracks lookups for a cache layer. Once the layer is marked warmed, lookups
+// also count toward the warm counters, which measure steady-state effectiveness
+// without the misses expected while the cache is first populated.
+type hitCounter struct {
+	hits       int64
+	misses     int64
+	warmHits   int64
+	warmMisses int64
+	warmed     atomic.Bool
+}
+
+func (hc *hitCounter) hit() {
+	atomic.AddInt64(&hc.hits, 1)
+	if hc.warmed.Load() {
+		atomic.AddInt64(&hc.warmHits, 1)
+	}
+}
+
+func (hc *hitCounter) miss() {
+	atomic.AddInt64(&hc.misses, 1)
+	if hc.warmed.Load() {
+		atomic.AddInt64(&hc.warmMisses, 1)
+	}
+}
+
+// markWarmed starts warm tracking; later calls have no effect
+func (hc *hitCounter) markWarmed() {
+	hc.warmed.Store(true)
+}
+
+func (hc *hitCounter) reset() {
+	atomic.StoreInt64(&hc.hits, 0)
+	atomic.StoreInt64(&hc.misses, 0)
+	atomic.StoreInt64(&hc.warmHits, 0)
+	atomic.StoreInt64(&hc.warmMisses, 0)
+	hc.warmed.Store(false)
+}
+
+// fill copies the counters and their hit rates into stats
+func (hc *hitCounter) fill(stats *models.CacheStats) {
+	stats.CacheHits = atomic.LoadInt64(&hc.hits)
+	stats.CacheMisses = atomic.LoadInt64(&hc.misses)
+	stats.HitRate = models.CalculateHitRate(stats.CacheHits, stats.CacheMisses)
+	stats.IsWarmed = hc.warmed.Load()
+	stats.WarmHits = atomic.LoadInt64(&hc.warmHits)
+	stats.WarmMisses = atomic.LoadInt64(&hc.warmMisses)
+	stats.WarmHitRate = models.CalculateHitRate(stats.WarmHits, stats.WarmMisses)
+}
@@ -1,6 +1,7 @@
 package layers
 
 import (
+	"container/list"
 	"fmt"
 	"sync"
 	"time"
@@ -10,61 +11,154 @@ import (
 	coreModels "github.com/tristendillon/conduit/core/models"
 )
 
-// ParseCache implements Layer 2: Parsed file data storage
+// ParseCache implements Layer 2: Parsed file data storage. When limits are configured the
+// least recently used entries are evicted; evicted files are simply parsed again on their next lookup.
 type ParseCache struct {
-	entries map[string]*coreModels.ParsedFile
-	mutex   sync.RWMutex
-	stats   struct {
-		hits   int64
-		misses int64
-	}
+	entries   map[string]*list.Element // values are *parseEntry
+	lru       *list.List               // front is the most recently used
+	config    models.ParseCacheConfig
+	bytes     int64
+	evictions int64
+	mutex     sync.RWMutex
+	stats     hitCounter
+}
+
+type parseEntry struct {
+	path   string
+	parsed *coreModels.ParsedFile
+	size   int64
 }
 
-// NewParseCache creates a new parse cache
+// NewParseCache creates a new parse cache without limits
 func NewParseCache() *ParseCache {
+	return NewParseCacheWithConfig(models.ParseCacheConfig{})
+}
+
+// NewParseCacheWithConfig creates a parse cache bounded by the given limits
+func NewParseCacheWithConfig(config models.ParseCacheConfig) *ParseCache {
 	return &ParseCache{
-		entries: make(map[string]*coreModels.ParsedFile),
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		config:  config,
 		mutex:   sync.RWMutex{},
 	}
 }
 
-// SetParsedFile stores parsed file data
+// SetParsedFile stores parsed file data under the file's canonical path; parsed keeps the
+// path it was parsed from
 func (pc *ParseCache) SetParsedFile(filePath string, parsed *coreModels.ParsedFile) error {
 	if parsed == nil {
 		return fmt.Errorf("parsed file cannot be nil")
 	}
 
+	key := models.CanonicalPath(filePath)
 	pc.mutex.Lock()
 	defer pc.mutex.Unlock()
 
-	pc.entries[filePath] = parsed
+	pc.remove(key)
+	entry := &parseEntry{path: key, parsed: parsed, size: estimateParsedSize(parsed)}
+	pc.entries[key] = pc.lru.PushFront(entry)
+	pc.bytes += entry.size
+	pc.evict()
+
 	logger.Debug("ParseCache: Stored parsed data for %s (methods: %v)", filePath, parsed.Methods)
 	return nil
 }
 
 // GetParsedFile retrieves parsed file data
 func (pc *ParseCache) GetParsedFile(filePath string) (*coreModels.ParsedFile, bool) {
-	pc.mutex.RLock()
-	defer pc.mutex.RUnlock()
+	key := models.CanonicalPath(filePath)
+	// A hit reorders the LRU list, so lookups take the write lock
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
 
-	parsed, exists := pc.entries[filePath]
-	if exists {
-		pc.stats.hits++
-		logger.Debug("ParseCache: Hit for %s", filePath)
-	} else {
-		pc.stats.misses++
+	element, exists := pc.entries[key]
+	if !exists {
+		pc.stats.miss()
 		logger.Debug("ParseCache: Miss for %s", filePath)
+		return nil, false
+	}
+
+	pc.lru.MoveToFront(element)
+	pc.stats.hit()
+	logger.Debug("ParseCache: Hit for %s", filePath)
+	return element.Value.(*parseEntry).parsed, true
+}
+
+// evict drops least recently used entries until the cache is within its limits, always
+// keeping the newest entry (not thread-safe, caller must lock)
+func (pc *ParseCache) evict() {
+	for pc.lru.Len() > 1 && pc.overLimit() {
+		entry := pc.lru.Back().Value.(*parseEntry)
+		pc.remove(entry.path)
+		pc.evictions++
+		logger.Debug("ParseCache: Evicted %s (%d entries, %d bytes left)", entry.path, pc.lru.Len(), pc.bytes)
 	}
-	return parsed, exists
+}
+
+func (pc *ParseCache) overLimit() bool {
+	if pc.config.MaxEntries > 0 && pc.lru.Len() > pc.config.MaxEntries {
+		return true
+	}
+	return pc.config.MaxBytes > 0 && pc.bytes > pc.config.MaxBytes
+}
+
+// remove drops the entry stored under key if present (not thread-safe, caller must lock)
+func (pc *ParseCache) remove(key string) bool {
+	element, exists := pc.entries[key]
+	if !exists {
+		return false
+	}
+	pc.bytes -= element.Value.(*parseEntry).size
+	pc.lru.Remove(element)
+	delete(pc.entries, key)
+	return true
+}
+
+// estimateParsedSize approximates the memory held by a parsed file from its strings,
+// which dominate it through the function bodies
+func estimateParsedSize(parsed *coreModels.ParsedFile) int64 {
+	size := len(parsed.Path) + len(parsed.RelPath) + len(parsed.PackageName) + len(parsed.DocComment)
+	for _, method := range parsed.Methods {
+		size += len(method)
+	}
+	for _, imp := range parsed.Imports {
+		size += len(imp)
+	}
+	for _, fn := range parsed.Functions {
+		size += len(fn.Name) + len(fn.Method) + len(fn.Signature) + len(fn.Body) +
+			len(fn.RequestType) + len(fn.ResponseType) + len(fn.DocComment) + len(fn.Description)
+		for _, middleware := range fn.Middlewares {
+			size += len(middleware)
+		}
+		for key, value := range fn.Annotations {
+			size += len(key) + len(value)
+		}
+	}
+	for _, decl := range parsed.Structs {
+		size += len(decl.Name)
+		for _, field := range decl.Fields {
+			size += len(field.Name) + len(field.JSONName) + len(field.Type.GoType)
+		}
+	}
+	if parsed.Dependencies != nil {
+		for _, local := range parsed.Dependencies.LocalImports {
+			size += len(local.ImportPath)
+		}
+		for _, external := range parsed.Dependencies.ExternalImports {
+			size += len(external)
+		}
+	}
+	return int64(size)
 }
 
 // InvalidateParse removes parsed data for a file
 func (pc *ParseCache) InvalidateParse(filePath string) error {
+	key := models.CanonicalPath(filePath)
 	pc.mutex.Lock()
 	defer pc.mutex.Unlock()
 
-	if _, exists := pc.entries[filePath]; exists {
-		delete(pc.entries, filePath)
+	if pc.remove(key) {
 		logger.Debug("ParseCache: Invalidated parsed data for %s", filePath)
 	}
 	return nil
@@ -72,13 +166,15 @@ func (pc *ParseCache) InvalidateParse(filePath string) error {
 
 // GetDependencies extracts dependency information from parsed data
 func (pc *ParseCache) GetDependencies(filePath string) ([]string, error) {
+	key := models.CanonicalPath(filePath)
 	pc.mutex.RLock()
 	defer pc.mutex.RUnlock()
 
-	parsed, exists := pc.entries[filePath]
+	element, exists := pc.entries[key]
 	if !exists {
 		return nil, fmt.Errorf("no parsed data found for %s", filePath)
 	}
+	parsed := element.Value.(*parseEntry).parsed
 
 	var dependencies []string
 
@@ -103,19 +199,18 @@ func (pc *ParseCache) GetStats() *models.CacheStats {
 	pc.mutex.RLock()
 	defer pc.mutex.RUnlock()
 
-	total := pc.stats.hits + pc.stats.misses
-	hitRate := 0.0
-	if total > 0 {
-		hitRate = float64(pc.stats.hits) / float64(total) * 100
+	stats := &models.CacheStats{
+		TotalFiles: len(pc.entries),
+		LastUpdate: time.Now(),
+		Evictions:  pc.evictions,
 	}
+	pc.stats.fill(stats)
+	return stats
+}
 
-	return &models.CacheStats{
-		TotalFiles:  len(pc.entries),
-		CacheHits:   pc.stats.hits,
-		CacheMisses: pc.stats.misses,
-		HitRate:     hitRate,
-		LastUpdate:  time.Now(),
-	}
+// MarkWarmed starts counting lookups toward the warm hit rate
+func (pc *ParseCache) MarkWarmed() {
+	pc.stats.markWarmed()
 }
 
 // Clear removes all entries
@@ -123,9 +218,11 @@ func (pc *ParseCache) Clear() error {
 	pc.mutex.Lock()
 	defer pc.mutex.Unlock()
 
-	pc.entries = make(map[string]*coreModels.ParsedFile)
-	pc.stats.hits = 0
-	pc.stats.misses = 0
+	pc.entries = make(map[string]*list.Element)
+	pc.lru.Init()
+	pc.bytes = 0
+	pc.evictions = 0
+	pc.stats.reset()
 	logger.Debug("ParseCache: Cleared all entries")
 	return nil
 }
@@ -137,8 +234,8 @@ func (pc *ParseCache) GetAllParsedFiles() map[string]*coreModels.ParsedFile {
 
 	// Return a copy to avoid concurrent modification issues
 	result := make(map[string]*coreModels.ParsedFile)
-	for path, parsed := range pc.entries {
-		result[path] = parsed
+	for path, element := range pc.entries {
+		result[path] = element.Value.(*parseEntry).parsed
 	}
 	return result
 }
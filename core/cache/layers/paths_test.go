@@ -0,0 +1,127 @@
+package layers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+	coreModels "github.com/tristendillon/conduit/core/models"
+)
+
+// spellings returns several paths to the same file: the plain one, with repeated and
+// dot separators, and through a symlinked directory
+func spellings(t *testing.T) (string, []string) {
+	t.Helper()
+	dir := t.TempDir()
+	routes := filepath.Join(dir, "routes")
+	if err := os.Mkdir(routes, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(routes, "route.go")
+	if err := os.WriteFile(file, []byte("package routes\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "linked")
+	if err := os.Symlink(routes, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	return file, []string{
+		file,
+		dir + "//routes/./route.go",
+		filepath.Join(dir, "routes", "..", "routes", "route.go"),
+		filepath.Join(link, "route.go"),
+	}
+}
+
+func TestContentCacheCanonicalizesPaths(t *testing.T) {
+	file, paths := spellings(t)
+	cc := NewContentCache()
+
+	for i, path := range paths {
+		entry, changed, err := cc.UpdateContent(path)
+		if err != nil {
+			t.Fatalf("UpdateContent(%s): %v", path, err)
+		}
+		if changed != (i == 0) {
+			t.Errorf("UpdateContent(%s) changed = %t, want %t", path, changed, i == 0)
+		}
+		if entry.FilePath != paths[0] {
+			t.Errorf("UpdateContent(%s) entry path = %s, want the first caller's %s", path, entry.FilePath, paths[0])
+		}
+		if _, exists := cc.GetContent(path); !exists {
+			t.Errorf("GetContent(%s) missed", path)
+		}
+	}
+
+	if files := cc.ListFiles(); len(files) != 1 || files[0] != models.CanonicalPath(file) {
+		t.Fatalf("ListFiles() = %v, want the single canonical path", files)
+	}
+
+	if err := cc.RemoveContent(paths[len(paths)-1]); err != nil {
+		t.Fatal(err)
+	}
+	if files := cc.ListFiles(); len(files) != 0 {
+		t.Fatalf("ListFiles() after removing through a symlink = %v, want none", files)
+	}
+}
+
+func TestParseCacheCanonicalizesPaths(t *testing.T) {
+	_, paths := spellings(t)
+	pc := NewParseCache()
+
+	parsed := &coreModels.ParsedFile{Path: paths[1]}
+	if err := pc.SetParsedFile(paths[1], parsed); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		got, exists := pc.GetParsedFile(path)
+		if !exists || got != parsed {
+			t.Errorf("GetParsedFile(%s) = %v, %t, want the stored file", path, got, exists)
+		}
+	}
+	if got := pc.GetFilesCount(); got != 1 {
+		t.Fatalf("GetFilesCount() = %d, want 1", got)
+	}
+	if got := pc.GetAllParsedFiles()[paths[1]]; got != nil {
+		t.Errorf("the entry is stored under the caller's spelling %s, want the canonical path", paths[1])
+	}
+
+	pc.InvalidateParse(paths[3])
+	if got := pc.GetFilesCount(); got != 0 {
+		t.Fatalf("GetFilesCount() after invalidating through a symlink = %d, want 0", got)
+	}
+}
+
+func TestDependencyGraphCanonicalizesPaths(t *testing.T) {
+	file, paths := spellings(t)
+	dependent := filepath.Join(filepath.Dir(file), "other.go")
+	dg := NewDependencyGraph()
+
+	for _, path := range paths {
+		if err := dg.UpdateNode(dependent, []string{path}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := dg.UpdateNode(paths[3], []string{"github.com/example/pkg"}); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := dg.GetAllNodes()
+	if len(nodes) != 3 {
+		t.Fatalf("graph has %d nodes, want the file, its dependent and the import: %v", len(nodes), dg.sortedPaths())
+	}
+	for _, path := range paths {
+		affected, err := dg.GetAffectedFiles(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(affected) != 1 || affected[0] != models.CanonicalPath(dependent) {
+			t.Errorf("GetAffectedFiles(%s) = %v, want [%s]", path, affected, dependent)
+		}
+	}
+	if _, exists := dg.GetNode("github.com/example/pkg"); !exists {
+		t.Error("import path node was rewritten as a file path")
+	}
+}
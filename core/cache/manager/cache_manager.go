@@ -1,7 +1,7 @@
 package manager
 
 import (
-	"crypto/md5"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/tristendillon/conduit/core/ast"
 	"github.com/tristendillon/conduit/core/cache/layers"
 	"github.com/tristendillon/conduit/core/cache/models"
 	coreModels "github.com/tristendillon/conduit/core/models"
@@ -22,6 +23,10 @@ type CacheManager struct {
 	deps            models.DependencyGraphInterface
 	generation      models.GenerationCacheInterface
 	registrySignature *models.RegistrySignature
+	templateHash      string
+	previousState     *models.PersistedState
+	renames           renameTracker
+	events            eventBus
 }
 
 // NewCacheManager creates a new cache manager with default implementations
@@ -34,6 +39,15 @@ func NewCacheManager() *CacheManager {
 	}
 }
 
+// NewCacheManagerWithConfig creates a cache manager with default implementations whose
+// content and parse caches use the given limits
+func NewCacheManagerWithConfig(config models.CacheConfig) *CacheManager {
+	cm := NewCacheManager()
+	cm.content = layers.NewContentCacheWithConfig(config.Content)
+	cm.parse = layers.NewParseCacheWithConfig(config.Parse)
+	return cm
+}
+
 // NewCacheManagerWithLayers creates a cache manager with custom layer implementations
 func NewCacheManagerWithLayers(
 	content models.ContentCacheInterface,
@@ -51,24 +65,140 @@ func NewCacheManagerWithLayers(
 
 // HandleFileChange processes a file system change event
 func (cm *CacheManager) HandleFileChange(event *models.ChangeEvent) (*models.RegenerationPlan, error) {
+	canonicalizeEvent(event)
 	logger.Debug("CacheManager: Handling file change: %s (%s)", event.FilePath, event.EventType)
 
-	plan := &models.RegenerationPlan{
-		ChangedFiles:    []string{event.FilePath},
-		AffectedFiles:   []string{},
-		RegenerationMap: make(map[string][]string),
-		Reasons:         make(map[string]string),
-		Priority:        make(map[string]int),
-	}
+	plan := newRegenerationPlan()
+	plan.ChangedFiles = []string{event.FilePath}
 
+	var err error
 	switch event.EventType {
 	case "delete":
-		return cm.handleFileDelete(event, plan)
+		plan, err = cm.handleFileDelete(event, plan)
 	case "write", "create":
-		return cm.handleFileChange(event, plan)
+		plan, err = cm.handleFileChange(event, plan)
+	case "rename":
+		plan, err = cm.handleFileRename(event, plan)
 	default:
 		return plan, fmt.Errorf("unknown event type: %s", event.EventType)
 	}
+
+	cm.finishPlan(plan)
+	return plan, err
+}
+
+// HandleFileChanges processes a batch of change events into a single plan. The content
+// cache is updated for every event before the dependency graph is traversed, and each
+// affected file appears once with the highest priority any event assigned it.
+func (cm *CacheManager) HandleFileChanges(events []*models.ChangeEvent) (*models.RegenerationPlan, error) {
+	logger.Debug("CacheManager: Handling %d file changes", len(events))
+
+	// Collapse repeated events for the same file into the most recent one
+	latest := make(map[string]*models.ChangeEvent)
+	var order []string
+	for _, event := range events {
+		canonicalizeEvent(event)
+		if _, seen := latest[event.FilePath]; !seen {
+			order = append(order, event.FilePath)
+		}
+		latest[event.FilePath] = event
+	}
+
+	merged := newRegenerationPlan()
+	var errs []error
+
+	// Update the content cache for every file first
+	contentChanged := make(map[string]bool)
+	for _, filePath := range order {
+		event := latest[filePath]
+		merged.ChangedFiles = append(merged.ChangedFiles, filePath)
+
+		switch event.EventType {
+		case "delete", "rename":
+		case "write", "create":
+			changed, err := cm.updateContent(event)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			contentChanged[filePath] = changed
+		default:
+			errs = append(errs, fmt.Errorf("unknown event type: %s", event.EventType))
+		}
+	}
+
+	// Then compute what each change affects. Deletions go first so a create in the
+	// same batch can be matched to the file it was renamed from.
+	for _, filePath := range order {
+		event := latest[filePath]
+		plan := newRegenerationPlan()
+
+		switch event.EventType {
+		case "delete":
+			cm.handleFileDelete(event, plan)
+		case "rename":
+			if _, err := cm.handleFileRename(event, plan); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			continue
+		}
+
+		mergeRegenerationPlan(merged, plan)
+	}
+
+	for _, filePath := range order {
+		event := latest[filePath]
+		changed, ok := contentChanged[filePath]
+		if !ok {
+			continue
+		}
+
+		plan := newRegenerationPlan()
+		cm.planFileChange(event, changed, plan)
+		mergeRegenerationPlan(merged, plan)
+	}
+
+	cm.finishPlan(merged)
+	return merged, errors.Join(errs...)
+}
+
+// canonicalizeEvent replaces the paths of event with their cache keys
+func canonicalizeEvent(event *models.ChangeEvent) {
+	event.FilePath = models.CanonicalPath(event.FilePath)
+	event.OldPath = models.CanonicalPath(event.OldPath)
+}
+
+func newRegenerationPlan() *models.RegenerationPlan {
+	return &models.RegenerationPlan{
+		ChangedFiles:    []string{},
+		AffectedFiles:   []string{},
+		RegenerationMap: make(map[string][]string),
+		Reasons:         make(map[string]string),
+		Priority:        make(map[string]int),
+		Outputs:         make(map[string][]string),
+	}
+}
+
+// mergeRegenerationPlan folds src into dst, keeping the highest priority (and its reason) per file
+func mergeRegenerationPlan(dst, src *models.RegenerationPlan) {
+	for _, file := range src.AffectedFiles {
+		priority := src.Priority[file]
+		existing, exists := dst.Priority[file]
+		if !exists {
+			dst.AffectedFiles = append(dst.AffectedFiles, file)
+		}
+		if !exists || priority > existing {
+			dst.Priority[file] = priority
+			dst.Reasons[file] = src.Reasons[file]
+		}
+	}
+
+	for source, outputs := range src.RegenerationMap {
+		dst.RegenerationMap[source] = append(dst.RegenerationMap[source], outputs...)
+	}
+
+	dst.StaleOutputs = append(dst.StaleOutputs, src.StaleOutputs...)
 }
 
 // GetParsedFile retrieves parsed file (checks content, then parse cache)
@@ -96,6 +226,15 @@ func (cm *CacheManager) GetParsedFile(filePath string) (*coreModels.ParsedFile,
 	return parsed, exists, nil
 }
 
+// ReadSource returns a file's contents, reusing the bytes the content cache read while
+// hashing it when they were kept
+func (cm *CacheManager) ReadSource(filePath string) ([]byte, error) {
+	if data, ok := cm.content.GetContentBytes(filePath); ok {
+		return data, nil
+	}
+	return os.ReadFile(filePath)
+}
+
 // SetParsedFile stores parsed file and updates dependency graph
 func (cm *CacheManager) SetParsedFile(filePath string, parsed *coreModels.ParsedFile) error {
 	// Store in parse cache
@@ -103,19 +242,13 @@ func (cm *CacheManager) SetParsedFile(filePath string, parsed *coreModels.Parsed
 		return fmt.Errorf("failed to store parsed file: %w", err)
 	}
 
-	// Extract dependencies and update dependency graph
-	dependencies, err := cm.parse.GetDependencies(filePath)
-	if err != nil {
-		logger.Debug("CacheManager: Failed to get dependencies for %s: %v", filePath, err)
-		dependencies = []string{} // Continue with empty dependencies
-	}
-
-	if err := cm.deps.UpdateNode(filePath, dependencies); err != nil {
+	// Extract dependencies with their imports and update dependency graph
+	if err := cm.deps.UpdateNodeEdges(filePath, models.ImportEdges(parsed)); err != nil {
 		return fmt.Errorf("failed to update dependency graph: %w", err)
 	}
 
 	// Update content hash in dependency graph if we have content info
-	if contentEntry, exists := cm.content.GetContent(filePath); exists {
+	if contentEntry, exists := cm.content.GetContent(filePath); exists && contentEntry.Exists {
 		if node, nodeExists := cm.deps.GetNode(filePath); nodeExists {
 			node.ContentHash = contentEntry.ContentHash
 		}
@@ -127,9 +260,10 @@ func (cm *CacheManager) SetParsedFile(filePath string, parsed *coreModels.Parsed
 
 // MarkGenerated records successful generation
 func (cm *CacheManager) MarkGenerated(sourcePath, outputPath string) error {
+	sourcePath = models.CanonicalPath(sourcePath)
 	// Get current content hash
 	contentEntry, exists := cm.content.GetContent(sourcePath)
-	if !exists {
+	if !exists || !contentEntry.Exists {
 		return fmt.Errorf("no content entry found for source file: %s", sourcePath)
 	}
 
@@ -140,22 +274,41 @@ func (cm *CacheManager) MarkGenerated(sourcePath, outputPath string) error {
 		dependencies = []string{}
 	}
 
-	// TODO: In a real implementation, you'd get actual template and config hashes
-	templateHash := "template-v1" // Placeholder
-	configHash := "config-v1"     // Placeholder
+	// TODO: In a real implementation, you'd get the actual config hash
+	configHash := "config-v1" // Placeholder
 
-	return cm.generation.MarkGenerated(sourcePath, outputPath, contentEntry.ContentHash, templateHash, configHash, dependencies)
+	if err := cm.generation.MarkGenerated(sourcePath, outputPath, contentEntry.ContentHash, cm.templateHash, configHash, dependencies); err != nil {
+		return err
+	}
+
+	cm.publish(models.CacheEvent{Type: models.CacheEventGenerated, FilePath: sourcePath, OutputPath: outputPath})
+	return nil
+}
+
+// SetTemplateHash sets the hash of the templates outputs are generated from, recorded with
+// each generation. Outputs generated from other templates need regeneration.
+func (cm *CacheManager) SetTemplateHash(hash string) {
+	cm.templateHash = hash
+}
+
+// needsRegeneration checks the generation layer, then whether the file was generated from
+// the current templates
+func (cm *CacheManager) needsRegeneration(sourcePath, contentHash string, dependencies []string) (bool, string, error) {
+	needsRegen, reason, err := cm.generation.NeedsRegeneration(sourcePath, contentHash, dependencies)
+	if err != nil || needsRegen || cm.templateHash == "" {
+		return needsRegen, reason, err
+	}
+	if info, exists := cm.generation.GetGenerationInfo(sourcePath); exists && info.TemplateHash != cm.templateHash {
+		return true, "templates changed", nil
+	}
+	return false, "", nil
 }
 
 // GetRegenerationPlan returns what needs to be regenerated
 func (cm *CacheManager) GetRegenerationPlan(changedFiles []string) (*models.RegenerationPlan, error) {
-	plan := &models.RegenerationPlan{
-		ChangedFiles:    changedFiles,
-		AffectedFiles:   []string{},
-		RegenerationMap: make(map[string][]string),
-		Reasons:         make(map[string]string),
-		Priority:        make(map[string]int),
-	}
+	changedFiles = canonicalPaths(changedFiles)
+	plan := newRegenerationPlan()
+	plan.ChangedFiles = changedFiles
 
 	allAffected := make(map[string]bool)
 
@@ -174,7 +327,7 @@ func (cm *CacheManager) GetRegenerationPlan(changedFiles []string) (*models.Rege
 			if !allAffected[affectedFile] {
 				allAffected[affectedFile] = true
 				plan.AffectedFiles = append(plan.AffectedFiles, affectedFile)
-				plan.Reasons[affectedFile] = fmt.Sprintf("depends on changed file: %s", changedFile)
+				plan.Reasons[affectedFile] = fmt.Sprintf("depends on changed file: %s%s", changedFile, cm.importDetail(affectedFile, changedFile))
 				plan.Priority[affectedFile] = 1 // Default priority
 			}
 		}
@@ -182,9 +335,9 @@ func (cm *CacheManager) GetRegenerationPlan(changedFiles []string) (*models.Rege
 
 	// Also check generation cache for files that need regeneration
 	for _, changedFile := range changedFiles {
-		if contentEntry, exists := cm.content.GetContent(changedFile); exists {
+		if contentEntry, exists := cm.content.GetContent(changedFile); exists && contentEntry.Exists {
 			dependencies, _ := cm.deps.GetDependencies(changedFile)
-			needsRegen, reason, err := cm.generation.NeedsRegeneration(changedFile, contentEntry.ContentHash, dependencies)
+			needsRegen, reason, err := cm.needsRegeneration(changedFile, contentEntry.ContentHash, dependencies)
 			if err != nil {
 				logger.Debug("CacheManager: Error checking regeneration for %s: %v", changedFile, err)
 				continue
@@ -204,39 +357,147 @@ func (cm *CacheManager) GetRegenerationPlan(changedFiles []string) (*models.Rege
 	logger.Debug("CacheManager: Generated regeneration plan - %d changed files affect %d total files",
 		len(changedFiles), len(plan.AffectedFiles))
 
+	cm.finishPlan(plan)
 	return plan, nil
 }
 
+// finishPlan orders a built plan, maps its affected files to their outputs and publishes it
+func (cm *CacheManager) finishPlan(plan *models.RegenerationPlan) {
+	cm.sortAffectedFiles(plan)
+	cm.mapOutputs(plan)
+	cm.publishPlan(plan)
+}
+
+// mapOutputs records the output paths each affected file was last generated to. Files
+// that have never been generated have no entry.
+func (cm *CacheManager) mapOutputs(plan *models.RegenerationPlan) {
+	if plan.Outputs == nil {
+		plan.Outputs = make(map[string][]string)
+	}
+	for _, filePath := range plan.AffectedFiles {
+		if info, exists := cm.generation.GetGenerationInfo(filePath); exists && info.OutputPath != "" {
+			plan.Outputs[filePath] = []string{info.OutputPath}
+		}
+	}
+}
+
+// GetPlannedOutputs returns the known output paths of a plan's affected files, in the
+// plan's order and without duplicates
+func (cm *CacheManager) GetPlannedOutputs(plan *models.RegenerationPlan) []string {
+	if plan == nil {
+		return nil
+	}
+	if plan.Outputs == nil {
+		cm.mapOutputs(plan)
+	}
+
+	seen := make(map[string]bool)
+	var outputs []string
+	for _, filePath := range plan.AffectedFiles {
+		for _, output := range plan.Outputs[filePath] {
+			if !seen[output] {
+				seen[output] = true
+				outputs = append(outputs, output)
+			}
+		}
+	}
+	return outputs
+}
+
+// sortAffectedFiles orders a plan's affected files by priority, highest first, and within
+// a priority by dependency order, so a file comes before the files that depend on it.
+// Files without a place in the order (in a cycle, or not in the graph) come last by path.
+func (cm *CacheManager) sortAffectedFiles(plan *models.RegenerationPlan) {
+	order, err := cm.deps.GetTopologicalOrder()
+	if err != nil {
+		logger.Debug("CacheManager: Ordering plan without a full dependency order: %v", err)
+	}
+	position := make(map[string]int, len(order))
+	for i, filePath := range order {
+		position[filePath] = i
+	}
+	rank := func(filePath string) int {
+		if i, ok := position[filePath]; ok {
+			return i
+		}
+		return len(order)
+	}
+
+	sort.SliceStable(plan.AffectedFiles, func(i, j int) bool {
+		a, b := plan.AffectedFiles[i], plan.AffectedFiles[j]
+		if plan.Priority[a] != plan.Priority[b] {
+			return plan.Priority[a] > plan.Priority[b]
+		}
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		return a < b
+	})
+}
+
 // GetAffectedFiles returns files affected by changes
 func (cm *CacheManager) GetAffectedFiles(changedFile string) ([]string, error) {
 	return cm.deps.GetAffectedFiles(changedFile)
 }
 
-// ValidateIntegrity checks cache consistency across layers
-func (cm *CacheManager) ValidateIntegrity() error {
+// canonicalPaths returns the cache keys of paths
+func canonicalPaths(paths []string) []string {
+	keys := make([]string, len(paths))
+	for i, path := range paths {
+		keys[i] = models.CanonicalPath(path)
+	}
+	return keys
+}
+
+// ValidateIntegrity checks cache consistency across layers and reports parse entries
+// without content, dependency nodes nothing refers to, and dependency cycles
+func (cm *CacheManager) ValidateIntegrity() (*models.IntegrityReport, error) {
+	report := &models.IntegrityReport{}
+
 	// Check that all parsed files have corresponding content entries
-	parsedFiles := cm.parse.(*layers.ParseCache).GetAllParsedFiles()
+	tracked := make(map[string]bool)
+	for _, filePath := range cm.content.ListFiles() {
+		tracked[filePath] = true
+	}
+	parsedFiles := cm.parse.GetAllParsedFiles()
 	for filePath := range parsedFiles {
-		if _, exists := cm.content.GetContent(filePath); !exists {
-			logger.Debug("CacheManager: Warning - parsed file %s has no content entry", filePath)
+		if !tracked[filePath] {
+			report.OrphanParseEntries = append(report.OrphanParseEntries, filePath)
 		}
 	}
+	sort.Strings(report.OrphanParseEntries)
+
+	// Nodes that are neither tracked sources nor depended on are left over from removed files.
+	// Tracked files without a parse entry may just have been evicted from the parse cache.
+	for filePath := range cm.deps.ToAdjacencyList() {
+		if _, parsed := parsedFiles[filePath]; parsed || tracked[filePath] {
+			continue
+		}
+		dependents, err := cm.deps.GetDependents(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependents of %s: %w", filePath, err)
+		}
+		if len(dependents) == 0 {
+			report.DanglingNodes = append(report.DanglingNodes, filePath)
+		}
+	}
+	sort.Strings(report.DanglingNodes)
 
 	// Check for dependency cycles
 	cycles, err := cm.deps.DetectCycles()
 	if err != nil {
-		return fmt.Errorf("failed to detect cycles: %w", err)
+		return nil, fmt.Errorf("failed to detect cycles: %w", err)
 	}
+	report.Cycles = cycles
 
-	if len(cycles) > 0 {
-		logger.Debug("CacheManager: Warning - detected %d dependency cycles", len(cycles))
-		for i, cycle := range cycles {
-			logger.Debug("  Cycle %d: %v", i+1, cycle)
-		}
-	}
+	logger.Debug("CacheManager: Cache integrity validation completed: %d orphan parse entries, %d dangling nodes, %d cycles",
+		len(report.OrphanParseEntries), len(report.DanglingNodes), len(report.Cycles))
+	return report, nil
+}
 
-	logger.Debug("CacheManager: Cache integrity validation completed")
-	return nil
+// GetDependencyGraph exposes the dependency graph layer
+func (cm *CacheManager) GetDependencyGraph() models.DependencyGraphInterface {
+	return cm.deps
 }
 
 // GetStats returns comprehensive cache statistics
@@ -250,11 +511,14 @@ func (cm *CacheManager) GetStats() map[string]*models.CacheStats {
 }
 
 // WarmCache initializes cache from file system
-func (cm *CacheManager) WarmCache(rootDir string, excludePaths []string) error {
+func (cm *CacheManager) WarmCache(rootDir string, excludePaths []string, moduleName string) (*models.WarmCacheReport, error) {
+	// Walk the resolved root, since a symlinked root isn't descended into
+	rootDir = filepath.FromSlash(models.CanonicalPath(rootDir))
 	logger.Debug("CacheManager: Warming cache from directory: %s", rootDir)
 	startTime := time.Now()
+	report := &models.WarmCacheReport{}
 
-	var fileCount int
+	parsedFiles := make(map[string]*coreModels.ParsedFile)
 	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -281,20 +545,79 @@ func (cm *CacheManager) WarmCache(rootDir string, excludePaths []string) error {
 			return nil
 		}
 
-		// Update content cache
-		_, _, err = cm.content.UpdateContent(path)
+		// Update content cache; the walk just found the file, so a missing entry is outdated
+		key := models.CanonicalPath(path)
+		cm.forgetMissing(key)
+		_, contentChanged, err := cm.content.UpdateContent(key)
 		if err != nil {
 			logger.Debug("CacheManager: Failed to cache content for %s: %v", path, err)
+			report.FilesFailed++
 			return nil // Continue with other files
 		}
+		report.FilesHashed++
+
+		if contentChanged {
+			cm.parse.InvalidateParse(key)
+		} else if parsed, exists := cm.parse.GetParsedFile(key); exists {
+			parsedFiles[key] = parsed
+			report.FilesSkipped++
+			return nil
+		}
 
-		fileCount++
+		// Routes are parsed relative to the directory holding route.go, as the walker does
+		routeRelPath := filepath.Dir(relPath)
+		src, err := cm.ReadSource(path)
+		if err != nil {
+			logger.Debug("CacheManager: Failed to read %s: %v", path, err)
+			report.FilesFailed++
+			return nil
+		}
+		parsed, err := ast.ParseRouteSource(path, routeRelPath, moduleName, src)
+		if err != nil {
+			logger.Debug("CacheManager: Failed to parse %s: %v", path, err)
+			report.FilesFailed++
+			return nil
+		}
+		if err := cm.parse.SetParsedFile(key, parsed); err != nil {
+			logger.Debug("CacheManager: Failed to cache parse for %s: %v", path, err)
+			report.FilesFailed++
+			return nil
+		}
+
+		parsedFiles[key] = parsed
+		report.FilesParsed++
 		return nil
 	})
+	if err != nil {
+		report.Duration = time.Since(startTime)
+		return report, err
+	}
+
+	if err := cm.deps.BuildGraph(parsedFiles); err != nil {
+		report.Duration = time.Since(startTime)
+		return report, fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+	for path := range parsedFiles {
+		if contentEntry, exists := cm.content.GetContent(path); exists && contentEntry.Exists {
+			if node, nodeExists := cm.deps.GetNode(path); nodeExists {
+				node.ContentHash = contentEntry.ContentHash
+			}
+		}
+	}
 
-	duration := time.Since(startTime)
-	logger.Debug("CacheManager: Cache warming completed in %v - processed %d files", duration, fileCount)
-	return err
+	// Lookups from here on reflect steady-state cache effectiveness
+	cm.content.MarkWarmed()
+	cm.parse.MarkWarmed()
+
+	report.Duration = time.Since(startTime)
+	logger.Debug("CacheManager: Cache warming completed in %v - hashed %d, parsed %d, skipped %d, failed %d files",
+		report.Duration, report.FilesHashed, report.FilesParsed, report.FilesSkipped, report.FilesFailed)
+	return report, nil
+}
+
+// Close stops the background work of the cache layers
+func (cm *CacheManager) Close() error {
+	return cm.content.Close()
 }
 
 // Clear resets all cache layers
@@ -349,7 +672,7 @@ func (cm *CacheManager) NeedsRegistryRegeneration(currentRoutes []string) (bool,
 	// Compare signatures
 	if cachedSignature.Signature != currentSignature.Signature {
 		logger.Debug("CacheManager: Registry signature changed (%s -> %s), regeneration needed",
-			cachedSignature.Signature[:8], currentSignature.Signature[:8])
+			models.ShortHash(cachedSignature.Signature), models.ShortHash(currentSignature.Signature))
 		return true, nil
 	}
 
@@ -358,75 +681,151 @@ func (cm *CacheManager) NeedsRegistryRegeneration(currentRoutes []string) (bool,
 }
 
 // createRegistrySignature generates a signature for the current route structure
-func (cm *CacheManager) createRegistrySignature(routePaths []string) *models.RegistrySignature {
-	// Sort the routes for consistent signature generation
-	sortedPaths := make([]string, len(routePaths))
-	copy(sortedPaths, routePaths)
-	sort.Strings(sortedPaths)
-
-	// Create hash from sorted route paths
-	data := strings.Join(sortedPaths, "|")
-	hash := md5.Sum([]byte(data))
-	signature := fmt.Sprintf("%x", hash)
-
-	return &models.RegistrySignature{
-		RouteCount: len(routePaths),
-		RoutePaths: sortedPaths,
-		Signature:  signature,
-		UpdatedAt:  time.Now(),
-	}
+func (cm *CacheManager) createRegistrySignature(routeEntries []string) *models.RegistrySignature {
+	return models.NewRegistrySignature(routeEntries)
 }
 
 // Helper methods for internal use
 
 // handleFileDelete processes file deletion
 func (cm *CacheManager) handleFileDelete(event *models.ChangeEvent, plan *models.RegenerationPlan) (*models.RegenerationPlan, error) {
-	// Remove from all caches
-	cm.content.RemoveContent(event.FilePath)
-	cm.parse.InvalidateParse(event.FilePath)
-	cm.deps.RemoveNode(event.FilePath)
-	cm.generation.InvalidateGeneration(event.FilePath)
+	// Remember the last known state before the entries are dropped, in case the file was renamed
+	snapshot := cm.snapshotFile(event.FilePath)
+	event.OldHash = snapshot.hash
+	event.NewHash = ""
+	cm.rememberDelete(snapshot)
 
-	// Find files that depended on this file
+	// Dependents have to be found before the node is removed from the graph
 	dependents, err := cm.deps.GetDependents(event.FilePath)
 	if err == nil {
 		plan.AffectedFiles = dependents
 		for _, dependent := range dependents {
-			plan.Reasons[dependent] = fmt.Sprintf("dependency deleted: %s", event.FilePath)
+			plan.Reasons[dependent] = fmt.Sprintf("dependency deleted: %s (last hash %s)", event.FilePath, models.ShortHash(event.OldHash))
 			plan.Priority[dependent] = 3 // High priority for deleted dependencies
 		}
 	}
 
+	cm.removeFile(event.FilePath)
 	return plan, nil
 }
 
+// removeFile drops a file from every cache layer
+func (cm *CacheManager) removeFile(filePath string) {
+	cm.content.RemoveContent(filePath)
+	cm.parse.InvalidateParse(filePath)
+	cm.deps.RemoveNode(filePath)
+	cm.generation.InvalidateGeneration(filePath)
+
+	cm.publish(models.CacheEvent{Type: models.CacheEventInvalidated, FilePath: filePath})
+}
+
 // handleFileChange processes file modification/creation
 func (cm *CacheManager) handleFileChange(event *models.ChangeEvent, plan *models.RegenerationPlan) (*models.RegenerationPlan, error) {
-	// Update content cache
-	_, contentChanged, err := cm.content.UpdateContent(event.FilePath)
+	contentChanged, err := cm.updateContent(event)
 	if err != nil {
-		return plan, fmt.Errorf("failed to update content cache: %w", err)
+		return plan, err
+	}
+
+	cm.planFileChange(event, contentChanged, plan)
+	return plan, nil
+}
+
+// updateContent refreshes the content cache for a modified file, invalidating its parse on change.
+// The hashes before and after the update are recorded on the event.
+func (cm *CacheManager) updateContent(event *models.ChangeEvent) (bool, error) {
+	// The event means the file is back, so a missing entry is outdated
+	cm.forgetMissing(event.FilePath)
+	if previous, exists := cm.content.GetContent(event.FilePath); exists {
+		event.OldHash = previous.ContentHash
+	}
+
+	contentEntry, contentChanged, err := cm.content.UpdateContent(event.FilePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to update content cache: %w", err)
+	}
+	if contentEntry != nil {
+		event.NewHash = contentEntry.ContentHash
 	}
 
 	if contentChanged {
-		// Invalidate parse cache
 		cm.parse.InvalidateParse(event.FilePath)
+		cm.publish(models.CacheEvent{Type: models.CacheEventInvalidated, FilePath: event.FilePath})
+	}
+
+	return contentChanged, nil
+}
 
+// forgetMissing drops the content entry of a file recorded as missing, so the next
+// update looks at the disk instead of waiting for the entry to expire
+func (cm *CacheManager) forgetMissing(filePath string) {
+	if entry, exists := cm.content.GetContent(filePath); exists && !entry.Exists {
+		cm.content.RemoveContent(filePath)
+	}
+}
+
+// planFileChange adds the files affected by a modified file to the plan. A created file
+// whose content matches a recently deleted one is treated as a rename of that file.
+func (cm *CacheManager) planFileChange(event *models.ChangeEvent, contentChanged bool, plan *models.RegenerationPlan) {
+	if event.EventType == "create" && event.NewHash != "" {
+		if previous, renamed := cm.matchRename(event.NewHash, event.FilePath); renamed {
+			event.OldHash = previous.hash
+			cm.migrateFile(previous, event, plan)
+			return
+		}
+	}
+
+	if contentChanged {
 		// Find affected files
 		affected, err := cm.deps.GetAffectedFiles(event.FilePath)
 		if err == nil {
 			plan.AffectedFiles = affected
 			for _, affectedFile := range affected {
-				plan.Reasons[affectedFile] = fmt.Sprintf("dependency changed: %s", event.FilePath)
+				plan.Reasons[affectedFile] = fmt.Sprintf("dependency changed: %s (%s)%s", event.FilePath, hashTransition(event), cm.importDetail(affectedFile, event.FilePath))
 				plan.Priority[affectedFile] = 1
 			}
 		}
 
 		// The changed file itself needs regeneration
 		plan.AffectedFiles = append(plan.AffectedFiles, event.FilePath)
-		plan.Reasons[event.FilePath] = "file content changed"
+		plan.Reasons[event.FilePath] = fmt.Sprintf("file content changed (%s)", hashTransition(event))
 		plan.Priority[event.FilePath] = 2
+		return
 	}
 
-	return plan, nil
-}
\ No newline at end of file
+	// Source unchanged, but the generated output may have been deleted or edited
+	if contentEntry, exists := cm.content.GetContent(event.FilePath); exists && contentEntry.Exists {
+		dependencies, _ := cm.deps.GetDependencies(event.FilePath)
+		needsRegen, reason, err := cm.needsRegeneration(event.FilePath, contentEntry.ContentHash, dependencies)
+		if err != nil {
+			logger.Debug("CacheManager: Error checking regeneration for %s: %v", event.FilePath, err)
+		} else if needsRegen {
+			plan.AffectedFiles = append(plan.AffectedFiles, event.FilePath)
+			plan.Reasons[event.FilePath] = reason
+			plan.Priority[event.FilePath] = 2
+		}
+	}
+}
+
+// hashTransition describes an event's content change as "hash abc12345 -> def67890"
+func hashTransition(event *models.ChangeEvent) string {
+	return fmt.Sprintf("hash %s -> %s", models.ShortHash(event.OldHash), models.ShortHash(event.NewHash))
+}
+
+// importDetail describes the import through which dependent directly depends on target
+// for reason messages, or returns "" when it doesn't or the import isn't known
+func (cm *CacheManager) importDetail(dependent, target string) string {
+	edges, err := cm.deps.GetEdges(dependent)
+	if err != nil {
+		return ""
+	}
+	for _, edge := range edges {
+		if edge.Target != target || edge.SourceLine == 0 {
+			continue
+		}
+		if edge.Alias != "" {
+			return fmt.Sprintf(" (imported as %s on line %d)", edge.Alias, edge.SourceLine)
+		}
+		return fmt.Sprintf(" (imported on line %d)", edge.SourceLine)
+	}
+	return ""
+}
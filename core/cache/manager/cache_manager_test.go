@@ -0,0 +1,72 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandleFileChangeRecordsHashes(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "repo.go")
+	dependent := models.CanonicalPath(filepath.Join(dir, "route.go"))
+	cm := NewCacheManager()
+
+	writeFile(t, source, "package repo\n")
+	firstHash := models.HashBytes([]byte("package repo\n"))
+	created := &models.ChangeEvent{FilePath: source, EventType: "create"}
+	if _, err := cm.HandleFileChange(created); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.OldHash != "" || created.NewHash != firstHash {
+		t.Fatalf("create hashes = %q -> %q, want \"\" -> %q", created.OldHash, created.NewHash, firstHash)
+	}
+
+	// The dependent is registered after the create so the delete below has one to report
+	if err := cm.deps.UpdateNode(dependent, []string{models.CanonicalPath(source)}); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, source, "package repo\n\nvar changed = true\n")
+	secondHash := models.HashBytes([]byte("package repo\n\nvar changed = true\n"))
+	written := &models.ChangeEvent{FilePath: source, EventType: "write"}
+	plan, err := cm.HandleFileChange(written)
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if written.OldHash != firstHash || written.NewHash != secondHash {
+		t.Fatalf("write hashes = %q -> %q, want %q -> %q", written.OldHash, written.NewHash, firstHash, secondHash)
+	}
+	transition := models.ShortHash(firstHash) + " -> " + models.ShortHash(secondHash)
+	if reason := plan.Reasons[dependent]; !strings.Contains(reason, transition) {
+		t.Fatalf("write reason for dependent = %q, want it to mention %q", reason, transition)
+	}
+
+	if err := os.Remove(source); err != nil {
+		t.Fatal(err)
+	}
+	deleted := &models.ChangeEvent{FilePath: source, EventType: "delete"}
+	plan, err = cm.HandleFileChange(deleted)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if deleted.OldHash != secondHash || deleted.NewHash != "" {
+		t.Fatalf("delete hashes = %q -> %q, want %q -> \"\"", deleted.OldHash, deleted.NewHash, secondHash)
+	}
+	if len(plan.AffectedFiles) != 1 || plan.AffectedFiles[0] != dependent {
+		t.Fatalf("delete affected files = %v, want [%s]", plan.AffectedFiles, dependent)
+	}
+	if reason := plan.Reasons[dependent]; !strings.Contains(reason, "last hash "+models.ShortHash(secondHash)) {
+		t.Fatalf("delete reason for dependent = %q, want it to mention the last hash", reason)
+	}
+}
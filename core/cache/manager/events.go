@@ -0,0 +1,85 @@
+package manager
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+// subscriberBuffer is how many events a subscriber can fall behind before events are dropped
+const subscriberBuffer = 256
+
+// subscriber delivers events to one callback from its own goroutine
+type subscriber struct {
+	events chan models.CacheEvent
+}
+
+// eventBus fans cache events out to subscribers without blocking the publisher
+type eventBus struct {
+	mutex       sync.RWMutex
+	subscribers map[int]*subscriber
+	nextID      int
+}
+
+// Subscribe calls fn for every invalidation, regeneration plan and recorded generation.
+// Events are delivered in order on a buffered channel, so a slow subscriber never blocks
+// the cache; events it falls too far behind on are dropped. The returned func stops
+// delivery and may be called more than once.
+func (cm *CacheManager) Subscribe(fn func(event models.CacheEvent)) func() {
+	bus := &cm.events
+	sub := &subscriber{events: make(chan models.CacheEvent, subscriberBuffer)}
+
+	bus.mutex.Lock()
+	if bus.subscribers == nil {
+		bus.subscribers = make(map[int]*subscriber)
+	}
+	id := bus.nextID
+	bus.nextID++
+	bus.subscribers[id] = sub
+	bus.mutex.Unlock()
+
+	go func() {
+		for event := range sub.events {
+			fn(event)
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			bus.mutex.Lock()
+			delete(bus.subscribers, id)
+			bus.mutex.Unlock()
+			close(sub.events)
+		})
+	}
+}
+
+// publish hands event to every subscriber, dropping it for those whose buffer is full
+func (cm *CacheManager) publish(event models.CacheEvent) {
+	bus := &cm.events
+	bus.mutex.RLock()
+	defer bus.mutex.RUnlock()
+
+	if len(bus.subscribers) == 0 {
+		return
+	}
+	event.Timestamp = time.Now()
+	for id, sub := range bus.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			logger.Debug("CacheManager: Subscriber %d is behind, dropping %s event", id, event.Type)
+		}
+	}
+}
+
+// publishPlan reports a created regeneration plan
+func (cm *CacheManager) publishPlan(plan *models.RegenerationPlan) {
+	if plan == nil {
+		return
+	}
+	cm.publish(models.CacheEvent{Type: models.CacheEventPlanCreated, Plan: plan})
+}
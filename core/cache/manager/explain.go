@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+)
+
+// Explain plans the regeneration of filePath as if it changed, and records for each
+// affected file the chain of dependents edges leading to it along with the stored and
+// current hashes of filePath
+func (cm *CacheManager) Explain(filePath string) (*models.RegenerationExplanation, error) {
+	filePath = models.CanonicalPath(filePath)
+	plan, err := cm.GetRegenerationPlan([]string{filePath})
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan regeneration of %s: %w", filePath, err)
+	}
+
+	explanation := &models.RegenerationExplanation{
+		File:   filePath,
+		Plan:   plan,
+		Chains: make(map[string][]string),
+	}
+	if entry, exists := cm.content.GetContent(filePath); exists && entry.Exists {
+		explanation.CurrentHash = entry.ContentHash
+	}
+	if info, exists := cm.generation.GetGenerationInfo(filePath); exists {
+		explanation.Generation = info
+	}
+
+	// Breadth-first over dependents, so each chain is a shortest one
+	parent := map[string]string{filePath: ""}
+	queue := []string{filePath}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		dependents, err := cm.deps.GetDependents(current)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependents of %s: %w", current, err)
+		}
+		for _, dependent := range dependents {
+			if _, seen := parent[dependent]; !seen {
+				parent[dependent] = current
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	for _, affected := range plan.AffectedFiles {
+		if _, reachable := parent[affected]; !reachable {
+			continue
+		}
+		var chain []string
+		for step := affected; step != ""; step = parent[step] {
+			chain = append([]string{step}, chain...)
+		}
+		explanation.Chains[affected] = chain
+	}
+	return explanation, nil
+}
@@ -0,0 +1,79 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+// ExportManifest writes the generation record of every source file to path
+func (cm *CacheManager) ExportManifest(path string) error {
+	manifest := &models.GenerationManifest{
+		Version:       models.GenerationManifestVersion,
+		HashAlgorithm: models.CurrentHasher().Name(),
+		Generations:   cm.sortedGenerations(),
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode generation manifest: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create generation manifest directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write generation manifest %s: %w", path, err)
+	}
+
+	logger.Debug("CacheManager: Exported %d generation records to %s", len(manifest.Generations), path)
+	return nil
+}
+
+// ImportManifest restores the generation records in path whose source still hashes to the
+// recorded SourceHash, so those sources aren't generated again. Records of changed or
+// removed sources are dropped. A missing manifest isn't an error.
+func (cm *CacheManager) ImportManifest(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			logger.Debug("CacheManager: No generation manifest found at %s", path)
+			return nil
+		}
+		return fmt.Errorf("failed to read generation manifest %s: %w", path, err)
+	}
+
+	var manifest models.GenerationManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse generation manifest %s: %w", path, err)
+	}
+	if manifest.Version != models.GenerationManifestVersion {
+		return fmt.Errorf("unsupported generation manifest version %d in %s (expected %d)",
+			manifest.Version, path, models.GenerationManifestVersion)
+	}
+	if !models.SameHashAlgorithm(manifest.HashAlgorithm) {
+		logger.Debug("CacheManager: Ignoring generation manifest %s (hashed with %s, now using %s)",
+			path, models.HashAlgorithmOrDefault(manifest.HashAlgorithm), models.CurrentHasher().Name())
+		return nil
+	}
+
+	restored := 0
+	for _, info := range manifest.Generations {
+		if info == nil {
+			continue
+		}
+		entry, _, err := cm.content.UpdateContent(info.SourcePath)
+		if err != nil || !entry.Exists || entry.ContentHash != info.SourceHash {
+			logger.Debug("CacheManager: Dropping manifest record of %s, its source changed", info.SourcePath)
+			continue
+		}
+		cm.generation.RestoreGeneration(info)
+		restored++
+	}
+
+	logger.Debug("CacheManager: Imported %d of %d generation records from %s", restored, len(manifest.Generations), path)
+	return nil
+}
@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+// LoadState reads persisted state from a previous run
+func (cm *CacheManager) LoadState(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			logger.Debug("CacheManager: No persisted state found at %s", path)
+			return nil
+		}
+		return fmt.Errorf("failed to read cache state %s: %w", path, err)
+	}
+
+	var state models.PersistedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse cache state %s: %w", path, err)
+	}
+
+	cm.previousState = &state
+	// Hashes from another algorithm would never match, so treat them as missing
+	if !models.SameHashAlgorithm(state.HashAlgorithm) {
+		logger.Debug("CacheManager: Discarding persisted hashes from %s (saved with %s, now using %s)",
+			path, models.HashAlgorithmOrDefault(state.HashAlgorithm), models.CurrentHasher().Name())
+		return nil
+	}
+	// A signature recorded during this run is newer than the persisted one
+	if cm.registrySignature == nil && state.RegistrySignature != nil {
+		cm.registrySignature = state.RegistrySignature
+	}
+	for _, info := range state.Generations {
+		// Records of sources removed since then would otherwise be carried forward forever
+		if _, err := os.Stat(info.SourcePath); err != nil {
+			continue
+		}
+		cm.generation.RestoreGeneration(info)
+	}
+	logger.Debug("CacheManager: Loaded persisted state from %s (saved %s)", path, state.SavedAt.Format(time.RFC3339))
+	return nil
+}
+
+// SaveState persists the current state for the next run
+func (cm *CacheManager) SaveState(path string) error {
+	state := &models.PersistedState{
+		SavedAt:           time.Now(),
+		Stats:             cm.GetStats(),
+		RegistrySignature: cm.registrySignature,
+		Generations:       cm.sortedGenerations(),
+		HashAlgorithm:     models.CurrentHasher().Name(),
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache state directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache state %s: %w", path, err)
+	}
+
+	cm.previousState = state
+	logger.Debug("CacheManager: Saved persisted state to %s", path)
+	return nil
+}
+
+// sortedGenerations returns the generation records in source path order
+func (cm *CacheManager) sortedGenerations() []*models.GenerationInfo {
+	entries := cm.generation.GetAllGenerations()
+	generations := make([]*models.GenerationInfo, 0, len(entries))
+	for _, info := range entries {
+		generations = append(generations, info)
+	}
+	sort.Slice(generations, func(i, j int) bool {
+		return generations[i].SourcePath < generations[j].SourcePath
+	})
+	return generations
+}
+
+// GetPreviousStats returns per-layer stats recorded by the previous run
+func (cm *CacheManager) GetPreviousStats() (map[string]*models.CacheStats, bool) {
+	if cm.previousState == nil || cm.previousState.Stats == nil {
+		return nil, false
+	}
+	return cm.previousState.Stats, true
+}
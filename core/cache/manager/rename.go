@@ -0,0 +1,207 @@
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/logger"
+	coreModels "github.com/tristendillon/conduit/core/models"
+)
+
+// renameWindow is how long a deleted file is remembered so a create with the same
+// content can be recognized as a rename
+const renameWindow = 2 * time.Second
+
+// deletedFile is the cached state of a recently deleted file
+type deletedFile struct {
+	path       string
+	hash       string
+	parsed     *coreModels.ParsedFile
+	edges      []models.Edge
+	outputPath string
+	deletedAt  time.Time
+}
+
+// renameTracker remembers recently deleted files by content hash. Several deleted files
+// can share a hash, like identical route files moved together.
+type renameTracker struct {
+	mutex   sync.Mutex
+	deleted map[string][]*deletedFile
+}
+
+// TrackedFilesUnder returns the tracked files inside a directory
+func (cm *CacheManager) TrackedFilesUnder(dir string) []string {
+	prefix := models.CanonicalPrefix(dir)
+
+	var files []string
+	for _, filePath := range cm.content.ListFiles() {
+		if strings.HasPrefix(filePath, prefix) {
+			files = append(files, filePath)
+		}
+	}
+	return files
+}
+
+// snapshotFile captures the cached state of a file before it is removed from the caches
+func (cm *CacheManager) snapshotFile(filePath string) *deletedFile {
+	snapshot := &deletedFile{path: filePath, deletedAt: time.Now()}
+
+	if contentEntry, exists := cm.content.GetContent(filePath); exists && contentEntry.Exists {
+		snapshot.hash = contentEntry.ContentHash
+	}
+	if parsed, exists := cm.parse.GetParsedFile(filePath); exists {
+		snapshot.parsed = parsed
+	}
+	if edges, err := cm.deps.GetEdges(filePath); err == nil {
+		snapshot.edges = edges
+	}
+	if info, exists := cm.generation.GetGenerationInfo(filePath); exists {
+		snapshot.outputPath = info.OutputPath
+	}
+
+	return snapshot
+}
+
+// rememberDelete keeps a deleted file's state around so it can be matched to a rename
+func (cm *CacheManager) rememberDelete(snapshot *deletedFile) {
+	if snapshot.hash == "" {
+		return
+	}
+
+	cm.renames.mutex.Lock()
+	defer cm.renames.mutex.Unlock()
+
+	if cm.renames.deleted == nil {
+		cm.renames.deleted = make(map[string][]*deletedFile)
+	}
+	cm.renames.deleted[snapshot.hash] = append(cm.renames.deleted[snapshot.hash], snapshot)
+}
+
+// matchRename returns the recently deleted file with the given content hash that newPath
+// was most likely renamed from, if any. When several files share the hash, the one whose
+// path ends like newPath wins, so files moved together with their directory keep their state.
+func (cm *CacheManager) matchRename(hash, newPath string) (*deletedFile, bool) {
+	cm.renames.mutex.Lock()
+	defer cm.renames.mutex.Unlock()
+
+	now := time.Now()
+	for key, candidates := range cm.renames.deleted {
+		var live []*deletedFile
+		for _, deleted := range candidates {
+			if now.Sub(deleted.deletedAt) <= renameWindow {
+				live = append(live, deleted)
+			}
+		}
+		if len(live) == 0 {
+			delete(cm.renames.deleted, key)
+		} else {
+			cm.renames.deleted[key] = live
+		}
+	}
+
+	candidates := cm.renames.deleted[hash]
+	if len(candidates) == 0 {
+		return nil, false
+	}
+	best := 0
+	for i, deleted := range candidates {
+		if commonSuffixParts(deleted.path, newPath) > commonSuffixParts(candidates[best].path, newPath) {
+			best = i
+		}
+	}
+
+	deleted := candidates[best]
+	if remaining := append(candidates[:best:best], candidates[best+1:]...); len(remaining) > 0 {
+		cm.renames.deleted[hash] = remaining
+	} else {
+		delete(cm.renames.deleted, hash)
+	}
+	return deleted, true
+}
+
+// commonSuffixParts counts the trailing path elements two paths share
+func commonSuffixParts(a, b string) int {
+	aParts := strings.Split(filepath.ToSlash(a), "/")
+	bParts := strings.Split(filepath.ToSlash(b), "/")
+	count := 0
+	for count < len(aParts) && count < len(bParts) && aParts[len(aParts)-1-count] == bParts[len(bParts)-1-count] {
+		count++
+	}
+	return count
+}
+
+// handleFileRename processes an explicit rename from event.OldPath to event.FilePath
+func (cm *CacheManager) handleFileRename(event *models.ChangeEvent, plan *models.RegenerationPlan) (*models.RegenerationPlan, error) {
+	if event.OldPath == "" {
+		return plan, fmt.Errorf("rename event for %s has no old path", event.FilePath)
+	}
+
+	previous := cm.snapshotFile(event.OldPath)
+	cm.removeFile(event.OldPath)
+
+	if _, err := cm.updateContent(event); err != nil {
+		return plan, err
+	}
+	event.OldHash = previous.hash
+
+	cm.migrateFile(previous, event, plan)
+	return plan, nil
+}
+
+// migrateFile moves the cached state of a renamed file to its new path and plans the
+// regeneration of the new path along with the removal of the old output
+func (cm *CacheManager) migrateFile(previous *deletedFile, event *models.ChangeEvent, plan *models.RegenerationPlan) {
+	newPath := event.FilePath
+	event.EventType = "rename"
+	event.OldPath = previous.path
+	logger.Debug("CacheManager: Detected rename %s -> %s", previous.path, newPath)
+
+	if migrated := migrateParsedFile(previous, newPath); migrated != nil {
+		if err := cm.parse.SetParsedFile(newPath, migrated); err != nil {
+			logger.Debug("CacheManager: Failed to migrate parse cache for %s: %v", newPath, err)
+		}
+	}
+
+	if err := cm.deps.UpdateNodeEdges(newPath, previous.edges); err != nil {
+		logger.Debug("CacheManager: Failed to migrate dependency node for %s: %v", newPath, err)
+	} else if node, exists := cm.deps.GetNode(newPath); exists {
+		node.ContentHash = event.NewHash
+	}
+
+	if previous.outputPath != "" {
+		plan.StaleOutputs = append(plan.StaleOutputs, previous.outputPath)
+	}
+
+	plan.AffectedFiles = append(plan.AffectedFiles, newPath)
+	plan.Reasons[newPath] = fmt.Sprintf("renamed from %s", previous.path)
+	plan.Priority[newPath] = 2
+}
+
+// migrateParsedFile rewrites a parsed file's paths for its new location. Route files are
+// parsed relative to the project root, which is recovered from the old path.
+func migrateParsedFile(previous *deletedFile, newPath string) *coreModels.ParsedFile {
+	if previous.parsed == nil {
+		return nil
+	}
+
+	oldDir := filepath.Dir(previous.path)
+	relPath := filepath.Clean(previous.parsed.RelPath)
+	if !strings.HasSuffix(oldDir, string(filepath.Separator)+relPath) {
+		return nil
+	}
+	root := strings.TrimSuffix(oldDir, relPath)
+
+	newRelPath, err := filepath.Rel(root, filepath.Dir(newPath))
+	if err != nil || strings.HasPrefix(newRelPath, "..") {
+		return nil
+	}
+
+	migrated := *previous.parsed
+	migrated.Path = newPath
+	migrated.RelPath = newRelPath
+	return &migrated
+}
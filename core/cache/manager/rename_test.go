@@ -0,0 +1,147 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+	coreModels "github.com/tristendillon/conduit/core/models"
+)
+
+func TestCommonSuffixParts(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"/p/users/route.go", "/p/accounts/route.go", 1},
+		{"/p/old/users/route.go", "/p/new/users/route.go", 2},
+		{"/p/users/route.go", "/p/users/route.go", 4},
+		{"/p/users/route.go", "/p/users/repo.go", 0},
+		{"route.go", "/p/users/route.go", 1},
+	}
+	for _, tt := range tests {
+		if got := commonSuffixParts(tt.a, tt.b); got != tt.want {
+			t.Errorf("commonSuffixParts(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// createRoute writes a route file and records it in cm as generated into output
+func createRoute(t *testing.T, cm *CacheManager, root, relPath, content, output string) string {
+	t.Helper()
+	source := filepath.Join(root, relPath, "route.go")
+	if err := os.MkdirAll(filepath.Dir(source), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, source, content)
+	if _, err := cm.HandleFileChange(&models.ChangeEvent{FilePath: source, EventType: "create"}); err != nil {
+		t.Fatal(err)
+	}
+	parsed := &coreModels.ParsedFile{Path: source, RelPath: relPath, Methods: []string{"GET"}}
+	if err := cm.SetParsedFile(source, parsed); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, output, "package generated\n")
+	if err := cm.MarkGenerated(source, output); err != nil {
+		t.Fatal(err)
+	}
+	return source
+}
+
+func TestDeleteThenCreateIsRename(t *testing.T) {
+	root := t.TempDir()
+	cm := NewCacheManager()
+	output := filepath.Join(root, "gen_users.go")
+	oldPath := createRoute(t, cm, root, "users", "package users\n", output)
+
+	if err := os.Rename(filepath.Dir(oldPath), filepath.Join(root, "accounts")); err != nil {
+		t.Fatal(err)
+	}
+	newPath := filepath.Join(root, "accounts", "route.go")
+	if _, err := cm.HandleFileChange(&models.ChangeEvent{FilePath: oldPath, EventType: "delete"}); err != nil {
+		t.Fatal(err)
+	}
+	created := &models.ChangeEvent{FilePath: newPath, EventType: "create"}
+	plan, err := cm.HandleFileChange(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := models.CanonicalPath(newPath)
+	if created.EventType != "rename" || created.OldPath != models.CanonicalPath(oldPath) {
+		t.Errorf("event = %s from %q, want a rename from %s", created.EventType, created.OldPath, oldPath)
+	}
+	if want := "renamed from " + models.CanonicalPath(oldPath); plan.Reasons[key] != want {
+		t.Errorf("reason = %q, want %q", plan.Reasons[key], want)
+	}
+	if !slices.Equal(plan.StaleOutputs, []string{output}) {
+		t.Errorf("stale outputs = %v, want [%s]", plan.StaleOutputs, output)
+	}
+	parsed, exists := cm.parse.GetParsedFile(newPath)
+	if !exists || parsed.RelPath != "accounts" || parsed.Path != key {
+		t.Errorf("migrated parse = %+v, %t, want it moved to accounts", parsed, exists)
+	}
+	if _, exists := cm.parse.GetParsedFile(oldPath); exists {
+		t.Error("the old path is still parsed")
+	}
+}
+
+func TestExplicitRename(t *testing.T) {
+	root := t.TempDir()
+	cm := NewCacheManager()
+	output := filepath.Join(root, "gen_users.go")
+	oldPath := createRoute(t, cm, root, "users", "package users\n", output)
+
+	newPath := filepath.Join(root, "members", "route.go")
+	if err := os.Rename(filepath.Dir(oldPath), filepath.Dir(newPath)); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := cm.HandleFileChange(&models.ChangeEvent{FilePath: newPath, OldPath: oldPath, EventType: "rename"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(plan.StaleOutputs, []string{output}) {
+		t.Errorf("stale outputs = %v, want [%s]", plan.StaleOutputs, output)
+	}
+	if parsed, exists := cm.parse.GetParsedFile(newPath); !exists || parsed.RelPath != "members" {
+		t.Errorf("migrated parse = %+v, %t, want it moved to members", parsed, exists)
+	}
+
+	if _, err := cm.HandleFileChange(&models.ChangeEvent{FilePath: newPath, EventType: "rename"}); err == nil {
+		t.Error("a rename without an old path was accepted")
+	}
+}
+
+func TestMatchRenameWithSharedHash(t *testing.T) {
+	cm := NewCacheManager()
+	const hash = "0123456789abcdef"
+	for _, path := range []string{"/p/old/users/route.go", "/p/old/orgs/route.go"} {
+		cm.rememberDelete(&deletedFile{path: path, hash: hash, deletedAt: time.Now()})
+	}
+	// Deleted too long ago to be matched
+	cm.rememberDelete(&deletedFile{path: "/p/stale/route.go", hash: "fedcba9876543210", deletedAt: time.Now().Add(-2 * renameWindow)})
+
+	tests := []struct {
+		hash    string
+		newPath string
+		want    string
+	}{
+		{hash, "/p/new/orgs/route.go", "/p/old/orgs/route.go"},
+		{hash, "/p/new/users/route.go", "/p/old/users/route.go"},
+		{hash, "/p/new/teams/route.go", ""},
+		{"fedcba9876543210", "/p/new/stale/route.go", ""},
+	}
+	for _, tt := range tests {
+		deleted, renamed := cm.matchRename(tt.hash, tt.newPath)
+		got := ""
+		if renamed {
+			got = deleted.path
+		}
+		if got != tt.want {
+			t.Errorf("matchRename(%s) = %q, want %q", tt.newPath, got, tt.want)
+		}
+	}
+}
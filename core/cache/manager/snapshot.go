@@ -0,0 +1,98 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/logger"
+	coreModels "github.com/tristendillon/conduit/core/models"
+)
+
+// Snapshot captures the state of every cache layer, so it can be restored later without
+// replaying file events. Kept file contents aren't included; they're read again on demand.
+func (cm *CacheManager) Snapshot() (*models.CacheSnapshot, error) {
+	snapshot := &models.CacheSnapshot{
+		TakenAt:       time.Now(),
+		Content:       []*models.ContentEntry{},
+		Parsed:        make(map[string]*coreModels.ParsedFile),
+		Dependencies:  []*models.DependencyNode{},
+		Generations:   cm.sortedGenerations(),
+		HashAlgorithm: models.CurrentHasher().Name(),
+	}
+
+	for _, entry := range cm.content.GetAllContent() {
+		snapshot.Content = append(snapshot.Content, entry)
+	}
+	sort.Slice(snapshot.Content, func(i, j int) bool {
+		return snapshot.Content[i].FilePath < snapshot.Content[j].FilePath
+	})
+
+	for filePath, parsed := range cm.parse.GetAllParsedFiles() {
+		parsedCopy := *parsed
+		snapshot.Parsed[filePath] = &parsedCopy
+	}
+
+	for _, node := range cm.deps.GetAllNodes() {
+		snapshot.Dependencies = append(snapshot.Dependencies, node)
+	}
+	sort.Slice(snapshot.Dependencies, func(i, j int) bool {
+		return snapshot.Dependencies[i].FilePath < snapshot.Dependencies[j].FilePath
+	})
+
+	if cm.registrySignature != nil {
+		signature := *cm.registrySignature
+		snapshot.RegistrySignature = &signature
+	}
+
+	logger.Debug("CacheManager: Took snapshot of %d files, %d parsed, %d nodes and %d generations",
+		len(snapshot.Content), len(snapshot.Parsed), len(snapshot.Dependencies), len(snapshot.Generations))
+	return snapshot, nil
+}
+
+// RestoreSnapshot clears every cache layer and fills it from snapshot. Dependency edges
+// and generation records are restored exactly as they were taken. A snapshot hashed with
+// another algorithm is rejected, since none of its hashes would match.
+func (cm *CacheManager) RestoreSnapshot(snapshot *models.CacheSnapshot) error {
+	if snapshot == nil {
+		return fmt.Errorf("snapshot cannot be nil")
+	}
+	if !models.SameHashAlgorithm(snapshot.HashAlgorithm) {
+		return fmt.Errorf("snapshot hashes use %s but the cache uses %s",
+			models.HashAlgorithmOrDefault(snapshot.HashAlgorithm), models.CurrentHasher().Name())
+	}
+	if err := cm.Clear(); err != nil {
+		return err
+	}
+
+	for _, entry := range snapshot.Content {
+		entryCopy := *entry
+		if err := cm.content.SetContent(entry.FilePath, &entryCopy); err != nil {
+			return fmt.Errorf("failed to restore content of %s: %w", entry.FilePath, err)
+		}
+	}
+
+	for filePath, parsed := range snapshot.Parsed {
+		parsedCopy := *parsed
+		if err := cm.parse.SetParsedFile(filePath, &parsedCopy); err != nil {
+			return fmt.Errorf("failed to restore parsed file %s: %w", filePath, err)
+		}
+	}
+
+	if err := cm.deps.RestoreNodes(snapshot.Dependencies); err != nil {
+		return fmt.Errorf("failed to restore dependency graph: %w", err)
+	}
+
+	for _, info := range snapshot.Generations {
+		cm.generation.RestoreGeneration(info)
+	}
+
+	if snapshot.RegistrySignature != nil {
+		signature := *snapshot.RegistrySignature
+		cm.registrySignature = &signature
+	}
+
+	logger.Debug("CacheManager: Restored snapshot taken %s", snapshot.TakenAt.Format(time.RFC3339))
+	return nil
+}
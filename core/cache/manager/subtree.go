@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+// InvalidateSubtree drops every cached entry under dirPath from all layers, as when a whole
+// folder is deleted or moved away. The plan lists the removed files as changed and every
+// file outside the directory that depended on them as affected. Removed files are remembered
+// so that creates of the same content elsewhere are treated as renames.
+func (cm *CacheManager) InvalidateSubtree(dirPath string) (*models.RegenerationPlan, error) {
+	prefix := models.CanonicalPrefix(dirPath)
+	inside := func(filePath string) bool {
+		return strings.HasPrefix(filePath, prefix)
+	}
+
+	// Entries can exist in one layer and not another, so collect paths from each of them
+	seen := make(map[string]bool)
+	var removed []string
+	collect := func(filePath string) {
+		if inside(filePath) && !seen[filePath] {
+			seen[filePath] = true
+			removed = append(removed, filePath)
+		}
+	}
+	for _, filePath := range cm.content.ListFiles() {
+		collect(filePath)
+	}
+	for filePath := range cm.parse.GetAllParsedFiles() {
+		collect(filePath)
+	}
+	for filePath := range cm.deps.ToAdjacencyList() {
+		collect(filePath)
+	}
+	sort.Strings(removed)
+
+	plan := newRegenerationPlan()
+	plan.ChangedFiles = removed
+
+	// Dependents have to be found before the nodes are removed from the graph
+	for _, filePath := range removed {
+		affected, err := cm.deps.GetAffectedFiles(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get affected files for %s: %w", filePath, err)
+		}
+
+		var outside []string
+		for _, affectedFile := range affected {
+			if inside(affectedFile) {
+				continue
+			}
+			outside = append(outside, affectedFile)
+			if _, exists := plan.Priority[affectedFile]; !exists {
+				plan.AffectedFiles = append(plan.AffectedFiles, affectedFile)
+				plan.Reasons[affectedFile] = fmt.Sprintf("dependency deleted: %s (directory %s removed)", filePath, dirPath)
+				plan.Priority[affectedFile] = 3 // High priority for deleted dependencies
+			}
+		}
+		plan.RegenerationMap[filePath] = outside
+	}
+
+	for _, filePath := range removed {
+		cm.rememberDelete(cm.snapshotFile(filePath))
+		cm.removeFile(filePath)
+	}
+
+	logger.Debug("CacheManager: Invalidated %d cached files under %s, affecting %d files", len(removed), dirPath, len(plan.AffectedFiles))
+	cm.finishPlan(plan)
+	return plan, nil
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sync"
+)
+
+// Hash algorithm names accepted by NewHasher
+const (
+	HashMD5    = "md5"
+	HashSHA256 = "sha256"
+)
+
+// Hasher computes the content hashes stored by the cache layers. Hashes are only
+// comparable when they come from the same algorithm, so its name is recorded with them.
+type Hasher interface {
+	Name() string
+	New() hash.Hash
+}
+
+type hashFunc struct {
+	name string
+	new  func() hash.Hash
+}
+
+func (h hashFunc) Name() string   { return h.name }
+func (h hashFunc) New() hash.Hash { return h.new() }
+
+var (
+	currentHasher Hasher = hashFunc{HashMD5, md5.New}
+	hasherMutex   sync.RWMutex
+)
+
+// NewHasher returns the hasher for an algorithm name; "" selects the default, MD5
+func NewHasher(name string) (Hasher, error) {
+	switch name {
+	case "", HashMD5:
+		return hashFunc{HashMD5, md5.New}, nil
+	case HashSHA256:
+		return hashFunc{HashSHA256, sha256.New}, nil
+	}
+	return nil, fmt.Errorf("unsupported hash algorithm %q (expected %q or %q)", name, HashMD5, HashSHA256)
+}
+
+// SetHasher replaces the hasher used for every cache hash in the process. It should be
+// called before anything is cached, since existing hashes aren't recomputed.
+func SetHasher(h Hasher) {
+	hasherMutex.Lock()
+	defer hasherMutex.Unlock()
+	currentHasher = h
+}
+
+// CurrentHasher returns the hasher used for cache hashes
+func CurrentHasher() Hasher {
+	hasherMutex.RLock()
+	defer hasherMutex.RUnlock()
+	return currentHasher
+}
+
+// HashBytes returns the hex encoded hash of data
+func HashBytes(data []byte) string {
+	h := CurrentHasher().New()
+	h.Write(data)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// HashReader returns the hex encoded hash of everything read from r
+func HashReader(r io.Reader) (string, error) {
+	h := CurrentHasher().New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// HashFile returns the hex encoded hash of a file's contents
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return HashReader(file)
+}
+
+// ShortHash abbreviates a hash to at most 8 runes for log and reason messages; it's safe
+// on hashes shorter than that, and a missing hash reads "none"
+func ShortHash(hash string) string {
+	if hash == "" {
+		return "none"
+	}
+	if runes := []rune(hash); len(runes) > 8 {
+		return string(runes[:8])
+	}
+	return hash
+}
+
+// HashAlgorithmOrDefault returns name, or the default algorithm for state recorded before
+// the algorithm was stored
+func HashAlgorithmOrDefault(name string) string {
+	if name == "" {
+		return HashMD5
+	}
+	return name
+}
+
+// SameHashAlgorithm reports whether hashes recorded under name can be compared with ones
+// from the current hasher
+func SameHashAlgorithm(name string) bool {
+	return HashAlgorithmOrDefault(name) == CurrentHasher().Name()
+}
@@ -0,0 +1,21 @@
+package models
+
+import "testing"
+
+func TestShortHash(t *testing.T) {
+	tests := []struct {
+		hash string
+		want string
+	}{
+		{"", "none"},
+		{"abc", "abc"},
+		{"01234567", "01234567"},
+		{"0123456789abcdef", "01234567"},
+		{"ééééééééé", "éééééééé"},
+	}
+	for _, tt := range tests {
+		if got := ShortHash(tt.hash); got != tt.want {
+			t.Errorf("ShortHash(%q) = %q, want %q", tt.hash, got, tt.want)
+		}
+	}
+}
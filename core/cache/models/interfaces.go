@@ -1,6 +1,8 @@
 package models
 
 import (
+	"io"
+
 	"github.com/tristendillon/conduit/core/models"
 )
 
@@ -12,17 +14,32 @@ type ContentCacheInterface interface {
 	// GetContent retrieves current content entry
 	GetContent(filePath string) (*ContentEntry, bool) // entry, exists
 
+	// GetContentBytes returns the contents kept when the file was last hashed, if any
+	GetContentBytes(filePath string) ([]byte, bool)
+
 	// SetContent manually sets content entry (for testing)
 	SetContent(filePath string, entry *ContentEntry) error
 
 	// RemoveContent removes entry for deleted files
 	RemoveContent(filePath string) error
 
+	// ListFiles returns the paths of all tracked files
+	ListFiles() []string
+
+	// GetAllContent returns a copy of every entry, keyed by file path
+	GetAllContent() map[string]*ContentEntry
+
+	// MarkWarmed starts counting lookups toward the warm hit rate
+	MarkWarmed()
+
 	// GetStats returns cache statistics
 	GetStats() *CacheStats
 
 	// Clear removes all entries
 	Clear() error
+
+	// Close stops any background cleanup
+	Close() error
 }
 
 // ParseCacheInterface manages parsed file data (Layer 2)
@@ -39,6 +56,12 @@ type ParseCacheInterface interface {
 	// GetDependencies extracts dependency information from parsed data
 	GetDependencies(filePath string) ([]string, error)
 
+	// GetAllParsedFiles returns a snapshot of every parsed file keyed by path
+	GetAllParsedFiles() map[string]*models.ParsedFile
+
+	// MarkWarmed starts counting lookups toward the warm hit rate
+	MarkWarmed()
+
 	// GetStats returns cache statistics
 	GetStats() *CacheStats
 
@@ -54,27 +77,71 @@ type DependencyGraphInterface interface {
 	// UpdateNode updates a single node in the graph
 	UpdateNode(filePath string, dependencies []string) error
 
+	// UpdateNodeEdges updates a single node in the graph with the imports behind its dependencies
+	UpdateNodeEdges(filePath string, edges []Edge) error
+
 	// GetAffectedFiles returns all files affected by a change
 	GetAffectedFiles(changedFile string) ([]string, error)
 
+	// GetAffectedFilesOrdered returns the files affected by a change up to maxDepth edges
+	// away (zero for no limit), ordered so each comes after the affected files it depends on
+	GetAffectedFilesOrdered(changedFile string, maxDepth int) ([]string, error)
+
 	// GetDependencies returns direct dependencies of a file
 	GetDependencies(filePath string) ([]string, error)
 
+	// SetNodeType records what kind of file a node is, adding the node if it's missing
+	SetNodeType(filePath string, nodeType NodeType) error
+
+	// GetEdges returns direct dependencies of a file with the imports that create them
+	GetEdges(filePath string) ([]Edge, error)
+
 	// GetDependents returns files that depend on this file
 	GetDependents(filePath string) ([]string, error)
 
+	// GetDependentsTransitive returns the direct and indirect dependents of a file up to
+	// maxDepth edges away (zero for no limit), nearest first
+	GetDependentsTransitive(filePath string, maxDepth int) ([]string, error)
+
+	// GetTransitiveDependencies returns the direct and indirect dependencies of a file,
+	// nearest first
+	GetTransitiveDependencies(filePath string) ([]string, error)
+
+	// FindPath returns the shortest dependency chain from one file to another, or an
+	// empty slice when there is none
+	FindPath(from, to string) ([]string, error)
+
 	// GetNode retrieves a dependency node
 	GetNode(filePath string) (*DependencyNode, bool)
 
+	// GetAllNodes returns a copy of every node, keyed by file path
+	GetAllNodes() map[string]*DependencyNode
+
+	// RestoreNodes replaces the graph with the given nodes and their edges
+	RestoreNodes(nodes []*DependencyNode) error
+
 	// RemoveNode removes a node and updates dependent relationships
 	RemoveNode(filePath string) error
 
 	// DetectCycles finds circular dependencies
 	DetectCycles() ([][]string, error)
 
-	// GetTopologicalOrder returns files in dependency order
+	// GetTopologicalOrder returns files in dependency order; on cycles it returns the
+	// acyclic portion with a *CycleError describing the remainder
 	GetTopologicalOrder() ([]string, error)
 
+	// ExportDOT writes the graph in Graphviz DOT format
+	ExportDOT(w io.Writer) error
+
+	// ExportMermaid writes the graph as a Mermaid flowchart
+	ExportMermaid(w io.Writer) error
+
+	// ExportJSON writes the graph as JSON
+	ExportJSON(w io.Writer) error
+
+	// ToAdjacencyList returns the graph as dependent -> dependencies
+	ToAdjacencyList() map[string][]string
+
 	// GetStats returns graph statistics
 	GetStats() *CacheStats
 
@@ -99,6 +166,12 @@ type GenerationCacheInterface interface {
 	// GetOutdatedFiles returns all files needing regeneration
 	GetOutdatedFiles() ([]string, error)
 
+	// GetAllGenerations returns a copy of every generation record, keyed by source path
+	GetAllGenerations() map[string]*GenerationInfo
+
+	// RestoreGeneration adds a record persisted by a previous run unless the source already has one
+	RestoreGeneration(info *GenerationInfo)
+
 	// GetStats returns cache statistics
 	GetStats() *CacheStats
 
@@ -111,9 +184,21 @@ type CacheManagerInterface interface {
 	// HandleFileChange processes a file system change event
 	HandleFileChange(event *ChangeEvent) (*RegenerationPlan, error)
 
+	// HandleFileChanges processes a batch of change events into a single merged plan
+	HandleFileChanges(events []*ChangeEvent) (*RegenerationPlan, error)
+
+	// TrackedFilesUnder returns the tracked files inside a directory
+	TrackedFilesUnder(dir string) []string
+
+	// InvalidateSubtree drops every cached entry under a directory and plans the regeneration of its dependents
+	InvalidateSubtree(dirPath string) (*RegenerationPlan, error)
+
 	// GetParsedFile retrieves parsed file (checks content, then parse cache)
 	GetParsedFile(filePath string) (*models.ParsedFile, bool, error)
 
+	// ReadSource returns a file's contents, from the content cache when they were kept
+	ReadSource(filePath string) ([]byte, error)
+
 	// SetParsedFile stores parsed file and updates dependency graph
 	SetParsedFile(filePath string, parsed *models.ParsedFile) error
 
@@ -123,17 +208,33 @@ type CacheManagerInterface interface {
 	// GetRegenerationPlan returns what needs to be regenerated
 	GetRegenerationPlan(changedFiles []string) (*RegenerationPlan, error)
 
+	// Explain plans the regeneration of a file and reports the dependency chains and hashes behind it
+	Explain(filePath string) (*RegenerationExplanation, error)
+
+	// GetPlannedOutputs returns the generated files a plan will rewrite, as far as known
+	GetPlannedOutputs(plan *RegenerationPlan) []string
+
 	// GetAffectedFiles returns files affected by changes
 	GetAffectedFiles(changedFile string) ([]string, error)
 
+	// Subscribe calls fn asynchronously for every invalidation, regeneration plan and
+	// recorded generation until the returned unsubscribe func is called
+	Subscribe(fn func(event CacheEvent)) (unsubscribe func())
+
+	// Snapshot captures the state of every cache layer
+	Snapshot() (*CacheSnapshot, error)
+
+	// RestoreSnapshot replaces the state of every cache layer with a snapshot
+	RestoreSnapshot(snapshot *CacheSnapshot) error
+
 	// ValidateIntegrity checks cache consistency across layers
-	ValidateIntegrity() error
+	ValidateIntegrity() (*IntegrityReport, error)
 
 	// GetStats returns comprehensive cache statistics
 	GetStats() map[string]*CacheStats
 
-	// WarmCache initializes cache from file system
-	WarmCache(rootDir string, excludePaths []string) error
+	// WarmCache hashes and parses every route file under rootDir and builds the dependency graph
+	WarmCache(rootDir string, excludePaths []string, moduleName string) (*WarmCacheReport, error)
 
 	// GetRegistrySignature gets cached registry signature
 	GetRegistrySignature() (*RegistrySignature, bool)
@@ -144,6 +245,30 @@ type CacheManagerInterface interface {
 	// NeedsRegistryRegeneration checks if registry needs regeneration
 	NeedsRegistryRegeneration(currentRoutes []string) (bool, error)
 
+	// SetTemplateHash sets the hash of the templates outputs are generated from
+	SetTemplateHash(hash string)
+
+	// GetDependencyGraph exposes the dependency graph layer
+	GetDependencyGraph() DependencyGraphInterface
+
+	// LoadState reads persisted state from a previous run
+	LoadState(path string) error
+
+	// SaveState persists the current state for the next run
+	SaveState(path string) error
+
+	// ExportManifest writes the generation record of every source file to path
+	ExportManifest(path string) error
+
+	// ImportManifest restores the generation records in path whose source content is unchanged
+	ImportManifest(path string) error
+
+	// GetPreviousStats returns per-layer stats recorded by the previous run
+	GetPreviousStats() (map[string]*CacheStats, bool)
+
 	// Clear resets all cache layers
 	Clear() error
+
+	// Close stops the background work of the cache layers
+	Close() error
 }
\ No newline at end of file
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// CanonicalPath returns the key the cache layers store a file under: absolute, with
+// symlinks resolved and forward slashes, so every spelling of a path reaches one entry.
+// A path that no longer exists is resolved through its nearest existing parent. Parsed
+// files keep the path they were parsed from for display.
+func CanonicalPath(path string) string {
+	if path == "" {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(resolveSymlinks(abs))
+}
+
+// NodeKey returns the key the dependency graph stores a node under. Absolute file paths
+// are canonicalized like CanonicalPath; anything else, such as an import path, is kept as
+// it is, since a relative file path can't be told apart from one.
+func NodeKey(path string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	return CanonicalPath(path)
+}
+
+// resolveSymlinks evaluates the symlinks in an absolute path, keeping the missing tail of a
+// path that doesn't exist as it is
+func resolveSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path
+	}
+	return filepath.Join(resolveSymlinks(parent), filepath.Base(path))
+}
+
+// CanonicalPrefix returns the key prefix shared by every file under dir
+func CanonicalPrefix(dir string) string {
+	prefix := CanonicalPath(dir)
+	if strings.HasSuffix(prefix, "/") {
+		return prefix
+	}
+	return prefix + "/"
+}
@@ -1,7 +1,12 @@
 package models
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/tristendillon/conduit/core/models"
 )
 
 // NodeType represents the type of file in the dependency graph
@@ -31,26 +36,80 @@ func (nt NodeType) String() string {
 
 // ContentEntry tracks file content state (Layer 1)
 type ContentEntry struct {
-	FilePath    string    `json:"file_path"`
+	FilePath    string    `json:"file_path"` // as given when the entry was recorded; it is stored under CanonicalPath
 	ContentHash string    `json:"content_hash"`
 	ModTime     time.Time `json:"mod_time"`
 	Size        int64     `json:"size"`
 	Exists      bool      `json:"exists"`
+	CheckedAt   time.Time `json:"checked_at,omitempty"` // when a missing file was last looked for
 }
 
 // DependencyNode represents a node in the dependency graph (Layer 3)
 type DependencyNode struct {
 	FilePath     string   `json:"file_path"`
 	NodeType     NodeType `json:"node_type"`
-	Dependencies []string `json:"dependencies"` // files this depends on
-	Dependents   []string `json:"dependents"`   // files that depend on this
-	ContentHash  string   `json:"content_hash"` // current content hash
+	Edges        []Edge   `json:"edges,omitempty"` // imports this depends on, one per dependency
+	Dependencies []string `json:"dependencies"`    // files this depends on, the targets of Edges
+	Dependents   []string `json:"dependents"`      // files that depend on this
+	ContentHash  string   `json:"content_hash"`    // current content hash
+}
+
+// EdgeKind is the kind of import a dependency edge comes from
+type EdgeKind string
+
+const (
+	EdgeLocal    EdgeKind = "local"    // a package of the project's own module
+	EdgeExternal EdgeKind = "external" // a third-party package
+)
+
+// Edge is a dependency of a node along with the import that creates it. Edges added
+// without import information only have a Target.
+type Edge struct {
+	Target     string   `json:"target"`
+	Alias      string   `json:"alias,omitempty"`
+	Kind       EdgeKind `json:"kind,omitempty"`
+	SourceLine int      `json:"source_line,omitempty"` // line of the import in the dependent file
+}
+
+// EdgesTo returns edges without import information for each target
+func EdgesTo(targets []string) []Edge {
+	edges := make([]Edge, len(targets))
+	for i, target := range targets {
+		edges[i] = Edge{Target: target}
+	}
+	return edges
+}
+
+// EdgeTargets returns the target of each edge
+func EdgeTargets(edges []Edge) []string {
+	targets := make([]string, len(edges))
+	for i, edge := range edges {
+		targets[i] = edge.Target
+	}
+	return targets
+}
+
+// ImportEdges returns an edge for every local and external import of a parsed file
+func ImportEdges(parsed *models.ParsedFile) []Edge {
+	edges := []Edge{}
+	if parsed == nil || parsed.Dependencies == nil {
+		return edges
+	}
+	for _, local := range parsed.Dependencies.LocalImports {
+		edges = append(edges, Edge{Target: local.ImportPath, Alias: local.Alias, Kind: EdgeLocal, SourceLine: local.Line})
+	}
+	for _, external := range parsed.Dependencies.ExternalImports {
+		decl := parsed.Dependencies.ExternalDecls[external]
+		edges = append(edges, Edge{Target: external, Alias: decl.Alias, Kind: EdgeExternal, SourceLine: decl.Line})
+	}
+	return edges
 }
 
 // GenerationInfo tracks generation state for output files (Layer 4)
 type GenerationInfo struct {
 	SourcePath      string    `json:"source_path"`
 	OutputPath      string    `json:"output_path"`
+	OutputHash      string    `json:"output_hash"`      // hash of the output file when written
 	SourceHash      string    `json:"source_hash"`      // hash when last generated
 	TemplateHash    string    `json:"template_hash"`    // template version used
 	DependencyHash  string    `json:"dependency_hash"`  // combined hash of all dependencies
@@ -65,6 +124,8 @@ type RegenerationPlan struct {
 	RegenerationMap map[string][]string   `json:"regeneration_map"` // source -> affected outputs
 	Reasons         map[string]string     `json:"reasons"`          // why each file needs regeneration
 	Priority        map[string]int        `json:"priority"`         // regeneration priority
+	StaleOutputs    []string              `json:"stale_outputs,omitempty"` // outputs of renamed or removed sources to delete
+	Outputs         map[string][]string   `json:"outputs,omitempty"`       // affected source -> output paths it was last generated to
 }
 
 // CacheStats provides metrics about cache performance
@@ -76,21 +137,171 @@ type CacheStats struct {
 	DependencyNodes  int     `json:"dependency_nodes"`
 	GenerationEntries int    `json:"generation_entries"`
 	LastUpdate       time.Time `json:"last_update"`
+	IsWarmed         bool    `json:"is_warmed"`     // whether warm lookups are being counted
+	WarmHits         int64   `json:"warm_hits"`     // hits since the cache was warmed
+	WarmMisses       int64   `json:"warm_misses"`   // misses since the cache was warmed
+	WarmHitRate      float64 `json:"warm_hit_rate"` // hit rate since the cache was warmed
+	Evictions        int64   `json:"evictions"`     // entries dropped to stay within the layer's limits
+	Expired          int64   `json:"expired"`       // entries dropped by cleanup once they expired
+}
+
+// ParseCacheConfig bounds the parse cache; zero values mean no limit
+type ParseCacheConfig struct {
+	MaxEntries int   `json:"max_entries"` // most parsed files kept
+	MaxBytes   int64 `json:"max_bytes"`   // approximate bytes of parsed data kept
+}
+
+// ContentCacheConfig makes the content cache keep file contents so they can be parsed
+// without another read; with a zero MaxFileBytes no contents are kept
+type ContentCacheConfig struct {
+	MaxFileBytes int64 `json:"max_file_bytes"` // largest file whose contents are kept
+	MaxBytes     int64 `json:"max_bytes"`      // total contents kept before the least recently used are dropped; zero means no limit
+	MaxEntries   int   `json:"max_entries"`    // files tracked before the least recently used are evicted; zero means no limit
+	// CleanupInterval is how often expired entries are removed in the background; zero
+	// leaves them until they're looked up
+	CleanupInterval time.Duration `json:"cleanup_interval"`
+}
+
+// CacheConfig configures the cache layers that have limits
+type CacheConfig struct {
+	Content ContentCacheConfig `json:"content"`
+	Parse   ParseCacheConfig   `json:"parse"`
+}
+
+// CalculateHitRate returns hits as a percentage of all lookups, or 0 without lookups
+func CalculateHitRate(hits, misses int64) float64 {
+	total := hits + misses
+	if total == 0 {
+		return 0
+	}
+	return float64(hits) / float64(total) * 100
 }
 
 // RegistrySignature represents the structural signature of the routes registry
 type RegistrySignature struct {
 	RouteCount int      `json:"route_count"`
-	RoutePaths []string `json:"route_paths"` // sorted list of route folder paths
+	RoutePaths []string `json:"route_paths"` // sorted list of route entries (folder path and methods)
 	Signature  string   `json:"signature"`   // hash of the structural data
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
+// NewRegistrySignature hashes the sorted route entries into a registry signature
+func NewRegistrySignature(entries []string) *RegistrySignature {
+	sortedEntries := make([]string, len(entries))
+	copy(sortedEntries, entries)
+	sort.Strings(sortedEntries)
+
+	// Entries starting with # describe settings rather than routes
+	routeCount := 0
+	for _, entry := range sortedEntries {
+		if !strings.HasPrefix(entry, "#") {
+			routeCount++
+		}
+	}
+
+	return &RegistrySignature{
+		RouteCount: routeCount,
+		RoutePaths: sortedEntries,
+		Signature:  HashBytes([]byte(strings.Join(sortedEntries, "|"))),
+		UpdatedAt:  time.Now(),
+	}
+}
+
 // ChangeEvent represents a file system change
 type ChangeEvent struct {
 	FilePath  string    `json:"file_path"`
-	EventType string    `json:"event_type"` // "write", "delete", "create"
+	EventType string    `json:"event_type"` // "write", "delete", "create", "rename"
+	OldPath   string    `json:"old_path,omitempty"` // previous path of a renamed file
 	Timestamp time.Time `json:"timestamp"`
 	OldHash   string    `json:"old_hash,omitempty"`
 	NewHash   string    `json:"new_hash,omitempty"`
-}
\ No newline at end of file
+}
+
+// CacheEventType is the kind of decision a CacheEvent reports
+type CacheEventType string
+
+const (
+	CacheEventInvalidated CacheEventType = "invalidated"  // a file's cached entries were dropped
+	CacheEventPlanCreated CacheEventType = "plan_created" // a regeneration plan was computed
+	CacheEventGenerated   CacheEventType = "generated"    // a generated output was recorded
+)
+
+// CacheEvent is delivered to cache manager subscribers
+type CacheEvent struct {
+	Type       CacheEventType    `json:"type"`
+	FilePath   string            `json:"file_path,omitempty"`   // invalidated file, or source of a generated output
+	OutputPath string            `json:"output_path,omitempty"` // generated output
+	Plan       *RegenerationPlan `json:"plan,omitempty"`        // created plan
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// WarmCacheReport summarizes a cache warming pass
+type WarmCacheReport struct {
+	FilesHashed  int           `json:"files_hashed"`  // route files run through the content cache
+	FilesParsed  int           `json:"files_parsed"`  // route files parsed because they were new or changed
+	FilesSkipped int           `json:"files_skipped"` // unchanged route files whose cached parse was reused
+	FilesFailed  int           `json:"files_failed"`  // route files that couldn't be hashed or parsed
+	Duration     time.Duration `json:"duration"`
+}
+
+// IntegrityReport lists cross-layer inconsistencies found by ValidateIntegrity
+type IntegrityReport struct {
+	OrphanParseEntries []string   `json:"orphan_parse_entries"` // parsed files with no content entry
+	DanglingNodes      []string   `json:"dangling_nodes"`       // graph nodes that aren't parsed and have no dependents
+	Cycles             [][]string `json:"cycles"`               // dependency cycles
+}
+
+// OK reports whether no inconsistencies were found
+func (r *IntegrityReport) OK() bool {
+	return len(r.OrphanParseEntries) == 0 && len(r.DanglingNodes) == 0 && len(r.Cycles) == 0
+}
+
+// PersistedState is the cache state carried between conduit runs
+type PersistedState struct {
+	SavedAt           time.Time              `json:"saved_at"`
+	Stats             map[string]*CacheStats `json:"stats"`                        // per-layer stats from the previous run
+	RegistrySignature *RegistrySignature     `json:"registry_signature,omitempty"` // signature of the last generated registry
+	Generations       []*GenerationInfo      `json:"generations,omitempty"`        // generation records, sorted by source path
+	HashAlgorithm     string                 `json:"hash_algorithm,omitempty"`     // algorithm of every hash above; empty means md5
+}
+
+// RegenerationExplanation describes why a file does or doesn't need regenerating
+type RegenerationExplanation struct {
+	File        string              `json:"file"`
+	Plan        *RegenerationPlan   `json:"plan"`                 // plan for the file as if it changed
+	Chains      map[string][]string `json:"chains"`               // affected file -> dependents path from File to it
+	CurrentHash string              `json:"current_hash"`         // content hash now, empty when missing
+	Generation  *GenerationInfo     `json:"generation,omitempty"` // stored record of the last generation
+}
+
+// GenerationManifestVersion is the generation manifest format understood by this version
+const GenerationManifestVersion = 1
+
+// GenerationManifest records how every source file was last generated, so a later run
+// can skip sources whose content hasn't changed since
+type GenerationManifest struct {
+	Version       int               `json:"version"`
+	HashAlgorithm string            `json:"hash_algorithm"`
+	Generations   []*GenerationInfo `json:"generations"` // sorted by source path
+}
+
+// CacheSnapshot is the state of every cache layer at one point in time. Slices are
+// sorted by path so equal states encode identically.
+type CacheSnapshot struct {
+	TakenAt           time.Time                     `json:"taken_at"`
+	Content           []*ContentEntry               `json:"content"`                      // including files recently found missing
+	Parsed            map[string]*models.ParsedFile `json:"parsed"`                       // parsed files by path
+	Dependencies      []*DependencyNode             `json:"dependencies"`                 // nodes with both edge directions
+	Generations       []*GenerationInfo             `json:"generations"`                  // generation records
+	RegistrySignature *RegistrySignature            `json:"registry_signature,omitempty"` // signature of the last generated registry
+	HashAlgorithm     string                        `json:"hash_algorithm"`               // algorithm of every hash above
+}
+
+// CycleError reports files that could not be ordered because of dependency cycles
+type CycleError struct {
+	Remaining []string `json:"remaining"` // files in or behind a cycle, sorted
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("dependency graph contains cycles (%d files could not be ordered)", len(e.Remaining))
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+)
+
+// PrometheusMetric is one metric family reported for every cache layer
+type PrometheusMetric struct {
+	Name  string
+	Kind  string // "counter" or "gauge"
+	Help  string
+	Value func(stats *models.CacheStats) float64
+}
+
+// PrometheusMetrics are rendered in this order; the names are part of the scrape contract,
+// so renamed families keep being exported under their old name, marked deprecated
+var PrometheusMetrics = []PrometheusMetric{
+	{"conduit_cache_hits_total", "counter", "Cache lookups that found an entry.",
+		func(s *models.CacheStats) float64 { return float64(s.CacheHits) }},
+	{"conduit_cache_misses_total", "counter", "Cache lookups that found no entry.",
+		func(s *models.CacheStats) float64 { return float64(s.CacheMisses) }},
+	{"conduit_cache_hit_rate", "gauge", "Hits as a fraction of all lookups.",
+		func(s *models.CacheStats) float64 { return s.HitRate / 100 }},
+	{"conduit_cache_hit_ratio", "gauge", "Deprecated: use conduit_cache_hit_rate. Hits as a fraction of all lookups.",
+		func(s *models.CacheStats) float64 { return s.HitRate / 100 }},
+	{"conduit_cache_evictions_total", "counter", "Entries dropped to stay within the layer's limits.",
+		func(s *models.CacheStats) float64 { return float64(s.Evictions) }},
+	{"conduit_cache_expired_total", "counter", "Entries removed by cleanup once they expired.",
+		func(s *models.CacheStats) float64 { return float64(s.Expired) }},
+	{"conduit_cache_entries", "gauge", "Files currently held by the layer.",
+		func(s *models.CacheStats) float64 { return float64(s.TotalFiles) }},
+	{"conduit_cache_files", "gauge", "Deprecated: use conduit_cache_entries. Files currently held by the layer.",
+		func(s *models.CacheStats) float64 { return float64(s.TotalFiles) }},
+	{"conduit_cache_dependency_nodes", "gauge", "Nodes in the dependency graph.",
+		func(s *models.CacheStats) float64 { return float64(s.DependencyNodes) }},
+	{"conduit_cache_generation_entries", "gauge", "Generated outputs being tracked.",
+		func(s *models.CacheStats) float64 { return float64(s.GenerationEntries) }},
+}
+
+// ExportPrometheus writes the global cache stats in the Prometheus text exposition format,
+// one sample per layer labelled layer="content|parse|dependency|generation"
+func ExportPrometheus(w io.Writer) error {
+	return writePrometheus(w, GetCacheManager().GetStats())
+}
+
+func writePrometheus(w io.Writer, stats map[string]*models.CacheStats) error {
+	layers := make([]string, 0, len(stats))
+	for layer := range stats {
+		layers = append(layers, layer)
+	}
+	sort.Strings(layers)
+
+	for _, metric := range PrometheusMetrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", metric.Name, metric.Help, metric.Name, metric.Kind); err != nil {
+			return err
+		}
+		for _, layer := range layers {
+			if _, err := fmt.Fprintf(w, "%s{layer=%q} %g\n", metric.Name, layer, metric.Value(stats[layer])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PrometheusHandler serves ExportPrometheus, for mounting at /metrics
+func PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := ExportPrometheus(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
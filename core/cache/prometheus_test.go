@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tristendillon/conduit/core/cache/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestWritePrometheusGolden(t *testing.T) {
+	stats := map[string]*models.CacheStats{
+		"content":    {TotalFiles: 12, CacheHits: 40, CacheMisses: 10, HitRate: 80, Evictions: 2, Expired: 1},
+		"parse":      {TotalFiles: 9, CacheHits: 30, CacheMisses: 3, HitRate: 90.9090909},
+		"dependency": {TotalFiles: 15, DependencyNodes: 15},
+		"generation": {TotalFiles: 9, GenerationEntries: 9},
+	}
+
+	var out bytes.Buffer
+	if err := writePrometheus(&out, stats); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "prometheus.golden")
+	if *update {
+		if err := os.WriteFile(golden, out.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("writePrometheus() output differs from %s; run go test -update if the change is intended\ngot:\n%s", golden, out.String())
+	}
+}
@@ -1,42 +1,170 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 
+	"github.com/BurntSushi/toml"
 	"github.com/tristendillon/conduit/core/logger"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	AppName string  `yaml:"app_name"`
-	Server  Server  `yaml:"server"`
-	Codegen Codegen `yaml:"codegen"`
+	AppName string  `yaml:"app_name" json:"app_name" toml:"app_name"`
+	Server  Server  `yaml:"server" json:"server" toml:"server"`
+	Codegen Codegen `yaml:"codegen" json:"codegen" toml:"codegen"`
+	Cache   Cache   `yaml:"cache" json:"cache" toml:"cache"`
+	Watcher Watcher `yaml:"watcher" json:"watcher" toml:"watcher"`
+}
+
+type Watcher struct {
+	// Mode is "fsnotify" (default) or "poll" for filesystems that don't deliver events
+	Mode string `yaml:"mode" json:"mode" toml:"mode"`
+	// PollIntervalMs is how often the poll watcher rescans the project
+	PollIntervalMs int `yaml:"poll_interval_ms" json:"poll_interval_ms" toml:"poll_interval_ms"`
+}
+
+type Cache struct {
+	// HitRateDropThreshold is the drop in warm hit rate, in percentage points, between runs
+	// that triggers a warning
+	HitRateDropThreshold float64 `yaml:"hit_rate_drop_threshold" json:"hit_rate_drop_threshold" toml:"hit_rate_drop_threshold"`
+	// ParseMaxEntries and ParseMaxBytes bound the parsed route cache; zero means unbounded
+	ParseMaxEntries int   `yaml:"parse_max_entries" json:"parse_max_entries" toml:"parse_max_entries"`
+	ParseMaxBytes   int64 `yaml:"parse_max_bytes" json:"parse_max_bytes" toml:"parse_max_bytes"`
+	// ContentMaxFileBytes keeps the contents of route files up to this size in memory so they
+	// are read once for hashing and parsing; zero disables it. ContentMaxBytes bounds the
+	// total kept, dropping the least recently used contents first; zero means unbounded.
+	ContentMaxFileBytes int64 `yaml:"content_max_file_bytes" json:"content_max_file_bytes" toml:"content_max_file_bytes"`
+	ContentMaxBytes     int64 `yaml:"content_max_bytes" json:"content_max_bytes" toml:"content_max_bytes"`
+	// ContentMaxEntries bounds the number of files tracked by the content cache, evicting
+	// the least recently used first; zero means unbounded
+	ContentMaxEntries int `yaml:"content_max_entries" json:"content_max_entries" toml:"content_max_entries"`
+	// ContentCleanupIntervalMs is how often expired content entries are removed in the
+	// background; zero leaves them until they're looked up
+	ContentCleanupIntervalMs int `yaml:"content_cleanup_interval_ms" json:"content_cleanup_interval_ms" toml:"content_cleanup_interval_ms"`
+	// HashAlgorithm is how file contents are hashed: "md5" (default, fastest) or "sha256"
+	// where MD5 isn't allowed
+	HashAlgorithm string `yaml:"hash_algorithm" json:"hash_algorithm" toml:"hash_algorithm"`
 }
 
 type Server struct {
-	Host string `yaml:"host"`
-	Port int    `yaml:"port"`
+	Host string `yaml:"host" json:"host" toml:"host"`
+	Port int    `yaml:"port" json:"port" toml:"port"`
+	// DebounceMs is how long the dev watcher waits for changes to settle before regenerating
+	DebounceMs int `yaml:"debounce_ms" json:"debounce_ms" toml:"debounce_ms"`
 }
 
 type Codegen struct {
-	Go struct {
-		Output string `yaml:"output"`
-	} `yaml:"go"`
+	Workers int `yaml:"workers" json:"workers" toml:"workers"`
+	// Strict makes conduit generate fail when any warning was emitted
+	Strict bool `yaml:"strict" json:"strict" toml:"strict"`
+	// MaxFileBytes is the generated file size above which conduit warns and splits the registry
+	// and its group files
+	MaxFileBytes int `yaml:"max_file_bytes" json:"max_file_bytes" toml:"max_file_bytes"`
+	// MaxDepth is the route folder nesting above which conduit warns
+	MaxDepth int `yaml:"max_depth" json:"max_depth" toml:"max_depth"`
+	Go       struct {
+		Output string `yaml:"output" json:"output" toml:"output"`
+		// RouteOverrides maps route folder prefixes such as "api/v2" to the directory their
+		// generated route files are written to instead of Output; the longest prefix wins
+		RouteOverrides map[string]string `yaml:"route_overrides" json:"route_overrides" toml:"route_overrides"`
+		// Mode is "multi-file" (default) or "single-file"
+		Mode string `yaml:"mode" json:"mode" toml:"mode"`
+		// Router is the router the generated code targets: "stdlib" (default) or "chi"
+		Router string `yaml:"router" json:"router" toml:"router"`
+		// Metrics wraps every handler with request metrics and serves them at /metrics
+		Metrics bool `yaml:"metrics" json:"metrics" toml:"metrics"`
+		// RegistryGroupDepth splits the registry into one registration function and file per
+		// route subtree at this folder depth; zero keeps a single registry
+		RegistryGroupDepth int `yaml:"registry_group_depth" json:"registry_group_depth" toml:"registry_group_depth"`
+		// ProvidedPackages are import path prefixes of local packages that are referenced
+		// at their original path instead of being copied into the generated tree
+		ProvidedPackages []string `yaml:"provided_packages" json:"provided_packages" toml:"provided_packages"`
+		// Health adds a GET /__conduit/health route reporting the conduit version unless
+		// the project defines that route itself
+		Health bool `yaml:"health" json:"health" toml:"health"`
+		// TemplateDir is a directory, relative to the project root, whose templates replace
+		// the built-in templates at the same path, e.g. dev/routes_registry.go.tmpl
+		TemplateDir string `yaml:"template_dir" json:"template_dir" toml:"template_dir"`
+	} `yaml:"go" json:"go" toml:"go"`
 	Typescript struct {
-		Output string `yaml:"output"`
-	} `yaml:"typescript"`
+		Output string `yaml:"output" json:"output" toml:"output"`
+	} `yaml:"typescript" json:"typescript" toml:"typescript"`
+}
+
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+const (
+	GoModeMultiFile  = "multi-file"
+	GoModeSingleFile = "single-file"
+)
+
+const (
+	RouterStdlib = "stdlib"
+	RouterChi    = "chi"
+)
+
+const (
+	WatcherModeFSNotify = "fsnotify"
+	WatcherModePoll     = "poll"
+)
+
+// FileNames are the config files conduit looks for in the project root. When several
+// exist the first in this order wins.
+var FileNames = []string{"conduit.yaml", "conduit.json", "conduit.toml"}
+
+// IsConfigFile reports whether name is one of the config file names
+func IsConfigFile(name string) bool {
+	return slices.Contains(FileNames, name)
+}
+
+// GoOutputDirs returns the default Go output directory followed by the distinct route
+// override directories in sorted order
+func (c *Config) GoOutputDirs() []string {
+	dirs := []string{c.Codegen.Go.Output}
+	for _, dir := range c.Codegen.Go.RouteOverrides {
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	slices.Sort(dirs[1:])
+	return dirs
+}
+
+// OutputDirs returns every directory conduit generates into: the Go output directories
+// followed by the TypeScript one
+func (c *Config) OutputDirs() []string {
+	dirs := c.GoOutputDirs()
+	if c.Codegen.Typescript.Output != "" && !slices.Contains(dirs, c.Codegen.Typescript.Output) {
+		dirs = append(dirs, c.Codegen.Typescript.Output)
+	}
+	return dirs
 }
 
 func Default() *Config {
-	return &Config{
+	cfg := &Config{
 		AppName: "conduit",
 		Server: Server{
-			Host: "localhost",
-			Port: 8080,
+			Host:       "localhost",
+			Port:       8080,
+			DebounceMs: 500,
+		},
+		Watcher: Watcher{
+			Mode:           WatcherModeFSNotify,
+			PollIntervalMs: 1000,
 		},
 	}
+	cfg.Codegen.Go.Health = true
+	return cfg
 }
 
 func Load() (*Config, error) {
@@ -45,35 +173,124 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("cannot determine working dir: %w", err)
 	}
 
-	paths := []string{
-		filepath.Join(wd, "conduit.yaml"),
-	}
-
-	var filePath string
-	for _, p := range paths {
-		if _, err := os.Stat(p); err == nil {
-			filePath = p
-			break
-		}
+	filePath, shadowed := FindFile(wd)
+	if len(shadowed) > 0 {
+		warnShadowedOnce.Do(func() {
+			logger.Warn("Loaded config from %s; ignoring %s", filepath.Base(filePath), strings.Join(shadowed, ", "))
+		})
 	}
 
 	if filePath == "" {
 		logger.Debug("No config file found, using default config")
 		config := Default()
+		if err := applyEnvOverrides(config); err != nil {
+			return nil, err
+		}
 		return config, nil
 	}
 
-	data, err := os.ReadFile(filePath)
+	return LoadFromFile(filePath)
+}
+
+// FindFile returns the config file Load reads from dir, or "" when there is none, along
+// with the names of the other config files in dir it ignores
+func FindFile(dir string) (string, []string) {
+	var filePath string
+	var shadowed []string
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if filePath == "" {
+			filePath = path
+		} else {
+			shadowed = append(shadowed, name)
+		}
+	}
+	return filePath, shadowed
+}
+
+// LoadFromFile loads the config file at path, in the format its extension names, and
+// applies the environment overrides before validating it
+func LoadFromFile(path string) (*Config, error) {
+	format, err := FormatFromExtension(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file %s: %w", filePath, err)
+		return nil, err
 	}
 
-	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse yaml: %w", err)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
 	}
-	logger.Debug("Config file found: %s", filePath)
-	logger.Debug("Config: %+v", cfg)
 
-	return &cfg, nil
+	cfg, err := LoadFromBytes(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := applyEnvOverrides(cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	logger.Debug("Config file found: %s", path)
+	logger.Debug("Config: %+v", *cfg)
+
+	return cfg, nil
+}
+
+// FormatFromExtension returns the config format of a file from its extension
+func FormatFromExtension(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported config file extension: %s", path)
+	}
+}
+
+// warnShadowedOnce keeps the multiple-config-files warning to once per run, since config is loaded often
+var warnShadowedOnce sync.Once
+
+// LoadFromBytes parses config data in the given format ("yaml", "json" or "toml"),
+// expanding ${VAR} and ${VAR:-default} references to environment variables first.
+// Keys missing from the data keep their Default() values.
+func LoadFromBytes(data []byte, format string) (*Config, error) {
+	cfg := Default()
+
+	data, err := expandEnv(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := decode(data, format, cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// decode unmarshals data in the given format into cfg
+func decode(data []byte, format string, cfg *Config) error {
+	switch format {
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return fmt.Errorf("failed to parse yaml: %w", err)
+		}
+	case FormatJSON:
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return fmt.Errorf("failed to parse json: %w", err)
+		}
+	case FormatTOML:
+		if err := toml.Unmarshal(data, cfg); err != nil {
+			return fmt.Errorf("failed to parse toml: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported config format: %q", format)
+	}
+	return nil
 }
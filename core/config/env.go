@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envPattern matches ${VAR} and ${VAR:-default}
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// expandEnv substitutes environment variables in raw config data before it is parsed,
+// so numeric fields like server.port can be set from the environment too. Variables
+// that are unset and have no default are reported together in one error.
+func expandEnv(data []byte) ([]byte, error) {
+	var missing []string
+	expanded := envPattern.ReplaceAllFunc(data, func(match []byte) []byte {
+		groups := envPattern.FindSubmatch(match)
+		name := string(groups[1])
+		if value, ok := os.LookupEnv(name); ok {
+			return []byte(value)
+		}
+		// ${VAR:-} is an explicit empty default, so check for the separator rather than the value
+		if strings.Contains(string(match), ":-") {
+			return groups[2]
+		}
+		missing = append(missing, name)
+		return match
+	})
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("environment variable(s) %s not set and no default given (use ${VAR:-default})", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts every environment override; the rest is the uppercased yaml key path
+// with dots replaced by underscores, e.g. CONDUIT_SERVER_PORT for server.port
+const EnvPrefix = "CONDUIT_"
+
+// EnvOverride describes one config field that can be set from the environment
+type EnvOverride struct {
+	Name string // environment variable, e.g. CONDUIT_CODEGEN_GO_OUTPUT
+	Key  string // dotted config key, e.g. codegen.go.output
+	Type string // Go kind of the field; lists are comma-separated
+}
+
+// EnvOverrides lists every supported override in config field order
+func EnvOverrides() []EnvOverride {
+	var overrides []EnvOverride
+	walkFields(reflect.ValueOf(Default()).Elem(), "", func(key string, field reflect.Value) {
+		overrides = append(overrides, EnvOverride{Name: envName(key), Key: key, Type: field.Type().String()})
+	})
+	return overrides
+}
+
+// applyEnvOverrides sets every field whose CONDUIT_* variable is present in the environment
+func applyEnvOverrides(cfg *Config) error {
+	var problems []string
+	walkFields(reflect.ValueOf(cfg).Elem(), "", func(key string, field reflect.Value) {
+		name := envName(key)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if err := setField(field, value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+	})
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid environment override(s):\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+func envName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// walkFields calls fn for every settable leaf field, keyed by its dotted yaml path
+func walkFields(v reflect.Value, prefix string, fn func(key string, field reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			walkFields(field, key, fn)
+			continue
+		}
+		fn(key, field)
+	}
+}
+
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", value)
+		}
+		field.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", value)
+		}
+		field.SetBool(b)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("expected a number, got %q", value)
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", field.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
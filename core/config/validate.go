@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Validate reports settings that would otherwise only fail deep inside generation,
+// listing every problem found rather than just the first
+func (c *Config) Validate() error {
+	var problems []string
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("server.port must be between 1 and 65535, got %d", c.Server.Port))
+	}
+
+	goOutput, goOK := checkOutputPath("codegen.go.output", c.Codegen.Go.Output, &problems)
+	tsOutput, tsOK := checkOutputPath("codegen.typescript.output", c.Codegen.Typescript.Output, &problems)
+	if goOK && tsOK && goOutput == tsOutput {
+		problems = append(problems, fmt.Sprintf("codegen.go.output and codegen.typescript.output must differ, both are %q", c.Codegen.Go.Output))
+	}
+	prefixes := slices.Sorted(maps.Keys(c.Codegen.Go.RouteOverrides))
+	for _, prefix := range prefixes {
+		key := fmt.Sprintf("codegen.go.route_overrides[%q]", prefix)
+		if override, ok := checkOutputPath(key, c.Codegen.Go.RouteOverrides[prefix], &problems); ok && tsOK && override == tsOutput {
+			problems = append(problems, fmt.Sprintf("%s and codegen.typescript.output must differ, both are %q", key, override))
+		}
+	}
+
+	if alg := c.Cache.HashAlgorithm; alg != "" && alg != "md5" && alg != "sha256" {
+		problems = append(problems, fmt.Sprintf("cache.hash_algorithm must be \"md5\" or \"sha256\", got %q", alg))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// checkOutputPath requires an output directory inside the project root, other than the
+// root itself, and returns its cleaned form
+func checkOutputPath(key, path string, problems *[]string) (string, bool) {
+	if strings.TrimSpace(path) == "" {
+		*problems = append(*problems, fmt.Sprintf("%s must be set", key))
+		return "", false
+	}
+	if filepath.IsAbs(path) {
+		*problems = append(*problems, fmt.Sprintf("%s must be relative to the project root, got %q", key, path))
+		return "", false
+	}
+
+	clean := filepath.Clean(path)
+	switch {
+	case clean == ".":
+		*problems = append(*problems, fmt.Sprintf("%s must not be the project root", key))
+		return "", false
+	case clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)):
+		*problems = append(*problems, fmt.Sprintf("%s must not point outside the project root, got %q", key, path))
+		return "", false
+	}
+	return clean, true
+}
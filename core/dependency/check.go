@@ -0,0 +1,119 @@
+package dependency
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tristendillon/conduit/core/models"
+)
+
+// StaleCopy is a copied dependency file that differs from what copying its source writes now
+type StaleCopy struct {
+	ImportPath string
+	Source     string
+	Copy       string
+	Reason     string
+}
+
+// packageCheck is the outcome of comparing one copied package with its source
+type packageCheck struct {
+	stale        []StaleCopy
+	dependencies []models.LocalDependency
+}
+
+// CheckCopies compares the copies of the local packages in analysis, and of the packages
+// they import, with what copying them would write now. Nothing is written. Each package is
+// compared once per copier, so checking many routes that share packages stays cheap.
+func (dc *DependencyCopier) CheckCopies(analysis *models.DependencyAnalysis) ([]StaleCopy, error) {
+	if analysis == nil {
+		return nil, nil
+	}
+
+	var stale []StaleCopy
+	seen := make(map[string]bool)
+	pending := slices.Clone(analysis.LocalImports)
+	for len(pending) > 0 {
+		dep := pending[0]
+		pending = pending[1:]
+		if seen[dep.ImportPath] || dc.IsGenerated(dep) || dc.isProvided(dep.ImportPath) {
+			continue
+		}
+		seen[dep.ImportPath] = true
+
+		check, err := dc.checkPackage(dep)
+		if err != nil {
+			return nil, err
+		}
+		stale = append(stale, check.stale...)
+		pending = append(pending, check.dependencies...)
+	}
+	return stale, nil
+}
+
+// checkPackage compares the copy of one package with its source, remembering the result
+func (dc *DependencyCopier) checkPackage(dep models.LocalDependency) (*packageCheck, error) {
+	dc.mutex.Lock()
+	if check, exists := dc.checked[dep.ImportPath]; exists {
+		dc.mutex.Unlock()
+		return check, nil
+	}
+	dc.mutex.Unlock()
+
+	check := &packageCheck{}
+	sourcePath := filepath.Join(dc.projectRoot, dep.RelativePath)
+	targetPath := filepath.Join(dc.outputDir, "dependencies", dep.RelativePath)
+	sourceInfo, err := os.Stat(sourcePath)
+	if err != nil {
+		// Generating would fail to copy it as well, so there's no copy to compare
+		return check, nil
+	}
+
+	sources := map[string]string{sourcePath: targetPath}
+	if sourceInfo.IsDir() {
+		sources = make(map[string]string)
+		entries, err := os.ReadDir(sourcePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") {
+				sources[filepath.Join(sourcePath, entry.Name())] = filepath.Join(targetPath, entry.Name())
+			}
+		}
+	}
+
+	for source, target := range sources {
+		want, err := dc.rewrittenSource(source)
+		if err != nil {
+			return nil, err
+		}
+		reason := ""
+		got, err := os.ReadFile(target)
+		switch {
+		case os.IsNotExist(err):
+			reason = "copy missing"
+		case err != nil:
+			return nil, err
+		case !bytes.Equal(got, want):
+			reason = "source changed since it was copied"
+		}
+		if reason != "" {
+			check.stale = append(check.stale, StaleCopy{ImportPath: dep.ImportPath, Source: source, Copy: target, Reason: reason})
+		}
+	}
+	slices.SortFunc(check.stale, func(a, b StaleCopy) int { return strings.Compare(a.Copy, b.Copy) })
+
+	if sourceInfo.IsDir() {
+		if check.dependencies, err = dc.analyzeTransitiveDependencies(sourcePath); err != nil {
+			return nil, err
+		}
+	}
+
+	dc.mutex.Lock()
+	dc.checked[dep.ImportPath] = check
+	dc.mutex.Unlock()
+	return check, nil
+}
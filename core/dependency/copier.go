@@ -1,23 +1,43 @@
 package dependency
 
 import (
+	"bytes"
 	"fmt"
+	"go/ast"
 	"go/parser"
+	"go/printer"
 	"go/token"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/ast/astutil"
 
 	"github.com/tristendillon/conduit/core/logger"
 	"github.com/tristendillon/conduit/core/models"
 	astParser "github.com/tristendillon/conduit/core/ast"
 )
 
+// DependencyCopier copies local packages into the generated tree. It is safe for
+// concurrent use; each package is copied once however many routes import it.
 type DependencyCopier struct {
 	projectRoot  string
 	moduleName   string
 	outputDir    string
-	copiedDeps   map[string]*models.CopiedDependency
+	copiedDeps   map[string]*copyResult // by original import path, guarded by mutex
+	checked      map[string]*packageCheck // by original import path, guarded by mutex
+	mutex        sync.Mutex
+	provided     []string
+	generated    []string // project-relative output directories, never copied
+}
+
+// copyResult is the outcome of copying one dependency; done is closed once it is set
+type copyResult struct {
+	done   chan struct{}
+	copied *models.CopiedDependency
+	err    error
 }
 
 func NewDependencyCopier(projectRoot, moduleName, outputDir string) *DependencyCopier {
@@ -25,10 +45,56 @@ func NewDependencyCopier(projectRoot, moduleName, outputDir string) *DependencyC
 		projectRoot: projectRoot,
 		moduleName:  moduleName,
 		outputDir:   outputDir,
-		copiedDeps:  make(map[string]*models.CopiedDependency),
+		copiedDeps:  make(map[string]*copyResult),
+		checked:     make(map[string]*packageCheck),
+	}
+}
+
+// SetProvidedPackages sets the import path prefixes of packages that are treated as
+// already available: they are neither copied nor recursed into
+func (dc *DependencyCopier) SetProvidedPackages(prefixes []string) {
+	dc.provided = nil
+	for _, prefix := range prefixes {
+		if prefix = strings.TrimSuffix(strings.TrimSpace(prefix), "/"); prefix != "" {
+			dc.provided = append(dc.provided, prefix)
+		}
 	}
 }
 
+// isProvided reports whether an import path falls under a provided package prefix
+func (dc *DependencyCopier) isProvided(importPath string) bool {
+	for _, prefix := range dc.provided {
+		if importPath == prefix || strings.HasPrefix(importPath, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// SetGeneratedDirs sets the output directories, relative to the project root, that
+// conduit generates into. Packages inside them are never copied, since copying generated
+// code into the generated tree would change it on every run.
+func (dc *DependencyCopier) SetGeneratedDirs(dirs []string) {
+	dc.generated = nil
+	for _, dir := range dirs {
+		dir = filepath.ToSlash(filepath.Clean(dir))
+		if dir != "." && dir != "" {
+			dc.generated = append(dc.generated, strings.TrimPrefix(dir, "./"))
+		}
+	}
+}
+
+// IsGenerated reports whether a dependency lives inside a generated output directory
+func (dc *DependencyCopier) IsGenerated(dep models.LocalDependency) bool {
+	relativePath := filepath.ToSlash(dep.RelativePath)
+	for _, dir := range dc.generated {
+		if relativePath == dir || strings.HasPrefix(relativePath, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // CopyDependencies recursively copies all local dependencies for a route
 func (dc *DependencyCopier) CopyDependencies(analysis *models.DependencyAnalysis) ([]models.CopiedDependency, error) {
 	var result []models.CopiedDependency
@@ -46,11 +112,129 @@ func (dc *DependencyCopier) CopyDependencies(analysis *models.DependencyAnalysis
 	return result, nil
 }
 
+// copyDependency copies a dependency and then its transitive dependencies. A dependency
+// another caller is already copying is waited for. The result is published before the
+// transitive dependencies are followed, so an import cycle ends at the package that
+// started it instead of recursing, and cycles (even across goroutines) only ever wait on
+// packages whose own files are being copied and cannot deadlock. Cycles reports them.
 func (dc *DependencyCopier) copyDependency(dep models.LocalDependency) (*models.CopiedDependency, error) {
-	// Check if already copied
+	if dc.IsGenerated(dep) {
+		logger.Debug("Not copying generated package %s", dep.ImportPath)
+		return nil, nil
+	}
+
+	dc.mutex.Lock()
 	if existing, exists := dc.copiedDeps[dep.ImportPath]; exists {
+		dc.mutex.Unlock()
+		<-existing.done
 		logger.Debug("Dependency %s already copied", dep.ImportPath)
-		return existing, nil
+		return existing.copied, existing.err
+	}
+	result := &copyResult{done: make(chan struct{})}
+	dc.copiedDeps[dep.ImportPath] = result
+	dc.mutex.Unlock()
+
+	copied, err := dc.copyPackage(dep)
+	result.copied, result.err = copied, err
+	close(result.done)
+	if err != nil {
+		// Failures aren't remembered, so a later call tries again
+		dc.mutex.Lock()
+		delete(dc.copiedDeps, dep.ImportPath)
+		dc.mutex.Unlock()
+		return nil, err
+	}
+
+	// Recursively copy transitive dependencies
+	for _, transitive := range copied.Dependencies {
+		_, err := dc.copyDependency(transitive)
+		if err != nil {
+			logger.Debug("Failed to copy transitive dependency %s: %v", transitive.ImportPath, err)
+		}
+	}
+
+	if !copied.Provided {
+		logger.Debug("Copied dependency %s to %s", dep.ImportPath, copied.GeneratedPath)
+	}
+	return copied, nil
+}
+
+// Cycles returns the import cycles between the local packages copied so far, each as the
+// import paths along it starting and ending with the same package. Copying never follows
+// a cycle back into a package, but Go rejects the generated code until it is removed.
+func (dc *DependencyCopier) Cycles() [][]string {
+	graph := make(map[string][]string)
+	for importPath, copied := range dc.GetCopiedDependencies() {
+		for _, dep := range copied.Dependencies {
+			graph[importPath] = append(graph[importPath], dep.ImportPath)
+		}
+	}
+	nodes := make([]string, 0, len(graph))
+	for importPath := range graph {
+		nodes = append(nodes, importPath)
+	}
+	slices.Sort(nodes)
+
+	const (
+		unvisited = iota
+		inProgress
+		visited
+	)
+	state := make(map[string]int)
+	seen := make(map[string]bool)
+	var cycles [][]string
+	var stack []string
+
+	var visit func(importPath string)
+	visit = func(importPath string) {
+		state[importPath] = inProgress
+		stack = append(stack, importPath)
+		for _, next := range graph[importPath] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case inProgress:
+				// Reaching a package still on the stack closes a cycle
+				cycle := canonicalCycle(stack[slices.Index(stack, next):])
+				if key := strings.Join(cycle, " -> "); !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[importPath] = visited
+	}
+	for _, importPath := range nodes {
+		if state[importPath] == unvisited {
+			visit(importPath)
+		}
+	}
+
+	slices.SortFunc(cycles, func(a, b []string) int {
+		return strings.Compare(strings.Join(a, " "), strings.Join(b, " "))
+	})
+	return cycles
+}
+
+// canonicalCycle rotates the packages of a cycle to start at the smallest import path and
+// repeats it at the end, so the same cycle found from any package looks the same
+func canonicalCycle(members []string) []string {
+	start := slices.Index(members, slices.Min(members))
+	cycle := append(slices.Clone(members[start:]), members[:start]...)
+	return append(cycle, cycle[0])
+}
+
+// copyPackage copies the files of a single dependency and records its transitive
+// dependencies without following them
+func (dc *DependencyCopier) copyPackage(dep models.LocalDependency) (*models.CopiedDependency, error) {
+	if dc.isProvided(dep.ImportPath) {
+		logger.Debug("Dependency %s is provided, referencing it without copying", dep.ImportPath)
+		return &models.CopiedDependency{
+			OriginalPath: filepath.Join(dc.projectRoot, dep.RelativePath),
+			ImportPath:   dep.ImportPath,
+			Provided:     true,
+		}, nil
 	}
 
 	// Determine source path
@@ -79,36 +263,23 @@ func (dc *DependencyCopier) copyDependency(dep models.LocalDependency) (*models.
 		return nil, fmt.Errorf("failed to copy package files: %w", err)
 	}
 
-	// Analyze transitive dependencies
-	transitiveDeps, err := dc.analyzeTransitiveDependencies(targetPath)
+	// Analyze transitive dependencies from the original sources, since the copies
+	// already have their local imports rewritten to the generated tree
+	transitiveDeps, err := dc.analyzeTransitiveDependencies(sourcePath)
 	if err != nil {
 		logger.Debug("Failed to analyze transitive dependencies for %s: %v", dep.ImportPath, err)
 		transitiveDeps = []models.LocalDependency{}
 	}
 
 	// Create copied dependency record
-	newImportPath := fmt.Sprintf("%s/%s/dependencies/%s", dc.moduleName, strings.TrimPrefix(dc.outputDir, "./"), dep.RelativePath)
-	copied := &models.CopiedDependency{
+	newImportPath := dc.generatedImportPath(dep.RelativePath)
+	return &models.CopiedDependency{
 		OriginalPath:  sourcePath,
 		GeneratedPath: targetPath,
 		ImportPath:    newImportPath,
 		Files:         copiedFiles,
 		Dependencies:  transitiveDeps,
-	}
-
-	// Cache the result
-	dc.copiedDeps[dep.ImportPath] = copied
-
-	// Recursively copy transitive dependencies
-	for _, transitive := range transitiveDeps {
-		_, err := dc.copyDependency(transitive)
-		if err != nil {
-			logger.Debug("Failed to copy transitive dependency %s: %v", transitive.ImportPath, err)
-		}
-	}
-
-	logger.Debug("Copied dependency %s to %s", dep.ImportPath, targetPath)
-	return copied, nil
+	}, nil
 }
 
 func (dc *DependencyCopier) copyPackageFiles(sourcePath, targetPath string) ([]string, error) {
@@ -175,30 +346,104 @@ func (dc *DependencyCopier) copyPackageFiles(sourcePath, targetPath string) ([]s
 }
 
 func (dc *DependencyCopier) copyAndRewriteFile(sourcePath, targetPath string) error {
+	content, err := dc.rewrittenSource(sourcePath)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(targetPath, content, 0644)
+}
+
+// rewrittenSource returns the content a source file is copied with: the file itself, with
+// its local imports pointed at the generated tree when it parses
+func (dc *DependencyCopier) rewrittenSource(sourcePath string) ([]byte, error) {
 	// Read source file
 	src, err := os.ReadFile(sourcePath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Parse AST to rewrite imports
 	fset := token.NewFileSet()
-	_, err = parser.ParseFile(fset, sourcePath, src, parser.ParseComments)
+	f, err := parser.ParseFile(fset, sourcePath, src, parser.ParseComments)
 	if err != nil {
 		// If parsing fails, just copy the file as-is
 		logger.Debug("Failed to parse %s for import rewriting, copying as-is: %v", sourcePath, err)
-		return os.WriteFile(targetPath, src, 0644)
+		return src, nil
 	}
 
-	// TODO: Implement import path rewriting here
-	// For now, just copy the file as-is
-	// In the future, we'll rewrite import paths to point to generated dependencies
+	if !dc.rewriteLocalImports(fset, f) {
+		return src, nil
+	}
 
-	return os.WriteFile(targetPath, src, 0644)
+	var buf bytes.Buffer
+	printerConfig := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := printerConfig.Fprint(&buf, fset, f); err != nil {
+		return nil, fmt.Errorf("failed to print rewritten file %s: %w", sourcePath, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// rewriteLocalImports points every local import that is copied alongside this file
+// at its location in the generated dependencies tree. Returns true if anything changed.
+func (dc *DependencyCopier) rewriteLocalImports(fset *token.FileSet, f *ast.File) bool {
+	generatedPrefix := dc.generatedImportPath("")
+	rewritten := false
+
+	for _, imp := range f.Imports {
+		importPath := strings.Trim(imp.Path.Value, "\"")
+		if !strings.HasPrefix(importPath, dc.moduleName+"/") || strings.HasPrefix(importPath, generatedPrefix) {
+			continue
+		}
+		// Provided packages are never copied, so their imports keep pointing at the original module
+		if dc.isProvided(importPath) {
+			continue
+		}
+
+		relativePath := strings.TrimPrefix(importPath, dc.moduleName+"/")
+		// Only local packages that exist are copied by the transitive pass
+		if !dc.pathExists(filepath.Join(dc.projectRoot, relativePath)) {
+			logger.Debug("Not rewriting import %s: source package not found", importPath)
+			continue
+		}
+
+		newPath := dc.generatedImportPath(relativePath)
+		if astutil.RewriteImport(fset, f, importPath, newPath) {
+			logger.Debug("Rewrote import %s -> %s", importPath, newPath)
+			rewritten = true
+		}
+	}
+
+	return rewritten
+}
+
+// generatedImportPath returns the import path of a dependency inside the generated tree
+func (dc *DependencyCopier) generatedImportPath(relativePath string) string {
+	return fmt.Sprintf("%s/%s/dependencies/%s", dc.moduleName, strings.TrimPrefix(dc.outputDir, "./"), relativePath)
 }
 
 func (dc *DependencyCopier) analyzeTransitiveDependencies(packagePath string) ([]models.LocalDependency, error) {
+	imports, err := AnalyzePackageImports(packagePath, dc.moduleName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Provided packages are referenced where they are, so their imports aren't followed
 	var transitiveDeps []models.LocalDependency
+	for _, dep := range imports {
+		if dc.isProvided(dep.ImportPath) {
+			logger.Debug("Not following provided dependency %s", dep.ImportPath)
+			continue
+		}
+		transitiveDeps = append(transitiveDeps, dep)
+	}
+
+	return transitiveDeps, nil
+}
+
+// AnalyzePackageImports returns the local packages imported by the Go files in packagePath,
+// each once in the order first imported. Files that fail to parse are skipped.
+func AnalyzePackageImports(packagePath, moduleName string) ([]models.LocalDependency, error) {
+	var imports []models.LocalDependency
 
 	// Read all .go files in the package
 	entries, err := os.ReadDir(packagePath)
@@ -219,7 +464,7 @@ func (dc *DependencyCopier) analyzeTransitiveDependencies(packagePath string) ([
 			continue
 		}
 
-		analysis, err := astParser.AnalyzeDependencies(f, dc.moduleName)
+		analysis, err := astParser.AnalyzeDependencies(fset, f, moduleName)
 		if err != nil {
 			logger.Debug("Failed to analyze dependencies in %s: %v", filePath, err)
 			continue
@@ -227,16 +472,16 @@ func (dc *DependencyCopier) analyzeTransitiveDependencies(packagePath string) ([
 
 		// Add local dependencies that we haven't seen yet
 		for _, dep := range analysis.LocalImports {
-			if !dc.containsLocalDep(transitiveDeps, dep) {
-				transitiveDeps = append(transitiveDeps, dep)
+			if !containsLocalDep(imports, dep) {
+				imports = append(imports, dep)
 			}
 		}
 	}
 
-	return transitiveDeps, nil
+	return imports, nil
 }
 
-func (dc *DependencyCopier) containsLocalDep(deps []models.LocalDependency, target models.LocalDependency) bool {
+func containsLocalDep(deps []models.LocalDependency, target models.LocalDependency) bool {
 	for _, dep := range deps {
 		if dep.ImportPath == target.ImportPath {
 			return true
@@ -250,7 +495,21 @@ func (dc *DependencyCopier) pathExists(path string) bool {
 	return err == nil
 }
 
-// GetCopiedDependencies returns all dependencies that have been copied
+// GetCopiedDependencies returns all dependencies that have been copied, keyed by their
+// original import path. Copies still in progress are left out.
 func (dc *DependencyCopier) GetCopiedDependencies() map[string]*models.CopiedDependency {
-	return dc.copiedDeps
+	dc.mutex.Lock()
+	defer dc.mutex.Unlock()
+
+	copied := make(map[string]*models.CopiedDependency, len(dc.copiedDeps))
+	for importPath, result := range dc.copiedDeps {
+		select {
+		case <-result.done:
+			if result.err == nil {
+				copied[importPath] = result.copied
+			}
+		default:
+		}
+	}
+	return copied
 }
\ No newline at end of file
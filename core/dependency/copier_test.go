@@ -0,0 +1,107 @@
+package dependency
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tristendillon/conduit/core/models"
+)
+
+const testModule = "example.com/app"
+
+// writePackage writes a one-file package importing the given project packages
+func writePackage(t *testing.T, root, relPath string, imports ...string) {
+	t.Helper()
+	dir := filepath.Join(root, relPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var src strings.Builder
+	src.WriteString("package " + filepath.Base(relPath) + "\n\n")
+	for _, imp := range imports {
+		src.WriteString("import _ \"" + testModule + "/" + imp + "\"\n")
+	}
+	if err := os.WriteFile(filepath.Join(dir, filepath.Base(relPath)+".go"), []byte(src.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func localDeps(relPaths ...string) *models.DependencyAnalysis {
+	analysis := &models.DependencyAnalysis{}
+	for _, relPath := range relPaths {
+		analysis.LocalImports = append(analysis.LocalImports, models.LocalDependency{
+			ImportPath:   testModule + "/" + relPath,
+			RelativePath: relPath,
+		})
+	}
+	return analysis
+}
+
+// TestCopyDependenciesConcurrently copies overlapping dependency sets from many goroutines
+// at once; run it with -race to check the copier's locking
+func TestCopyDependenciesConcurrently(t *testing.T) {
+	root := t.TempDir()
+	writePackage(t, root, "shared/log")
+	writePackage(t, root, "repo/users", "shared/log")
+	writePackage(t, root, "repo/orgs", "repo/users", "shared/log")
+	// cycle/a and cycle/b import each other, which copying must not deadlock on
+	writePackage(t, root, "cycle/a", "cycle/b")
+	writePackage(t, root, "cycle/b", "cycle/a", "shared/log")
+	t.Chdir(root)
+
+	dc := NewDependencyCopier(root, testModule, ".conduit/go")
+	routes := [][]string{
+		{"repo/users"},
+		{"repo/orgs"},
+		{"repo/orgs", "repo/users"},
+		{"cycle/a"},
+		{"cycle/b", "repo/orgs"},
+		{"shared/log", "cycle/a"},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(routes)*4)
+	for range 4 {
+		for _, deps := range routes {
+			wg.Go(func() {
+				if _, err := dc.CopyDependencies(localDeps(deps...)); err != nil {
+					errs <- err
+				}
+			})
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	copied := dc.GetCopiedDependencies()
+	for _, relPath := range []string{"shared/log", "repo/users", "repo/orgs", "cycle/a", "cycle/b"} {
+		dep, exists := copied[testModule+"/"+relPath]
+		if !exists {
+			t.Errorf("%s wasn't copied", relPath)
+			continue
+		}
+		if len(dep.Files) != 1 {
+			t.Errorf("%s copied files = %v, want one", relPath, dep.Files)
+			continue
+		}
+		content, err := os.ReadFile(dep.Files[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(content), "\""+testModule+"/shared/") || strings.Contains(string(content), "\""+testModule+"/repo/") {
+			t.Errorf("%s copy still imports the original packages:\n%s", relPath, content)
+		}
+	}
+
+	want := [][]string{{testModule + "/cycle/a", testModule + "/cycle/b", testModule + "/cycle/a"}}
+	if cycles := dc.Cycles(); !reflect.DeepEqual(cycles, want) {
+		t.Errorf("Cycles() = %v, want %v", cycles, want)
+	}
+}
@@ -0,0 +1,172 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/tristendillon/conduit/core/ast"
+	"github.com/tristendillon/conduit/core/cache"
+	cacheModels "github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/dependency"
+	"github.com/tristendillon/conduit/core/models"
+)
+
+// StaleOutput is a generated file that no longer matches its sources
+type StaleOutput struct {
+	Output string `json:"output"`
+	Source string `json:"source,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// SourceError is a route file that can't be parsed
+type SourceError struct {
+	Source  string `json:"source"`
+	Message string `json:"message"`
+}
+
+// CheckReport lists what `conduit generate` would change without generating anything
+type CheckReport struct {
+	Stale  []StaleOutput `json:"stale"`
+	Errors []SourceError `json:"errors"`
+}
+
+// CheckOutputs compares every route file against the generation records persisted by
+// the last run, and every copied dependency against its source, and reports the generated
+// files that are out of date, along with route files that have syntax errors. Routes that
+// import a stale copy, directly or through other copied packages, are stale as well.
+// Nothing is written, including the cache state.
+func (rg *RouteGenerator) CheckOutputs() (*CheckReport, error) {
+	tree, err := rg.WalkRouteTree()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := rg.loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	if cfg.Codegen.Go.Mode == config.GoModeSingleFile {
+		return nil, fmt.Errorf("checking generated files is only supported in %s mode", config.GoModeMultiFile)
+	}
+	moduleName := rg.getModuleName()
+	if err := tree.CalculateOutputPaths(cfg, moduleName); err != nil {
+		return nil, fmt.Errorf("failed to calculate output paths: %w", err)
+	}
+
+	rg.loadCacheState()
+
+	report := &CheckReport{Stale: []StaleOutput{}, Errors: []SourceError{}}
+	var sources []string
+	for _, route := range tree.Routes {
+		if route.ParsedFile == nil {
+			continue
+		}
+
+		// The built-in health route has no source; it's only stale when missing
+		if rg.isHealthRoute(route) {
+			if _, err := os.Stat(route.OutputPath); os.IsNotExist(err) {
+				report.Stale = append(report.Stale, StaleOutput{Output: route.OutputPath, Reason: "output missing"})
+			}
+			continue
+		}
+
+		if err := ast.CheckSyntax(route.ParsedFile.Path); err != nil {
+			report.Errors = append(report.Errors, SourceError{Source: rg.relPath(route.ParsedFile.Path), Message: err.Error()})
+			continue
+		}
+		sources = append(sources, route.ParsedFile.Path)
+	}
+
+	plan, err := cache.GetCacheManager().GetRegenerationPlan(sources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get regeneration plan: %w", err)
+	}
+
+	// Every source is passed in as changed, so only the generation checks (priority 2)
+	// mean an output is out of date; the dependents each source lists are not
+	checked := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		checked[source] = true
+	}
+	depCopier := rg.newDependencyCopier(cfg, moduleName)
+	staleCopies := make(map[string]dependency.StaleCopy)
+	for _, route := range tree.Routes {
+		if route.ParsedFile == nil || !checked[route.ParsedFile.Path] {
+			continue
+		}
+
+		stale, err := rg.staleCopies(depCopier, route)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check dependencies of %s: %w", route.FolderPath, err)
+		}
+		for _, staleCopy := range stale {
+			staleCopies[staleCopy.Copy] = staleCopy
+		}
+
+		// Plans list files by cache key
+		source := cacheModels.CanonicalPath(route.ParsedFile.Path)
+		reason := plan.Reasons[source]
+		if plan.Priority[source] < 2 {
+			if len(stale) == 0 {
+				continue
+			}
+			reason = fmt.Sprintf("copied dependency %s changed", stale[0].ImportPath)
+		}
+		report.Stale = append(report.Stale, StaleOutput{
+			Output: route.OutputPath,
+			Source: rg.relPath(route.ParsedFile.Path),
+			Reason: reason,
+		})
+	}
+
+	copies := make([]string, 0, len(staleCopies))
+	for output := range staleCopies {
+		copies = append(copies, output)
+	}
+	sort.Strings(copies)
+	for _, output := range copies {
+		report.Stale = append(report.Stale, StaleOutput{
+			Output: output,
+			Source: rg.relPath(staleCopies[output].Source),
+			Reason: staleCopies[output].Reason,
+		})
+	}
+
+	if rg.needsRegistryRegeneration(tree.Routes, cfg) {
+		report.Stale = append(report.Stale, StaleOutput{
+			Output: filepath.Join(cfg.Codegen.Go.Output, "routes_registry.go"),
+			Reason: "routes or settings changed since the registry was generated",
+		})
+	}
+
+	return report, nil
+}
+
+// staleCopies returns the copied dependencies of a route and its folder middleware that
+// differ from their sources
+func (rg *RouteGenerator) staleCopies(depCopier *dependency.DependencyCopier, route models.Route) ([]dependency.StaleCopy, error) {
+	stale, err := depCopier.CheckCopies(route.ParsedFile.Dependencies)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range route.Middleware {
+		middlewareStale, err := depCopier.CheckCopies(file.Dependencies)
+		if err != nil {
+			return nil, err
+		}
+		stale = append(stale, middlewareStale...)
+	}
+	return stale, nil
+}
+
+// relPath returns path relative to the project root, or path itself if it's outside it
+func (rg *RouteGenerator) relPath(path string) string {
+	rel, err := filepath.Rel(rg.wd, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
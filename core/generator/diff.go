@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tristendillon/conduit/core/cache"
+	"github.com/tristendillon/conduit/core/models"
+)
+
+// DiffRoutes compares the current route tree with the routes recorded by the last
+// generation, from the registry signature in the persisted cache state. Nothing is written.
+func (rg *RouteGenerator) DiffRoutes() (models.RouteDiff, error) {
+	tree, err := rg.WalkRouteTree()
+	if err != nil {
+		return models.RouteDiff{}, err
+	}
+
+	rg.loadCacheState()
+	signature, ok := cache.GetCacheManager().GetRegistrySignature()
+	if !ok {
+		return models.RouteDiff{}, fmt.Errorf("no previous generation found in %s, run conduit generate first", rg.cacheStatePath())
+	}
+
+	// Entries are "<folder path> <METHOD,METHOD>"; those starting with # record settings
+	previous := models.NewRouteTree()
+	for _, entry := range signature.RoutePaths {
+		if strings.HasPrefix(entry, "#") {
+			continue
+		}
+		folderPath, methods, _ := strings.Cut(entry, " ")
+		parsed := &models.ParsedFile{RelPath: folderPath, Methods: []string{}}
+		if methods != "" {
+			parsed.Methods = strings.Split(methods, ",")
+		}
+		previous.AddRoute(parsed)
+	}
+
+	return tree.Diff(previous), nil
+}
@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/tristendillon/conduit/core/ast"
+	"github.com/tristendillon/conduit/core/cache"
+)
+
+// DoctorReport lists the problems in the project that would break or surprise generation
+type DoctorReport struct {
+	// Cycles are the dependency cycles in the cache's dependency graph, each starting and
+	// ending with the same file or import path
+	Cycles [][]string `json:"cycles"`
+	// ParseErrors are route files that can't be parsed
+	ParseErrors []SourceError `json:"parse_errors"`
+	// EmptyRoutes are route folders whose route.go declares no HTTP methods
+	EmptyRoutes []string `json:"empty_routes"`
+}
+
+// Healthy reports whether nothing that fails `conduit doctor` was found; routes without
+// methods are only warned about
+func (r *DoctorReport) Healthy() bool {
+	return len(r.Cycles) == 0 && len(r.ParseErrors) == 0
+}
+
+// Doctor warms the cache and builds the dependency graph like generate does, then reports
+// dependency cycles, route files that fail to parse and routes without methods. Nothing
+// is written.
+func (rg *RouteGenerator) Doctor() (*DoctorReport, error) {
+	tree, err := rg.WalkRouteTree()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DoctorReport{Cycles: [][]string{}, ParseErrors: []SourceError{}, EmptyRoutes: []string{}}
+	cacheManager := cache.GetCacheManager()
+
+	cycles, err := cacheManager.GetDependencyGraph().DetectCycles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect dependency cycles: %w", err)
+	}
+	for _, cycle := range cycles {
+		closed := make([]string, 0, len(cycle)+1)
+		for _, node := range cycle {
+			closed = append(closed, rg.relPath(filepath.FromSlash(node)))
+		}
+		report.Cycles = append(report.Cycles, append(closed, closed[0]))
+	}
+
+	// The walker drops some routes it can't parse, so look at every route file the cache tracks
+	broken := make(map[string]bool)
+	for _, filePath := range cacheManager.TrackedFilesUnder(rg.wd) {
+		if filepath.Base(filePath) != "route.go" {
+			continue
+		}
+		path := filepath.FromSlash(filePath)
+		if err := ast.CheckSyntax(path); err != nil {
+			report.ParseErrors = append(report.ParseErrors, SourceError{Source: rg.relPath(path), Message: err.Error()})
+			broken[rg.relPath(path)] = true
+		}
+	}
+	sort.Slice(report.ParseErrors, func(i, j int) bool {
+		return report.ParseErrors[i].Source < report.ParseErrors[j].Source
+	})
+
+	for _, route := range tree.Routes {
+		if route.ParsedFile == nil || rg.isHealthRoute(route) || len(route.Methods) > 0 {
+			continue
+		}
+		// A route with parse errors is already reported as such
+		if source := rg.relPath(filepath.Join(rg.wd, route.FolderPath, "route.go")); !broken[source] {
+			report.EmptyRoutes = append(report.EmptyRoutes, source)
+		}
+	}
+	sort.Strings(report.EmptyRoutes)
+
+	return report, nil
+}
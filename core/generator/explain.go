@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tristendillon/conduit/core/cache"
+	cacheModels "github.com/tristendillon/conduit/core/cache/models"
+)
+
+// Explain warms the cache from the route tree and the state persisted by the last run,
+// then explains whether filePath (absolute or relative to the project root) would be
+// regenerated and why. Nothing is written.
+func (rg *RouteGenerator) Explain(filePath string) (*cacheModels.RegenerationExplanation, error) {
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(rg.wd, filePath)
+	}
+	if _, err := os.Stat(filePath); err != nil {
+		return nil, err
+	}
+
+	if _, err := rg.WalkRouteTree(); err != nil {
+		return nil, err
+	}
+	rg.loadCacheState()
+
+	explanation, err := cache.GetCacheManager().Explain(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain %s: %w", filePath, err)
+	}
+	return explanation, nil
+}
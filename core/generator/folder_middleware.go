@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tristendillon/conduit/core/ast"
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/models"
+	"github.com/tristendillon/conduit/core/shared"
+)
+
+// generatedMiddleware is a function from a _middleware.go copied into a generated route file
+type generatedMiddleware struct {
+	Name   string // prefixed with the folder so middleware from different folders can't collide
+	Params string
+	Body   string
+	Source string
+}
+
+// routeMiddleware returns the folder middleware wrapping route, outermost first, along
+// with the standard library and external imports they need beyond the route's own
+func routeMiddleware(route models.Route, router string) ([]generatedMiddleware, []string) {
+	present := make(map[string]bool)
+	if route.ParsedFile != nil && route.ParsedFile.Dependencies != nil {
+		for _, imp := range route.ParsedFile.Dependencies.StandardLibImports {
+			present[imp] = true
+		}
+		for _, imp := range route.ParsedFile.Dependencies.ExternalImports {
+			present[imp] = true
+		}
+	}
+	// The chi import is provided by the template when targeting chi
+	if router == config.RouterChi {
+		present[ChiImportPath] = true
+	}
+
+	var middleware []generatedMiddleware
+	var imports []string
+	for _, file := range route.Middleware {
+		for _, fn := range file.Functions {
+			middleware = append(middleware, generatedMiddleware{
+				Name:   middlewareFuncName(file.RelPath, fn.Name),
+				Params: strings.TrimPrefix(fn.Signature, fn.Name),
+				Body:   fn.Body,
+				Source: filepath.ToSlash(filepath.Join(file.RelPath, ast.MiddlewareFileName)),
+			})
+		}
+		if file.Dependencies == nil {
+			continue
+		}
+		for _, imp := range slices.Concat(file.Dependencies.StandardLibImports, file.Dependencies.ExternalImports) {
+			if !present[imp] {
+				present[imp] = true
+				imports = append(imports, imp)
+			}
+		}
+	}
+
+	sort.Strings(imports)
+	return middleware, imports
+}
+
+// middlewareFuncName returns the name a folder's middleware function is generated under,
+// e.g. Auth in "api/v1" becomes "mw_api_v1_Auth" and Auth in the project root "mw_Auth"
+func middlewareFuncName(folderPath, name string) string {
+	folderPath = filepath.ToSlash(filepath.Clean(folderPath))
+	if folderPath == "." {
+		return "mw_" + name
+	}
+	return "mw_" + shared.ToSnakeCase(models.IdentifierPath(folderPath)) + "_" + name
+}
+
+// middlewareChanged reports whether a _middleware.go that applies to route may have been
+// edited, added or removed since its output was generated. Middleware files aren't tracked
+// by the cache, so the folders from the project root down to the route are checked: a
+// folder's modification time changes when a file in it is added or removed.
+func (rg *RouteGenerator) middlewareChanged(route models.Route) bool {
+	output, err := os.Stat(route.OutputPath)
+	if err != nil {
+		return true
+	}
+
+	dirs := []string{rg.wd}
+	for _, part := range strings.Split(filepath.ToSlash(route.FolderPath), "/") {
+		dirs = append(dirs, filepath.Join(dirs[len(dirs)-1], part))
+	}
+	for _, dir := range dirs {
+		for _, path := range []string{dir, filepath.Join(dir, ast.MiddlewareFileName)} {
+			if stat, err := os.Stat(path); err == nil && stat.ModTime().After(output.ModTime()) {
+				return true
+			}
+		}
+	}
+	return false
+}
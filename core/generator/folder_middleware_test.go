@@ -0,0 +1,20 @@
+package generator
+
+import "testing"
+
+func TestMiddlewareFuncName(t *testing.T) {
+	tests := []struct {
+		folderPath string
+		want       string
+	}{
+		{".", "mw_Auth"},
+		{"", "mw_Auth"},
+		{"api", "mw_api_Auth"},
+		{"api/v1", "mw_api_v1_Auth"},
+	}
+	for _, tt := range tests {
+		if got := middlewareFuncName(tt.folderPath, "Auth"); got != tt.want {
+			t.Errorf("middlewareFuncName(%q) = %q, want %q", tt.folderPath, got, tt.want)
+		}
+	}
+}
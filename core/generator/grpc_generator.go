@@ -0,0 +1,162 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/models"
+	"github.com/tristendillon/conduit/core/shared"
+	"github.com/tristendillon/conduit/core/template_engine"
+)
+
+// protoPackagePattern matches a proto package name such as "api" or "acme.api.v1"
+var protoPackagePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// ProtoOptions configures the .proto file generated from the route tree
+type ProtoOptions struct {
+	Output  string // path of the .proto file; <package>.proto in the project root when empty
+	Package string // proto package name; derived from the app name when empty
+}
+
+type protoField struct {
+	Name   string
+	Number int
+}
+
+type protoRPC struct {
+	Name    string
+	Method  string
+	APIPath string
+	Fields  []protoField // path parameters, in path order
+}
+
+// GenerateProto writes a .proto file with a service holding one rpc per route handler:
+// GET api/users/:id becomes rpc GetApiUsersById(GetApiUsersByIdRequest) returns
+// (GetApiUsersByIdResponse), with the path parameters as request fields. It returns the
+// number of rpcs written.
+func (rg *RouteGenerator) GenerateProto(opts ProtoOptions) (int, error) {
+	tree, err := rg.WalkRouteTree()
+	if err != nil {
+		return 0, err
+	}
+
+	cfg, err := rg.loadConfig()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = strings.ToLower(protoIdentifier(cfg.AppName))
+	}
+	if !protoPackagePattern.MatchString(pkg) {
+		return 0, fmt.Errorf("invalid proto package name %q", pkg)
+	}
+
+	output := opts.Output
+	if output == "" {
+		output = filepath.Join(rg.wd, pkg+".proto")
+	}
+
+	rpcs, err := protoRPCs(tree.Routes)
+	if err != nil {
+		return 0, err
+	}
+
+	templateData := struct {
+		Package   string
+		Service   string
+		RPCs      []protoRPC
+		Timestamp time.Time
+	}{
+		Package:   pkg,
+		Service:   protoIdentifier(cfg.AppName) + "Service",
+		RPCs:      rpcs,
+		Timestamp: time.Now(),
+	}
+
+	engine, err := newTemplateEngine(rg.wd, cfg)
+	if err != nil {
+		return 0, err
+	}
+	if err := engine.GenerateFile(template_engine.TEMPLATES.DEV.PROTO, output, templateData); err != nil {
+		return 0, fmt.Errorf("failed to generate proto file %s: %w", output, err)
+	}
+
+	logger.Info("Generated %s with %d rpcs", rg.relPath(output), len(rpcs))
+	return len(rpcs), nil
+}
+
+// protoRPCs maps each route handler to an rpc, sorted by name. Routes whose names
+// collide are rejected since proto names must be unique.
+func protoRPCs(routes []models.Route) ([]protoRPC, error) {
+	var rpcs []protoRPC
+	sources := make(map[string]string)
+	for _, route := range routes {
+		if route.ParsedFile == nil {
+			continue
+		}
+
+		var words []string
+		var fields []protoField
+		for _, segment := range route.Segments {
+			if segment.IsParam || segment.IsCatchAll {
+				words = append(words, "By", segment.ParamName)
+				fields = append(fields, protoField{Name: protoFieldName(segment.ParamName), Number: len(fields) + 1})
+				continue
+			}
+			words = append(words, segment.Name)
+		}
+		name := strings.Join(words, "/")
+
+		for _, method := range route.Methods {
+			rpc := protoRPC{
+				Name:    shared.ToPascalCase(method + "/" + name),
+				Method:  method,
+				APIPath: route.APIPath,
+				Fields:  fields,
+			}
+
+			source := method + " /" + route.APIPath
+			if existing, exists := sources[rpc.Name]; exists {
+				return nil, fmt.Errorf("%s and %s both map to rpc %s", existing, source, rpc.Name)
+			}
+			sources[rpc.Name] = source
+			rpcs = append(rpcs, rpc)
+		}
+	}
+
+	sort.Slice(rpcs, func(i, j int) bool { return rpcs[i].Name < rpcs[j].Name })
+	return rpcs, nil
+}
+
+// protoIdentifier converts a name to an upper camel case identifier, e.g.
+// "user-profiles" becomes "UserProfiles". Proto identifiers must start with a letter, so
+// one starting with a digit, or an empty one, is prefixed with X.
+func protoIdentifier(name string) string {
+	identifier := shared.ToPascalCase(name)
+	if identifier == "" || !unicode.IsLetter([]rune(identifier)[0]) {
+		return "X" + strings.TrimPrefix(identifier, "_")
+	}
+	return identifier
+}
+
+// protoFieldName converts a path parameter name to a lower snake case field name
+func protoFieldName(name string) string {
+	field := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, name)
+	if field == "" || unicode.IsDigit(rune(field[0])) {
+		return "_" + field
+	}
+	return field
+}
@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tristendillon/conduit/core/ast"
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/models"
+	"github.com/tristendillon/conduit/core/version"
+)
+
+// healthRouteDir is the folder path of the built-in health-check route
+const healthRouteDir = "__conduit/health"
+
+// healthRouteSource is the route.go conduit generates the health-check route from. The
+// version is written in as a literal so the project doesn't need to import conduit.
+func healthRouteSource() []byte {
+	return []byte(fmt.Sprintf(`package health
+
+import "net/http"
+
+// GET reports the conduit version the routes were generated with
+func GET(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(%q))
+}
+`, version.Version))
+}
+
+// isHealthRoute reports whether route is the built-in health-check route rather than a
+// route.go in the project
+func (rg *RouteGenerator) isHealthRoute(route models.Route) bool {
+	if route.FolderPath != healthRouteDir || route.ParsedFile == nil {
+		return false
+	}
+	_, err := os.Stat(route.ParsedFile.Path)
+	return os.IsNotExist(err)
+}
+
+// healthRoutePath is where the health-check route.go would live in the project
+func (rg *RouteGenerator) healthRoutePath() string {
+	return filepath.Join(rg.wd, filepath.FromSlash(healthRouteDir), "route.go")
+}
+
+// addHealthRoute adds the health-check route to tree when it's enabled in cfg and the
+// project doesn't define __conduit/health itself
+func (rg *RouteGenerator) addHealthRoute(tree *models.RouteTree, cfg *config.Config) error {
+	if !cfg.Codegen.Go.Health {
+		return nil
+	}
+	for _, route := range tree.Routes {
+		if route.FolderPath == healthRouteDir {
+			logger.Debug("Project defines %s, skipping the built-in health route", healthRouteDir)
+			return nil
+		}
+	}
+
+	parsed, err := ast.ParseRouteSource(rg.healthRoutePath(), filepath.FromSlash(healthRouteDir), rg.getModuleName(), healthRouteSource())
+	if err != nil {
+		return fmt.Errorf("failed to parse health route: %w", err)
+	}
+	tree.AddRoute(parsed)
+	return nil
+}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/models"
+	"github.com/tristendillon/conduit/core/template_engine"
+)
+
+// metricsImports are needed by the metrics code when it's inlined into the single-file server
+var metricsImports = []string{`"fmt"`, `"sort"`, `"strings"`, `"sync"`, `"time"`}
+
+type metricsTemplateData struct {
+	PackageName string
+	Embedded    bool
+	Timestamp   time.Time
+}
+
+// metricsRouteAvailable reports whether /metrics can be served without shadowing a project route
+func metricsRouteAvailable(routes []models.Route) bool {
+	for _, route := range routes {
+		if route.Pattern == "metrics" {
+			logger.Warn("Route %s collides with the generated /metrics route, not serving metrics", route.FolderPath)
+			return false
+		}
+	}
+	return true
+}
+
+// writeMetricsFile generates metrics.go next to the registry when metrics are enabled and
+// removes it otherwise
+func (rg *RouteGenerator) writeMetricsFile(engine *template_engine.TemplateEngine, cfg *config.Config, timestamp time.Time) error {
+	metricsPath := filepath.Join(cfg.Codegen.Go.Output, "metrics.go")
+
+	if !cfg.Codegen.Go.Metrics {
+		if err := os.Remove(metricsPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove metrics file: %w", err)
+		}
+		return nil
+	}
+
+	err := engine.GenerateFile(template_engine.TEMPLATES.DEV.METRICS_GO, metricsPath, metricsTemplateData{
+		PackageName: registryPackageName,
+		Timestamp:   timestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate metrics: %w", err)
+	}
+	return nil
+}
+
+// renderEmbeddedMetrics renders the metrics code without its file header for inlining
+func renderEmbeddedMetrics(engine *template_engine.TemplateEngine) (string, error) {
+	content, err := engine.RenderFile(template_engine.TEMPLATES.DEV.METRICS_GO, metricsTemplateData{Embedded: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to render metrics: %w", err)
+	}
+	return string(content), nil
+}
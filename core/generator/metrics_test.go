@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tristendillon/conduit/core/template_engine"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenTimestamp stands in for the generation time in golden files
+var goldenTimestamp = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// checkGolden compares got with testdata/name, rewriting the file first with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s; run go test -update if the change is intended\ngot:\n%s", golden, got)
+	}
+}
+
+// goRun runs the go command in dir, skipping the test when the toolchain isn't available
+func goRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("runs the go toolchain")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skipf("go toolchain not found: %v", err)
+	}
+	cmd := exec.Command(goBin, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go %v: %v\n%s", args, err, out)
+	}
+}
+
+func renderMetrics(t *testing.T) []byte {
+	t.Helper()
+	content, err := template_engine.NewTemplateEngine().RenderFile(template_engine.TEMPLATES.DEV.METRICS_GO, metricsTemplateData{
+		PackageName: registryPackageName,
+		Timestamp:   goldenTimestamp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func TestMetricsGolden(t *testing.T) {
+	checkGolden(t, "metrics.go.golden", renderMetrics(t))
+}
+
+// counterTest exercises the generated instrumentation from inside the generated package
+const counterTest = `package generated
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInstrumentHandlerCountsRequests(t *testing.T) {
+	collector := NewDefaultMetricsCollector()
+	SetMetricsCollector(collector)
+	handler := instrumentHandler("GET", "/users", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	for i := 1; i <= 3; i++ {
+		handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/users", nil))
+		if got := collector.RequestCount("GET", "/users"); got != uint64(i) {
+			t.Fatalf("after %d requests the counter is %d", i, got)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	metricsHandler(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	for _, want := range []string{
+		"conduit_http_requests_total{method=\"GET\",route=\"/users\",status=\"201\"} 3",
+		"conduit_http_requests_in_flight{method=\"GET\",route=\"/users\"} 0",
+		"conduit_http_request_duration_seconds_count{method=\"GET\",route=\"/users\"} 3",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output is missing %s:\n%s", want, body)
+		}
+	}
+}
+`
+
+func TestInstrumentedHandlerCounterIncrements(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":        "module generated\n\ngo 1.25\n",
+		"metrics.go":    string(renderMetrics(t)),
+		"count_test.go": counterTest,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	goRun(t, dir, "test", "./...")
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/models"
+)
+
+// ChiImportPath is imported by generated code when targeting the chi router
+const ChiImportPath = "github.com/go-chi/chi/v5"
+
+// queryParamAccess matches r.URL.Query().Get("name"), which reads the query string rather than the path
+var queryParamAccess = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\.URL\.Query\(\)\.Get\("([^"]+)"\)`)
+
+// pathParamExpr returns the expression that reads a path parameter with the given router
+func pathParamExpr(router, request string, segment models.RouteSegment) string {
+	if router == config.RouterChi {
+		key := segment.ParamName
+		if segment.IsCatchAll {
+			key = models.ChiCatchAllParam
+		}
+		return fmt.Sprintf("chi.URLParam(%s, %q)", request, key)
+	}
+	return fmt.Sprintf("%s.PathValue(%q)", request, segment.ParamName)
+}
+
+// migratePathParams returns a copy of the route's parsed file whose handlers read path
+// parameters with the configured router instead of through the query string. Each rewrite
+// is reported so the source can be fixed.
+func migratePathParams(route models.Route, router string) *models.ParsedFile {
+	parsed := route.ParsedFile
+	if parsed == nil {
+		return nil
+	}
+
+	params := make(map[string]models.RouteSegment)
+	for _, segment := range route.Segments {
+		if segment.IsParam || segment.IsCatchAll {
+			params[segment.ParamName] = segment
+		}
+	}
+
+	migrated := *parsed
+	migrated.Functions = make([]models.ExtractedFunction, len(parsed.Functions))
+	for i, fn := range parsed.Functions {
+		fn.Body = queryParamAccess.ReplaceAllStringFunc(fn.Body, func(match string) string {
+			groups := queryParamAccess.FindStringSubmatch(match)
+			segment, isPathParam := params[groups[2]]
+			if !isPathParam {
+				return match
+			}
+
+			replacement := pathParamExpr(router, groups[1], segment)
+			logger.Warn("%s: %s reads path parameter %q from the query string, generating %s instead",
+				parsed.RelPath, fn.Name, groups[2], replacement)
+			return replacement
+		})
+		migrated.Functions[i] = fn
+	}
+
+	// The chi import is provided by the templates when targeting chi
+	if router == config.RouterChi && parsed.Dependencies != nil {
+		deps := *parsed.Dependencies
+		deps.ExternalImports = nil
+		for _, imp := range parsed.Dependencies.ExternalImports {
+			if imp != ChiImportPath {
+				deps.ExternalImports = append(deps.ExternalImports, imp)
+			}
+		}
+		migrated.Dependencies = &deps
+	}
+
+	return &migrated
+}
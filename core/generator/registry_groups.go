@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/models"
+	"github.com/tristendillon/conduit/core/template_engine"
+)
+
+var registryGroupPattern = regexp.MustCompile(`^routes_registry_group_\w+\.go$`)
+
+// registryGroup is a route subtree registered by its own function and file
+type registryGroup struct {
+	Name       string
+	FolderPath string
+	Routes     []models.Route
+}
+
+type registryGroupTemplateData struct {
+	Name       string
+	FolderPath string
+	Routes     []models.Route
+	// Parts are the function suffixes of the parts the group is split into, if any
+	Parts       []string
+	Router      string
+	Metrics     bool
+	PackageName string
+	Timestamp   time.Time
+}
+
+// groupRoutesBySubtree assigns each route to the subtree node at depth that contains it.
+// Routes shallower than depth are returned as ungrouped and stay in the top-level registry.
+func groupRoutesBySubtree(tree *models.RouteTree, routes []models.Route, depth int) ([]models.Route, []registryGroup) {
+	nodes := tree.SubtreesAtDepth(depth)
+	groups := make([]registryGroup, len(nodes))
+	usedNames := make(map[string]int)
+	for i, node := range nodes {
+		name := node.GroupName()
+		if count := usedNames[name]; count > 0 {
+			usedNames[name]++
+			name = fmt.Sprintf("%s%d", name, count+1)
+		} else {
+			usedNames[name] = 1
+		}
+		groups[i] = registryGroup{Name: name, FolderPath: node.FolderPath}
+	}
+
+	var ungrouped []models.Route
+	for _, route := range routes {
+		folderPath := filepath.ToSlash(route.FolderPath)
+		grouped := false
+		for i := range groups {
+			if folderPath == groups[i].FolderPath || strings.HasPrefix(folderPath, groups[i].FolderPath+"/") {
+				groups[i].Routes = append(groups[i].Routes, route)
+				grouped = true
+				break
+			}
+		}
+		if !grouped {
+			ungrouped = append(ungrouped, route)
+		}
+	}
+
+	// Subtrees without routes (folders holding only deeper folders excluded from generation) are dropped
+	var nonEmpty []registryGroup
+	for _, group := range groups {
+		if len(group.Routes) > 0 {
+			nonEmpty = append(nonEmpty, group)
+		}
+	}
+	return ungrouped, nonEmpty
+}
+
+// registryRouteTree returns the walked route tree, or builds one from the routes when the
+// registry is generated without walking (from a manifest)
+func (rg *RouteGenerator) registryRouteTree(routes []models.Route) *models.RouteTree {
+	if rg.Walker != nil && len(rg.Walker.RouteTree.Routes) > 0 {
+		return rg.Walker.RouteTree
+	}
+
+	tree := models.NewRouteTree()
+	for _, route := range routes {
+		tree.AddRoute(&models.ParsedFile{RelPath: route.FolderPath, Methods: route.Methods, DocComment: route.Description})
+	}
+	return tree
+}
+
+// writeGroupedRegistry renders one routes_registry_group_<name>.go per subtree at
+// codegen.go.registry_group_depth and a top-level registry that calls each of them
+func (rg *RouteGenerator) writeGroupedRegistry(engine *template_engine.TemplateEngine, routes []models.Route, cfg *config.Config, timestamp time.Time) error {
+	outputDir := cfg.Codegen.Go.Output
+	router := cfg.Codegen.Go.Router
+	metrics := cfg.Codegen.Go.Metrics
+
+	ungrouped, groups := groupRoutesBySubtree(rg.registryRouteTree(routes), routes, cfg.Codegen.Go.RegistryGroupDepth)
+
+	written := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		fileNames, err := writeRegistryGroup(engine, group, router, metrics, timestamp, outputDir)
+		if err != nil {
+			return fmt.Errorf("failed to generate registry group %s: %w", group.Name, err)
+		}
+		for _, fileName := range fileNames {
+			written[fileName] = true
+		}
+	}
+
+	if err := removeRegistryParts(outputDir, 0); err != nil {
+		return err
+	}
+	if err := removeRegistryGroups(outputDir, written); err != nil {
+		return err
+	}
+
+	logger.Debug("Grouped routes registry into %d subtrees (%d routes registered directly)", len(groups), len(ungrouped))
+	return engine.GenerateFile(template_engine.TEMPLATES.DEV.ROUTES_REGISTRY_GO, filepath.Join(outputDir, "routes_registry.go"), registryTemplateData{
+		Routes:       ungrouped,
+		Groups:       groups,
+		Router:       router,
+		Metrics:      metrics,
+		MetricsRoute: metrics && metricsRouteAvailable(routes),
+		PackageName:  registryPackageName,
+		Timestamp:    timestamp,
+	})
+}
+
+// writeRegistryGroup renders a group into routes_registry_group_<name>.go, splitting its
+// routes across routes_registry_group_<name>_N.go files when the result exceeds the
+// engine's size limit, and returns the names of the files written
+func writeRegistryGroup(engine *template_engine.TemplateEngine, group registryGroup, router string, metrics bool, timestamp time.Time, outputDir string) ([]string, error) {
+	baseName := "routes_registry_group_" + strings.ToLower(group.Name)
+	data := registryGroupTemplateData{
+		Name:        group.Name,
+		FolderPath:  group.FolderPath,
+		Routes:      group.Routes,
+		Router:      router,
+		Metrics:     metrics,
+		PackageName: registryPackageName,
+		Timestamp:   timestamp,
+	}
+	content, err := engine.RenderFile(template_engine.TEMPLATES.DEV.ROUTES_REGISTRY_GROUP_GO, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileNames []string
+	maxBytes := engine.MaxFileBytes()
+	if maxBytes > 0 && len(content) > maxBytes && len(group.Routes) > 1 {
+		parts, err := splitRegistry(engine, group.Routes, maxBytes, group.Name, router, metrics, timestamp)
+		if err != nil {
+			return nil, err
+		}
+		for i, part := range parts {
+			fileName := fmt.Sprintf("%s_%d.go", baseName, i)
+			if err := engine.WriteFile(filepath.Join(outputDir, fileName), part); err != nil {
+				return nil, err
+			}
+			fileNames = append(fileNames, fileName)
+			data.Parts = append(data.Parts, registryPartSuffix(group.Name, i))
+		}
+
+		if content, err = engine.RenderFile(template_engine.TEMPLATES.DEV.ROUTES_REGISTRY_GROUP_GO, data); err != nil {
+			return nil, err
+		}
+		logger.Debug("Split registry group %s into %d parts to stay under %d bytes", group.Name, len(parts), maxBytes)
+	}
+
+	fileName := baseName + ".go"
+	if err := engine.WriteFile(filepath.Join(outputDir, fileName), content); err != nil {
+		return nil, err
+	}
+	return append(fileNames, fileName), nil
+}
+
+// removeRegistryGroups deletes registry group files that weren't written by this run
+func removeRegistryGroups(outputDir string, keep map[string]bool) error {
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read output directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !registryGroupPattern.MatchString(entry.Name()) || keep[entry.Name()] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(outputDir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove stale registry group %s: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/models"
+	"github.com/tristendillon/conduit/core/template_engine"
+)
+
+const registryPackageName = "generated"
+
+var registryPartPattern = regexp.MustCompile(`^routes_registry_\d+\.go$`)
+
+type registryTemplateData struct {
+	Routes       []models.Route
+	Parts        []int
+	Groups       []registryGroup
+	Router       string
+	Metrics      bool
+	MetricsRoute bool
+	PackageName  string
+	Timestamp    time.Time
+}
+
+type registryPartTemplateData struct {
+	Index int
+	// Group is the name of the registry group the part belongs to, empty for the top level
+	Group       string
+	Routes      []models.Route
+	Router      string
+	Metrics     bool
+	PackageName string
+	Timestamp   time.Time
+}
+
+// Suffix names the part's functions: registerRoutes<Suffix> and routeInfos<Suffix>
+func (d registryPartTemplateData) Suffix() string {
+	return registryPartSuffix(d.Group, d.Index)
+}
+
+// registryPartSuffix returns the suffix of the functions in a part of the registry or of
+// one of its groups: "0" for the first top-level part and "ApiV1_0" for that of group ApiV1
+func registryPartSuffix(group string, index int) string {
+	if group == "" {
+		return fmt.Sprint(index)
+	}
+	return fmt.Sprintf("%s_%d", group, index)
+}
+
+// writeRoutesRegistry renders the registry into routes_registry.go, splitting the route
+// entries across routes_registry_N.go files when the result exceeds the engine's size limit
+func (rg *RouteGenerator) writeRoutesRegistry(engine *template_engine.TemplateEngine, routes []models.Route, cfg *config.Config) error {
+	timestamp := time.Now()
+	outputDir := cfg.Codegen.Go.Output
+	router := cfg.Codegen.Go.Router
+	metrics := cfg.Codegen.Go.Metrics
+	registryPath := filepath.Join(outputDir, "routes_registry.go")
+
+	if err := rg.writeMetricsFile(engine, cfg, timestamp); err != nil {
+		return err
+	}
+
+	if cfg.Codegen.Go.RegistryGroupDepth > 0 {
+		return rg.writeGroupedRegistry(engine, routes, cfg, timestamp)
+	}
+	if err := removeRegistryGroups(outputDir, nil); err != nil {
+		return err
+	}
+
+	content, err := engine.RenderFile(template_engine.TEMPLATES.DEV.ROUTES_REGISTRY_GO, registryTemplateData{
+		Routes:       routes,
+		Router:       router,
+		Metrics:      metrics,
+		MetricsRoute: metrics && metricsRouteAvailable(routes),
+		PackageName:  registryPackageName,
+		Timestamp:    timestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate routes registry: %w", err)
+	}
+
+	maxBytes := engine.MaxFileBytes()
+	if maxBytes <= 0 || len(content) <= maxBytes || len(routes) < 2 {
+		if err := removeRegistryParts(outputDir, 0); err != nil {
+			return err
+		}
+		return engine.WriteFile(registryPath, content)
+	}
+
+	parts, err := splitRegistry(engine, routes, maxBytes, "", router, metrics, timestamp)
+	if err != nil {
+		return err
+	}
+
+	indexes := make([]int, len(parts))
+	for i, part := range parts {
+		indexes[i] = i
+		partPath := filepath.Join(outputDir, fmt.Sprintf("routes_registry_%d.go", i))
+		if err := engine.WriteFile(partPath, part); err != nil {
+			return err
+		}
+	}
+	if err := removeRegistryParts(outputDir, len(parts)); err != nil {
+		return err
+	}
+
+	content, err = engine.RenderFile(template_engine.TEMPLATES.DEV.ROUTES_REGISTRY_GO, registryTemplateData{
+		Routes:       routes,
+		Parts:        indexes,
+		Router:       router,
+		Metrics:      metrics,
+		MetricsRoute: metrics && metricsRouteAvailable(routes),
+		PackageName:  registryPackageName,
+		Timestamp:    timestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate routes registry: %w", err)
+	}
+
+	logger.Debug("Split routes registry into %d parts to stay under %d bytes", len(parts), maxBytes)
+	return engine.WriteFile(registryPath, content)
+}
+
+// splitRegistry renders the routes of the registry, or of one of its groups, into the
+// fewest parts that each fit within maxBytes. A part holding a single route is accepted
+// even if it is still over the limit.
+func splitRegistry(engine *template_engine.TemplateEngine, routes []models.Route, maxBytes int, group string, router string, metrics bool, timestamp time.Time) ([][]byte, error) {
+	for count := 2; ; count++ {
+		chunkSize := (len(routes) + count - 1) / count
+		var parts [][]byte
+		fits := true
+
+		for start := 0; start < len(routes); start += chunkSize {
+			end := min(start+chunkSize, len(routes))
+			part, err := engine.RenderFile(template_engine.TEMPLATES.DEV.ROUTES_REGISTRY_PART_GO, registryPartTemplateData{
+				Index:       len(parts),
+				Group:       group,
+				Routes:      routes[start:end],
+				Router:      router,
+				Metrics:     metrics,
+				PackageName: registryPackageName,
+				Timestamp:   timestamp,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate routes registry part: %w", err)
+			}
+			if len(part) > maxBytes && chunkSize > 1 {
+				fits = false
+				break
+			}
+			parts = append(parts, part)
+		}
+
+		if fits {
+			return parts, nil
+		}
+	}
+}
+
+// removeRegistryParts deletes registry parts left over from a previous split, keeping the first keep parts
+func removeRegistryParts(outputDir string, keep int) error {
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read output directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !registryPartPattern.MatchString(entry.Name()) {
+			continue
+		}
+		var index int
+		if _, err := fmt.Sscanf(entry.Name(), "routes_registry_%d.go", &index); err != nil || index < keep {
+			continue
+		}
+		if err := os.Remove(filepath.Join(outputDir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove stale registry part %s: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
@@ -1,14 +1,19 @@
 package generator
 
 import (
-	"crypto/md5"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/tristendillon/conduit/core/ast"
 	"github.com/tristendillon/conduit/core/cache"
 	cacheModels "github.com/tristendillon/conduit/core/cache/models"
 	"github.com/tristendillon/conduit/core/config"
@@ -16,43 +21,135 @@ import (
 	"github.com/tristendillon/conduit/core/logger"
 	"github.com/tristendillon/conduit/core/models"
 	"github.com/tristendillon/conduit/core/template_engine"
+	"github.com/tristendillon/conduit/core/version"
 	"github.com/tristendillon/conduit/core/walker"
+	"golang.org/x/sync/errgroup"
 )
 
 type RouteGenerator struct {
 	wd     string
 	Walker *walker.RouteWalkerImpl
+
+	// Concurrency overrides codegen.workers when greater than zero
+	Concurrency int
+
+	// TypeCheck type checks every route package before generating
+	TypeCheck bool
+
+	// EmitManifest imports the generation manifest before generating, skipping routes whose
+	// source is unchanged since it was written, and exports the updated manifest afterwards
+	EmitManifest bool
+
+	stateLoaded bool
+
+	// cfg is the config set by SetConfig; when nil the config is loaded from disk on each run
+	cfg atomic.Pointer[config.Config]
 }
 
+const defaultHitRateDropThreshold = 10.0
+
+// generatedRouteFileName is the name of every per-route generated file
+const generatedRouteFileName = "gen_route.go"
+
 func NewRouteGenerator(wd string) *RouteGenerator {
 	walker := walker.NewRouteWalker()
 	return &RouteGenerator{wd: wd, Walker: walker}
 }
 
+// SetConfig replaces the config used by later generations, e.g. after conduit.yaml was
+// edited during `conduit dev`. It is safe to call while a generation is running.
+func (rg *RouteGenerator) SetConfig(cfg *config.Config) {
+	rg.cfg.Store(cfg)
+}
+
+// loadConfig returns the config set by SetConfig, or loads it from disk
+func (rg *RouteGenerator) loadConfig() (*config.Config, error) {
+	if cfg := rg.cfg.Load(); cfg != nil {
+		return cfg, nil
+	}
+	return config.Load()
+}
+
 func (rg *RouteGenerator) GenerateRouteTree(logLevel logger.LogLevel) error {
 	walker := rg.Walker
+	if cfg := rg.cfg.Load(); cfg != nil {
+		walker.ApplyConfig(cfg)
+	}
 	moduleName := rg.getModuleName()
 	if _, err := walker.Walk(rg.wd, moduleName); err != nil {
 		return fmt.Errorf("failed to walk directory: %w", err)
 	}
 	walker.RouteTree.PrintTree(logLevel)
 
-	cfg, err := config.Load()
+	conflicts, err := walker.RouteTree.DetectConflicts()
+	if err != nil {
+		return fmt.Errorf("failed to detect route conflicts: %w", err)
+	}
+	for _, conflict := range conflicts {
+		logger.Warn("Route conflict: %s", conflict)
+	}
+
+	rg.warnHandlerNames(walker.RouteTree.Routes)
+
+	if rg.TypeCheck {
+		if err := rg.typeCheckRoutes(walker.RouteTree.Routes); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := rg.loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to get config: %w", err)
 	}
 
+	// Report broken template overrides before anything is written
+	if _, err := newTemplateEngine(rg.wd, cfg); err != nil {
+		return err
+	}
+	cache.GetCacheManager().SetTemplateHash(templateHash(rg.wd, cfg))
+
+	if err := rg.addHealthRoute(walker.RouteTree, cfg); err != nil {
+		return err
+	}
+
 	moduleName = rg.getModuleName()
 	if err := walker.RouteTree.CalculateOutputPaths(cfg, moduleName); err != nil {
 		return fmt.Errorf("failed to calculate output paths: %w", err)
 	}
 
+	if rg.EmitManifest {
+		if err := cache.GetCacheManager().ImportManifest(rg.generationManifestPath()); err != nil {
+			logger.Warn("Regenerating every route: %v", err)
+		}
+	}
+
+	// The client is generated first: generating the Go routes marks their sources as
+	// generated, after which the regeneration plan no longer lists them
+	if err := NewTypescriptGenerator(rg.wd).Generate(walker.RouteTree.Routes, cfg); err != nil {
+		return fmt.Errorf("failed to generate typescript client: %w", err)
+	}
+
+	if cfg.Codegen.Go.Mode == config.GoModeSingleFile {
+		if err := rg.generateSingleFile(walker.RouteTree.Routes, cfg); err != nil {
+			return fmt.Errorf("failed to generate single-file server: %w", err)
+		}
+		rg.exportGenerationManifest()
+		rg.reportCacheStats(cfg)
+		return nil
+	}
+
 	if err := rg.generatePerRouteFiles(walker.RouteTree.Routes); err != nil {
 		return fmt.Errorf("failed to generate per-route files: %w", err)
 	}
 
-	// Only generate routes registry if needed
-	if rg.needsRegistryRegeneration(walker.RouteTree.Routes) {
+	pruned, err := pruneOrphanedOutputs(walker.RouteTree.Routes, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to prune orphaned route files: %w", err)
+	}
+
+	// Only generate routes registry if needed; pruning changes the route set it imports
+	rg.loadCacheState()
+	if pruned > 0 || rg.needsRegistryRegeneration(walker.RouteTree.Routes, cfg) {
 		if err := rg.generateRoutesRegistry(walker.RouteTree.Routes, cfg); err != nil {
 			return fmt.Errorf("failed to generate routes registry: %w", err)
 		}
@@ -60,15 +157,154 @@ func (rg *RouteGenerator) GenerateRouteTree(logLevel logger.LogLevel) error {
 		logger.Debug("Routes registry is up to date, skipping generation")
 	}
 
+	rg.exportGenerationManifest()
+	rg.reportCacheStats(cfg)
+	return nil
+}
+
+// exportGenerationManifest writes the generation manifest when EmitManifest is set
+func (rg *RouteGenerator) exportGenerationManifest() {
+	if !rg.EmitManifest {
+		return
+	}
+	if err := cache.GetCacheManager().ExportManifest(rg.generationManifestPath()); err != nil {
+		logger.Warn("Failed to write generation manifest: %v", err)
+	}
+}
+
+// GenerateRegistryFromManifest builds the routes registry from a manifest instead of
+// walking the filesystem, for environments where the route sources aren't available.
+func (rg *RouteGenerator) GenerateRegistryFromManifest(manifestPath string) error {
+	manifest, err := models.LoadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	routes, err := manifest.ToRoutes()
+	if err != nil {
+		return fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	cfg, err := rg.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	if err := rg.generateRoutesRegistry(routes, cfg); err != nil {
+		return fmt.Errorf("failed to generate routes registry: %w", err)
+	}
+
+	logger.Info("Generated routes registry from manifest %s (%d routes)", manifestPath, len(routes))
+	return nil
+}
+
+func (rg *RouteGenerator) reportCacheStats(cfg *config.Config) {
 	cacheManager := cache.GetCacheManager()
+	statePath := rg.cacheStatePath()
+	rg.loadCacheState()
 
 	// Log cache statistics
 	stats := cacheManager.GetStats()
 	for layer, stat := range stats {
-		logger.Debug("%s cache stats: %d files, %.1f%% hit rate", layer, stat.TotalFiles, stat.HitRate)
+		if stat.IsWarmed {
+			logger.Debug("%s cache stats: %d files, %.1f%% hit rate, %.1f%% warm hit rate (%d/%d since warming)",
+				layer, stat.TotalFiles, stat.HitRate, stat.WarmHitRate, stat.WarmHits, stat.WarmHits+stat.WarmMisses)
+		} else {
+			logger.Debug("%s cache stats: %d files, %.1f%% hit rate", layer, stat.TotalFiles, stat.HitRate)
+		}
+		if stat.Evictions > 0 {
+			logger.Debug("%s cache evicted %d entries to stay within its limits", layer, stat.Evictions)
+		}
 	}
 
-	return nil
+	threshold := cfg.Cache.HitRateDropThreshold
+	if threshold <= 0 {
+		threshold = defaultHitRateDropThreshold
+	}
+	if previous, ok := cacheManager.GetPreviousStats(); ok {
+		for _, regression := range detectHitRateRegressions(previous, stats, threshold) {
+			logger.Warn("%s", regression)
+		}
+	}
+
+	if err := cacheManager.SaveState(statePath); err != nil {
+		logger.Debug("Failed to save cache state: %v", err)
+	}
+}
+
+// loadCacheState restores the state persisted by the previous run, once per generator
+func (rg *RouteGenerator) loadCacheState() {
+	if rg.stateLoaded {
+		return
+	}
+	if err := cache.GetCacheManager().LoadState(rg.cacheStatePath()); err != nil {
+		logger.Debug("Failed to load cache state: %v", err)
+	}
+	rg.stateLoaded = true
+}
+
+func (rg *RouteGenerator) cacheStatePath() string {
+	return filepath.Join(rg.wd, ".conduit", "cache.json")
+}
+
+func (rg *RouteGenerator) generationManifestPath() string {
+	return filepath.Join(rg.wd, ".conduit", "generation_manifest.json")
+}
+
+// warmHitRate returns the percentage of lookups since warm-up that hit
+func warmHitRate(stats *cacheModels.CacheStats) float64 {
+	return float64(stats.WarmHits) / float64(stats.WarmHits+stats.WarmMisses) * 100
+}
+
+func detectHitRateRegressions(previous, current map[string]*cacheModels.CacheStats, threshold float64) []string {
+	layers := make([]string, 0, len(current))
+	for layer := range current {
+		layers = append(layers, layer)
+	}
+	sort.Strings(layers)
+
+	var regressions []string
+	for _, layer := range layers {
+		before, ok := previous[layer]
+		after := current[layer]
+		if !ok || before == nil || after == nil {
+			continue
+		}
+		// Only lookups after warm-up are compared, since the cold-start misses say nothing
+		// about the cache. Layers without warm lookups have no meaningful hit rate.
+		if before.WarmHits+before.WarmMisses == 0 || after.WarmHits+after.WarmMisses == 0 {
+			continue
+		}
+
+		beforeRate, afterRate := warmHitRate(before), warmHitRate(after)
+		if drop := beforeRate - afterRate; drop >= threshold {
+			regressions = append(regressions, fmt.Sprintf(
+				"%s cache warm hit rate dropped from %.1f%% to %.1f%% since the last run (threshold %.1f points)",
+				layer, beforeRate, afterRate, threshold))
+		}
+	}
+	return regressions
+}
+
+func (rg *RouteGenerator) WalkRouteTree() (*models.RouteTree, error) {
+	walker := rg.Walker
+	if _, err := walker.Walk(rg.wd, rg.getModuleName()); err != nil {
+		return nil, fmt.Errorf("failed to walk directory: %w", err)
+	}
+
+	cfg, err := rg.loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	if err := rg.addHealthRoute(walker.RouteTree, cfg); err != nil {
+		return nil, err
+	}
+	return walker.RouteTree, nil
+}
+
+// ModuleName returns the module path declared in the project's go.mod
+func (rg *RouteGenerator) ModuleName() string {
+	return rg.getModuleName()
 }
 
 func (rg *RouteGenerator) getModuleName() string {
@@ -92,94 +328,302 @@ func (rg *RouteGenerator) getModuleName() string {
 }
 
 func (rg *RouteGenerator) generatePerRouteFiles(routes []models.Route) error {
-	engine := template_engine.NewTemplateEngine()
 	moduleName := rg.getModuleName()
 
 	// Load config to get output directory
-	cfg, err := config.Load()
+	cfg, err := rg.loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config for dependency copying: %w", err)
 	}
+	engine, err := newTemplateEngine(rg.wd, cfg)
+	if err != nil {
+		return err
+	}
 
-	// Create dependency copier
-	depCopier := dependency.NewDependencyCopier(rg.wd, moduleName, cfg.Codegen.Go.Output)
+	depCopier := rg.newDependencyCopier(cfg, moduleName)
+	rg.reportGeneratedImports(routes, depCopier)
+
+	group, ctx := errgroup.WithContext(context.Background())
+	group.SetLimit(rg.workerCount(cfg))
+
+	// Routes start in regeneration plan order, so a route starts before its dependents
+	for _, route := range planOrder(routes) {
+		group.Go(func() error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if !rg.needsRegeneration(route) {
+				logger.Debug("Skipping unchanged route: %s", route.FolderPath)
+				return nil
+			}
+
+			// Copy dependencies if they exist
+			var copiedDependencies []models.CopiedDependency
+			if route.ParsedFile != nil && route.ParsedFile.Dependencies != nil && len(route.ParsedFile.Dependencies.LocalImports) > 0 {
+				logger.Debug("Copying dependencies for route %s", route.FolderPath)
+				copiedDeps, err := depCopier.CopyDependencies(route.ParsedFile.Dependencies)
+				if err != nil {
+					logger.Debug("Failed to copy dependencies for route %s: %v", route.FolderPath, err)
+				} else {
+					copiedDependencies = copiedDeps
+					logger.Debug("Successfully copied %d dependencies for route %s", len(copiedDeps), route.FolderPath)
+				}
+			}
+
+			// Local packages imported by folder middleware are copied like the route's own
+			for _, file := range route.Middleware {
+				if file.Dependencies == nil || len(file.Dependencies.LocalImports) == 0 {
+					continue
+				}
+				copiedDeps, err := depCopier.CopyDependencies(file.Dependencies)
+				if err != nil {
+					logger.Debug("Failed to copy dependencies of %s: %v", file.Path, err)
+					continue
+				}
+				for _, dep := range copiedDeps {
+					if !slices.ContainsFunc(copiedDependencies, func(copied models.CopiedDependency) bool {
+						return copied.ImportPath == dep.ImportPath
+					}) {
+						copiedDependencies = append(copiedDependencies, dep)
+					}
+				}
+			}
+
+			middleware, middlewareImports := routeMiddleware(route, cfg.Codegen.Go.Router)
+			route.ParsedFile = migratePathParams(route, cfg.Codegen.Go.Router)
+
+			templateData := struct {
+				Route              models.Route
+				ModuleName         string
+				Router             string
+				Timestamp          time.Time
+				CopiedDependencies []models.CopiedDependency
+				Middleware         []generatedMiddleware
+				MiddlewareImports  []string
+			}{
+				Route:              route,
+				ModuleName:         moduleName,
+				Router:             cfg.Codegen.Go.Router,
+				Timestamp:          time.Now(),
+				CopiedDependencies: copiedDependencies,
+				Middleware:         middleware,
+				MiddlewareImports:  middlewareImports,
+			}
+
+			if err := engine.GenerateFile(template_engine.TEMPLATES.DEV.FULL_GEN_ROUTE_GO, route.OutputPath, templateData); err != nil {
+				return fmt.Errorf("failed to generate route file %s: %w", route.OutputPath, err)
+			}
+
+			if rg.isHealthRoute(route) {
+				logger.Debug("Generated built-in route %s", route.FolderPath)
+				return nil
+			}
+
+			// Mark the file as generated in the cache
+			cacheManager := cache.GetCacheManager()
+			if err := cacheManager.MarkGenerated(route.ParsedFile.Path, route.OutputPath); err != nil {
+				logger.Debug("Failed to mark %s as generated: %v", route.ParsedFile.Path, err)
+			}
 
+			logger.Debug("Generated %s for route %s with %d dependencies", route.RelativeOutput, route.FolderPath, len(copiedDependencies))
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return err
+	}
+
+	// Go rejects import cycles, so the generated code won't build until they're removed
+	for _, cycle := range depCopier.Cycles() {
+		logger.Warn("Import cycle between local packages: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// planOrder returns routes sorted like the regeneration plan for their sources: by
+// priority, then dependency order. Routes the plan doesn't list keep their order at the end.
+func planOrder(routes []models.Route) []models.Route {
+	var sources []string
 	for _, route := range routes {
-		if !rg.needsRegeneration(route) {
-			logger.Debug("Skipping unchanged route: %s", route.FolderPath)
-			continue
+		if route.ParsedFile != nil {
+			sources = append(sources, route.ParsedFile.Path)
+		}
+	}
+
+	cacheManager := cache.GetCacheManager()
+	plan, err := cacheManager.GetRegenerationPlan(sources)
+	if err != nil {
+		logger.Debug("Generating routes in tree order: %v", err)
+		return routes
+	}
+	for _, output := range cacheManager.GetPlannedOutputs(plan) {
+		logger.Debug("Planned to regenerate %s", output)
+	}
+	position := make(map[string]int, len(plan.AffectedFiles))
+	for i, filePath := range plan.AffectedFiles {
+		position[filePath] = i
+	}
+	rank := func(route models.Route) int {
+		if route.ParsedFile != nil {
+			if i, ok := position[cacheModels.CanonicalPath(route.ParsedFile.Path)]; ok {
+				return i
+			}
 		}
+		return len(position)
+	}
 
-		// Copy dependencies if they exist
-		var copiedDependencies []models.CopiedDependency
-		if route.ParsedFile != nil && route.ParsedFile.Dependencies != nil && len(route.ParsedFile.Dependencies.LocalImports) > 0 {
-			logger.Debug("Copying dependencies for route %s", route.FolderPath)
-			copiedDeps, err := depCopier.CopyDependencies(route.ParsedFile.Dependencies)
+	ordered := slices.Clone(routes)
+	slices.SortStableFunc(ordered, func(a, b models.Route) int {
+		return rank(a) - rank(b)
+	})
+	return ordered
+}
+
+// relativeOutputDirs returns the Go output directories relative to the project root
+func (rg *RouteGenerator) relativeOutputDirs(cfg *config.Config) []string {
+	var dirs []string
+	for _, dir := range cfg.GoOutputDirs() {
+		if filepath.IsAbs(dir) {
+			rel, err := filepath.Rel(rg.wd, dir)
 			if err != nil {
-				logger.Debug("Failed to copy dependencies for route %s: %v", route.FolderPath, err)
-			} else {
-				copiedDependencies = copiedDeps
-				logger.Debug("Successfully copied %d dependencies for route %s", len(copiedDeps), route.FolderPath)
+				continue
 			}
+			dir = rel
 		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
 
-		templateData := struct {
-			Route              models.Route
-			ModuleName         string
-			Timestamp          time.Time
-			CopiedDependencies []models.CopiedDependency
-		}{
-			Route:              route,
-			ModuleName:         moduleName,
-			Timestamp:          time.Now(),
-			CopiedDependencies: copiedDependencies,
+// reportGeneratedImports logs an error for every route or folder middleware that imports a
+// package conduit generates, which the copier refuses to copy, and marks those packages
+// as generated in the dependency graph
+func (rg *RouteGenerator) reportGeneratedImports(routes []models.Route, depCopier *dependency.DependencyCopier) {
+	graph := cache.GetCacheManager().GetDependencyGraph()
+	// Folder middleware is shared by the routes below it, so report each import once
+	reported := make(map[string]bool)
+	for _, route := range routes {
+		files := append([]*models.ParsedFile{route.ParsedFile}, route.Middleware...)
+		for _, file := range files {
+			if file == nil || file.Dependencies == nil {
+				continue
+			}
+			for _, dep := range file.Dependencies.LocalImports {
+				if !depCopier.IsGenerated(dep) || reported[file.Path+" "+dep.ImportPath] {
+					continue
+				}
+				reported[file.Path+" "+dep.ImportPath] = true
+				logger.Error("Route %s imports generated package %s in %s; generated code can't be a dependency, import the source package instead",
+					route.FolderPath, dep.ImportPath, rg.relPath(file.Path))
+				if err := graph.SetNodeType(dep.ImportPath, cacheModels.GeneratedFile); err != nil {
+					logger.Debug("Failed to mark %s as generated: %v", dep.ImportPath, err)
+				}
+			}
 		}
+	}
+}
 
-		if err := engine.GenerateFile(template_engine.TEMPLATES.DEV.FULL_GEN_ROUTE_GO, route.OutputPath, templateData); err != nil {
-			return fmt.Errorf("failed to generate route file %s: %w", route.OutputPath, err)
+// warnHandlerNames warns about functions in route files that look like misnamed handlers
+func (rg *RouteGenerator) warnHandlerNames(routes []models.Route) {
+	for _, route := range routes {
+		if route.ParsedFile == nil {
+			continue
 		}
+		violations, err := ast.CheckHandlerNames(route.ParsedFile.Path)
+		if err != nil {
+			// Unparseable route files are already reported by the walker
+			continue
+		}
+		for _, violation := range violations {
+			logger.Warn("%s: %s", route.FolderPath, violation.Issue)
+		}
+	}
+}
 
-		// Mark the file as generated in the cache
-		cacheManager := cache.GetCacheManager()
-		if err := cacheManager.MarkGenerated(route.ParsedFile.Path, route.OutputPath); err != nil {
-			logger.Debug("Failed to mark %s as generated: %v", route.ParsedFile.Path, err)
+// typeCheckRoutes runs the type checker over every route package and fails if any
+// route would not compile, logging the errors of each one
+func (rg *RouteGenerator) typeCheckRoutes(routes []models.Route) error {
+	var dirs []string
+	for _, route := range routes {
+		if route.ParsedFile == nil {
+			continue
+		}
+		dir, err := filepath.Rel(rg.wd, filepath.Dir(route.ParsedFile.Path))
+		if err != nil {
+			return fmt.Errorf("failed to resolve route directory for %s: %w", route.ParsedFile.Path, err)
 		}
+		dirs = append(dirs, dir)
+	}
 
-		logger.Debug("Generated %s for route %s with %d dependencies", route.RelativeOutput, route.FolderPath, len(copiedDependencies))
+	startTime := time.Now()
+	failures, err := ast.TypeCheckRoutes(rg.wd, dirs)
+	if err != nil {
+		return err
 	}
+	logger.Debug("Type checked %d routes in %v", len(dirs), time.Since(startTime))
 
+	for _, failure := range failures {
+		logger.Error("%s", failure)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d routes failed type checking", len(failures), len(dirs))
+	}
 	return nil
 }
 
-func (rg *RouteGenerator) generateRoutesRegistry(routes []models.Route, cfg *config.Config) error {
+func (rg *RouteGenerator) workerCount(cfg *config.Config) int {
+	if rg.Concurrency > 0 {
+		return rg.Concurrency
+	}
+	if cfg.Codegen.Workers > 0 {
+		return cfg.Codegen.Workers
+	}
+	return runtime.NumCPU()
+}
+
+// newTemplateEngine creates a template engine that enforces the configured file size limit
+// and prefers the templates in codegen.go.template_dir, relative to wd, over the built-in ones
+func newTemplateEngine(wd string, cfg *config.Config) (*template_engine.TemplateEngine, error) {
 	engine := template_engine.NewTemplateEngine()
+	engine.SetMaxFileBytes(cfg.Codegen.MaxFileBytes)
 
-	templateData := struct {
-		Routes      []models.Route
-		PackageName string
-		ModuleName  string
-		Timestamp   time.Time
-	}{
-		Routes:      routes,
-		PackageName: "generated",
-		ModuleName:  rg.getModuleName(),
-		Timestamp:   time.Now(),
+	if dir := templateDir(wd, cfg); dir != "" {
+		if err := engine.SetTemplateDir(dir); err != nil {
+			return nil, fmt.Errorf("codegen.go.template_dir: %w", err)
+		}
 	}
+	return engine, nil
+}
 
-	registryPath := filepath.Join(cfg.Codegen.Go.Output, "routes_registry.go")
-	if err := engine.GenerateFile(template_engine.TEMPLATES.DEV.ROUTES_REGISTRY_GO, registryPath, templateData); err != nil {
-		return fmt.Errorf("failed to generate routes registry: %w", err)
+// templateDir resolves codegen.go.template_dir against wd, or returns "" when it's unset
+func templateDir(wd string, cfg *config.Config) string {
+	dir := cfg.Codegen.Go.TemplateDir
+	if dir != "" && !filepath.IsAbs(dir) {
+		dir = filepath.Join(wd, dir)
 	}
+	return dir
+}
 
-	// Update registry signature in cache
-	cacheManager := cache.GetCacheManager()
-	routePaths := make([]string, len(routes))
-	for i, route := range routes {
-		routePaths[i] = route.FolderPath
+// templateHash identifies the templates routes are generated from: the built-in ones of
+// this conduit version with the contents of any overrides
+func templateHash(wd string, cfg *config.Config) string {
+	return version.Version + "/" + template_engine.OverridesFingerprint(templateDir(wd, cfg))
+}
+
+func (rg *RouteGenerator) generateRoutesRegistry(routes []models.Route, cfg *config.Config) error {
+	engine, err := newTemplateEngine(rg.wd, cfg)
+	if err != nil {
+		return err
+	}
+	if err := rg.writeRoutesRegistry(engine, routes, cfg); err != nil {
+		return err
 	}
 
-	// Create new signature with proper hash calculation
-	signature := rg.createRegistrySignature(routePaths)
+	// Update registry signature in cache
+	cacheManager := cache.GetCacheManager()
+	signature := cacheModels.NewRegistrySignature(registryEntries(routes, cfg, rg.wd))
 
 	if err := cacheManager.SetRegistrySignature(signature); err != nil {
 		logger.Debug("Failed to update registry signature: %v", err)
@@ -189,6 +633,15 @@ func (rg *RouteGenerator) generateRoutesRegistry(routes []models.Route, cfg *con
 	return nil
 }
 
+// newDependencyCopier creates a copier into the Go output directory that skips provided
+// packages and the generated output directories
+func (rg *RouteGenerator) newDependencyCopier(cfg *config.Config, moduleName string) *dependency.DependencyCopier {
+	depCopier := dependency.NewDependencyCopier(rg.wd, moduleName, cfg.Codegen.Go.Output)
+	depCopier.SetProvidedPackages(cfg.Codegen.Go.ProvidedPackages)
+	depCopier.SetGeneratedDirs(rg.relativeOutputDirs(cfg))
+	return depCopier
+}
+
 func (rg *RouteGenerator) needsRegeneration(route models.Route) bool {
 	// Check if output file exists
 	if _, err := os.Stat(route.OutputPath); os.IsNotExist(err) {
@@ -196,39 +649,58 @@ func (rg *RouteGenerator) needsRegeneration(route models.Route) bool {
 		return true
 	}
 
-	cacheManager := cache.GetCacheManager()
+	// The built-in health route has no source file for the cache to track, and it's cheap
+	if rg.isHealthRoute(route) {
+		return true
+	}
 
-	// Get a regeneration plan for this specific file
-	plan, err := cacheManager.GetRegenerationPlan([]string{route.ParsedFile.Path})
+	if rg.middlewareChanged(route) {
+		logger.Debug("Regeneration needed for route: %s - folder middleware may have changed", route.FolderPath)
+		return true
+	}
+
+	affected, reason, err := planAffects(route.ParsedFile.Path)
 	if err != nil {
 		logger.Debug("Failed to get regeneration plan for %s: %v, assuming regeneration needed", route.ParsedFile.Path, err)
 		return true
 	}
-
-	// Check if this route is in the affected files list
-	for _, affectedFile := range plan.AffectedFiles {
-		if affectedFile == route.ParsedFile.Path {
-			reason := plan.Reasons[affectedFile]
-			logger.Debug("Regeneration needed for route: %s (source: %s) - %s", route.FolderPath, route.ParsedFile.Path, reason)
-			return true
-		}
+	if affected {
+		logger.Debug("Regeneration needed for route: %s (source: %s) - %s", route.FolderPath, route.ParsedFile.Path, reason)
+		return true
 	}
 
 	logger.Debug("No regeneration needed for route: %s (source: %s)", route.FolderPath, route.ParsedFile.Path)
 	return false
 }
 
-func (rg *RouteGenerator) needsRegistryRegeneration(routes []models.Route) bool {
-	cacheManager := cache.GetCacheManager()
+// planAffects reports whether the regeneration plan for a source file lists it, and why
+func planAffects(source string) (bool, string, error) {
+	plan, err := cache.GetCacheManager().GetRegenerationPlan([]string{source})
+	if err != nil {
+		return false, "", err
+	}
+
+	// Plans list files by cache key
+	key := cacheModels.CanonicalPath(source)
+	for _, affectedFile := range plan.AffectedFiles {
+		if affectedFile == key {
+			return true, plan.Reasons[affectedFile], nil
+		}
+	}
+	return false, "", nil
+}
 
-	// Extract route paths (the structural information we care about for registry)
-	routePaths := make([]string, len(routes))
-	for i, route := range routes {
-		routePaths[i] = route.FolderPath
+func (rg *RouteGenerator) needsRegistryRegeneration(routes []models.Route, cfg *config.Config) bool {
+	registryPath := filepath.Join(rg.wd, cfg.Codegen.Go.Output, "routes_registry.go")
+	if _, err := os.Stat(registryPath); os.IsNotExist(err) {
+		logger.Debug("Routes registry does not exist, regeneration needed: %s", registryPath)
+		return true
 	}
 
+	cacheManager := cache.GetCacheManager()
+
 	// Check if registry needs regeneration
-	needsRegen, err := cacheManager.NeedsRegistryRegeneration(routePaths)
+	needsRegen, err := cacheManager.NeedsRegistryRegeneration(registryEntries(routes, cfg, rg.wd))
 	if err != nil {
 		logger.Debug("Failed to check registry regeneration: %v, assuming regeneration needed", err)
 		return true
@@ -237,21 +709,110 @@ func (rg *RouteGenerator) needsRegistryRegeneration(routes []models.Route) bool
 	return needsRegen
 }
 
-func (rg *RouteGenerator) createRegistrySignature(routePaths []string) *cacheModels.RegistrySignature {
-	// Sort the routes for consistent signature generation
-	sortedPaths := make([]string, len(routePaths))
-	copy(sortedPaths, routePaths)
-	sort.Strings(sortedPaths)
+// registryEntries describes everything the registry is generated from: each route's
+// folder with its methods, plus the settings, conduit version and template overrides, by
+// content, that shape the output. The signature persists across runs, so anything that
+// changes the registry must be here.
+func registryEntries(routes []models.Route, cfg *config.Config, wd string) []string {
+	entries := make([]string, 0, len(routes)+1)
+	for _, route := range routes {
+		methods := make([]string, len(route.Methods))
+		copy(methods, route.Methods)
+		sort.Strings(methods)
+		entries = append(entries, route.FolderPath+" "+strings.Join(methods, ","))
+	}
 
-	// Create hash from sorted route paths
-	data := strings.Join(sortedPaths, "|")
-	hash := md5.Sum([]byte(data))
-	signature := fmt.Sprintf("%x", hash)
+	entries = append(entries, fmt.Sprintf("#config router=%s metrics=%t group_depth=%d max_file_bytes=%d output=%s templates=%s",
+		cfg.Codegen.Go.Router, cfg.Codegen.Go.Metrics, cfg.Codegen.Go.RegistryGroupDepth,
+		cfg.Codegen.MaxFileBytes, cfg.Codegen.Go.Output, templateHash(wd, cfg)))
+	return entries
+}
 
-	return &cacheModels.RegistrySignature{
-		RouteCount: len(routePaths),
-		RoutePaths: sortedPaths,
-		Signature:  signature,
-		UpdatedAt:  time.Now(),
+// RemoveStaleOutputs deletes generated files whose source no longer exists at the path they
+// were generated from, along with any directories the removal leaves empty inside the
+// output root (one of roots) holding the file. Directories outside every root are kept.
+func RemoveStaleOutputs(outputs []string, roots []string) error {
+	absRoots := make([]string, 0, len(roots))
+	for _, root := range roots {
+		if absRoot, err := filepath.Abs(root); err == nil {
+			absRoots = append(absRoots, absRoot)
+		}
+	}
+
+	var errs []error
+	for _, output := range outputs {
+		if err := os.Remove(output); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("failed to remove %s: %w", output, err))
+			continue
+		}
+		logger.Debug("Removed stale generated file %s", output)
+
+		absOutput, err := filepath.Abs(output)
+		if err != nil {
+			continue
+		}
+		for _, root := range absRoots {
+			if !strings.HasPrefix(absOutput, root+string(filepath.Separator)) {
+				continue
+			}
+			// Prune directories emptied by the removal, stopping at the output root
+			for dir := filepath.Dir(absOutput); dir != root; dir = filepath.Dir(dir) {
+				if err := os.Remove(dir); err != nil {
+					break
+				}
+			}
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pruneOrphanedOutputs removes the generated route files findOrphanedOutputs reports and
+// returns the number removed
+func pruneOrphanedOutputs(routes []models.Route, cfg *config.Config) (int, error) {
+	orphans, err := findOrphanedOutputs(routes, cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, orphan := range orphans {
+		logger.Info("Removing orphaned generated route %s", orphan)
+	}
+	return len(orphans), RemoveStaleOutputs(orphans, cfg.GoOutputDirs())
+}
+
+// findOrphanedOutputs returns the generated route files under the routes outputs (the
+// default one and any route overrides) that no longer correspond to a route. Only files
+// named like generated routes are considered, so user files living in the output tree
+// are left alone.
+func findOrphanedOutputs(routes []models.Route, cfg *config.Config) ([]string, error) {
+	expected := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		expected[filepath.Clean(route.OutputPath)] = true
+	}
+
+	var orphans []string
+	for _, output := range cfg.GoOutputDirs() {
+		routesDir := filepath.Join(output, "routes")
+		if _, err := os.Stat(routesDir); os.IsNotExist(err) {
+			continue
+		}
+
+		err := filepath.WalkDir(routesDir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || d.Name() != generatedRouteFileName {
+				return nil
+			}
+			if !expected[filepath.Clean(path)] {
+				orphans = append(orphans, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
+	return orphans, nil
 }
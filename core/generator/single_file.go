@@ -0,0 +1,257 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tristendillon/conduit/core/ast"
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/models"
+	"github.com/tristendillon/conduit/core/template_engine"
+)
+
+type inlinedHandler struct {
+	Name    string
+	Method  string
+	APIPath string
+	Pattern string
+	Source  string
+	Params  string
+	Body    string
+	// @conduit key=value annotations from the handler's doc comment
+	Annotations map[string]string
+}
+
+// inlinedDeclarations are the top-level declarations of a route file other than its handlers
+type inlinedDeclarations struct {
+	Source string
+	Code   string
+}
+
+// singleFileImportsProvided are always imported by the single-file template (chi is added when targeted)
+var singleFileImportsProvided = map[string]bool{
+	"log":      true,
+	"net/http": true,
+	"os":       true,
+}
+
+// ServerAddrEnv overrides the address the single-file server listens on, which otherwise
+// comes from the server config at generation time
+const ServerAddrEnv = "CONDUIT_ADDR"
+
+// SingleFileServerPath returns the main.go generated in single-file mode. It gets its own
+// directory since the Go output directory holds the generated package.
+func SingleFileServerPath(cfg *config.Config) string {
+	return filepath.Join(cfg.Codegen.Go.Output, "server", "main.go")
+}
+
+func (rg *RouteGenerator) generateSingleFile(routes []models.Route, cfg *config.Config) error {
+	engine, err := newTemplateEngine(rg.wd, cfg)
+	if err != nil {
+		return err
+	}
+
+	sorted := make([]models.Route, len(routes))
+	copy(sorted, routes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].APIPath < sorted[j].APIPath
+	})
+
+	var handlers []inlinedHandler
+	var declarations []inlinedDeclarations
+	var parsedFiles []*models.ParsedFile
+	for _, route := range sorted {
+		if route.ParsedFile == nil {
+			continue
+		}
+		if len(route.Middleware) > 0 {
+			logger.Warn("Route %s: %s middleware isn't applied in %s mode", route.FolderPath, ast.MiddlewareFileName, config.GoModeSingleFile)
+		}
+
+		// Every route file's declarations are prefixed so they can share the package
+		prefix := strings.TrimSuffix(route.PackageAlias, "_route")
+		handlerName := func(method string) string { return prefix + "_" + method }
+		src, err := rg.routeSource(route)
+		if err != nil {
+			return err
+		}
+		inlined, err := ast.InlineRouteSource(route.ParsedFile.Path, src, prefix+"_", handlerName)
+		if err != nil {
+			return fmt.Errorf("failed to inline route %s: %w", route.FolderPath, err)
+		}
+		if route.ParsedFile, err = ast.ParseRouteSource(route.ParsedFile.Path, route.ParsedFile.RelPath, rg.getModuleName(), inlined.Source); err != nil {
+			return fmt.Errorf("failed to parse inlined route %s: %w", route.FolderPath, err)
+		}
+		if inlined.Declarations != "" {
+			declarations = append(declarations, inlinedDeclarations{Source: route.ParsedFile.RelPath, Code: inlined.Declarations})
+		}
+
+		parsed := migratePathParams(route, cfg.Codegen.Go.Router)
+		parsedFiles = append(parsedFiles, parsed)
+
+		for _, fn := range parsed.Functions {
+			handlers = append(handlers, inlinedHandler{
+				Name:        handlerName(fn.Method),
+				Method:      fn.Method,
+				APIPath:     route.APIPath,
+				Pattern:     route.Pattern,
+				Source:      parsed.RelPath,
+				Params:      strings.TrimPrefix(fn.Signature, fn.Name),
+				Body:        fn.Body,
+				Annotations: fn.Annotations,
+			})
+		}
+	}
+
+	provided := singleFileImportsProvided
+	if cfg.Codegen.Go.Router == config.RouterChi {
+		provided = map[string]bool{ChiImportPath: true}
+		for importPath := range singleFileImportsProvided {
+			provided[importPath] = true
+		}
+	}
+
+	imports, err := mergeImports(parsedFiles, provided)
+	if err != nil {
+		return fmt.Errorf("failed to merge handler imports: %w", err)
+	}
+
+	var metricsCode string
+	if cfg.Codegen.Go.Metrics {
+		if metricsCode, err = renderEmbeddedMetrics(engine); err != nil {
+			return err
+		}
+		imports = addImports(imports, metricsImports)
+	}
+
+	templateData := struct {
+		Handlers     []inlinedHandler
+		Declarations []inlinedDeclarations
+		Imports      []string
+		ModuleName   string
+		Router       string
+		Metrics      bool
+		MetricsRoute bool
+		MetricsCode  string
+		Addr         string
+		AddrEnv      string
+		Timestamp    time.Time
+	}{
+		Handlers:     handlers,
+		Declarations: declarations,
+		Imports:      imports,
+		ModuleName:   rg.getModuleName(),
+		Router:       cfg.Codegen.Go.Router,
+		Metrics:      cfg.Codegen.Go.Metrics,
+		MetricsRoute: cfg.Codegen.Go.Metrics && metricsRouteAvailable(routes),
+		MetricsCode:  metricsCode,
+		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
+		AddrEnv:      ServerAddrEnv,
+		Timestamp:    time.Now(),
+	}
+
+	outputPath := SingleFileServerPath(cfg)
+	if err := engine.GenerateFile(template_engine.TEMPLATES.DEV.SINGLE_FILE_SERVER_GO, outputPath, templateData); err != nil {
+		return fmt.Errorf("failed to generate single-file server: %w", err)
+	}
+	removeLegacySingleFileServer(cfg)
+
+	logger.Debug("Generated single-file server %s with %d handlers", outputPath, len(handlers))
+	return nil
+}
+
+// routeSource returns the source of a route file, which for the built-in health route
+// only exists in memory
+func (rg *RouteGenerator) routeSource(route models.Route) ([]byte, error) {
+	if rg.isHealthRoute(route) {
+		return healthRouteSource(), nil
+	}
+	src, err := os.ReadFile(route.ParsedFile.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read route %s: %w", route.FolderPath, err)
+	}
+	return src, nil
+}
+
+// removeLegacySingleFileServer removes the main.go earlier versions generated directly in
+// the Go output directory, where it breaks the generated package
+func removeLegacySingleFileServer(cfg *config.Config) {
+	legacy := filepath.Join(cfg.Codegen.Go.Output, "main.go")
+	content, err := os.ReadFile(legacy)
+	if err != nil || !bytes.Contains(content, []byte("// Single-file server containing every route handler")) {
+		return
+	}
+	if err := os.Remove(legacy); err != nil {
+		logger.Debug("Failed to remove %s: %v", legacy, err)
+		return
+	}
+	logger.Info("Moved the single-file server from %s to %s", legacy, SingleFileServerPath(cfg))
+}
+
+// mergeImports deduplicates import statements across route files and rejects
+// distinct packages that would be visible under the same name.
+func mergeImports(parsedFiles []*models.ParsedFile, provided map[string]bool) ([]string, error) {
+	statements := make(map[string]bool)
+	owners := make(map[string]string) // package name -> import path
+
+	for _, parsed := range parsedFiles {
+		for _, statement := range parsed.Imports {
+			alias, importPath := splitImportStatement(statement)
+			if provided[importPath] && alias == "" {
+				continue
+			}
+
+			name := alias
+			if name == "" {
+				name = path.Base(importPath)
+			}
+			if name != "_" && name != "." {
+				if owner, exists := owners[name]; exists && owner != importPath {
+					return nil, fmt.Errorf("import name %q is used by both %q and %q (in %s)", name, owner, importPath, parsed.RelPath)
+				}
+				owners[name] = importPath
+			}
+
+			statements[statement] = true
+		}
+	}
+
+	result := make([]string, 0, len(statements))
+	for statement := range statements {
+		result = append(result, statement)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// addImports adds import statements that aren't already present, keeping the result sorted
+func addImports(imports []string, extra []string) []string {
+	present := make(map[string]bool, len(imports))
+	for _, statement := range imports {
+		present[statement] = true
+	}
+	for _, statement := range extra {
+		if !present[statement] {
+			imports = append(imports, statement)
+			present[statement] = true
+		}
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+// splitImportStatement splits `alias "path"` or `"path"` into its parts
+func splitImportStatement(statement string) (string, string) {
+	statement = strings.TrimSpace(statement)
+	if idx := strings.Index(statement, " "); idx != -1 {
+		return statement[:idx], strings.Trim(statement[idx+1:], "\"")
+	}
+	return "", strings.Trim(statement, "\"")
+}
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+// singleFileProject lays out routes whose files share imports and declare the same helper,
+// which the single file has to merge and rename to compile
+var singleFileProject = map[string]string{
+	"go.mod": "module example.com/app\n\ngo 1.25\n",
+	"conduit.yaml": `app_name: app
+codegen:
+  go:
+    output: "./.conduit/go"
+    mode: single-file
+  typescript:
+    output: "./.conduit/ts"
+`,
+	"api/users/route.go": `package users
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func respond(w http.ResponseWriter, v any) {
+	json.NewEncoder(w).Encode(v)
+}
+
+func GET(w http.ResponseWriter, r *http.Request) {
+	respond(w, []string{"ada", "grace"})
+}
+
+func POST(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusCreated)
+	respond(w, "created")
+}
+`,
+	"api/users/id_/route.go": `package id_
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func respond(w http.ResponseWriter, v any) {
+	json.NewEncoder(w).Encode(v)
+}
+
+func GET(w http.ResponseWriter, r *http.Request) {
+	respond(w, r.PathValue("id"))
+}
+`,
+}
+
+// singleFileServerTest is compiled next to the generated main.go and fails unless
+// RegisterRoutes serves every route of singleFileProject
+const singleFileServerTest = `package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterRoutes(mux)
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{http.MethodGet, "/api/users", http.StatusOK, "[\"ada\",\"grace\"]\n"},
+		{http.MethodPost, "/api/users", http.StatusCreated, "\"created\"\n"},
+		{http.MethodGet, "/api/users/42", http.StatusOK, "\"42\"\n"},
+		{http.MethodGet, "/__conduit/health", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != tt.status {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, rec.Code, tt.status)
+		}
+		if tt.body != "" && rec.Body.String() != tt.body {
+			t.Errorf("%s %s body = %q, want %q", tt.method, tt.path, rec.Body.String(), tt.body)
+		}
+	}
+}
+`
+
+func TestSingleFileServerRegistersEveryRoute(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go toolchain")
+	}
+	dir := t.TempDir()
+	for name, content := range singleFileProject {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rg := NewRouteGenerator(dir)
+	rg.SetConfig(cfg)
+	if err := rg.GenerateRouteTree(logger.DEBUG); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	serverDir := filepath.Dir(SingleFileServerPath(cfg))
+	if err := os.WriteFile(filepath.Join(serverDir, "server_test.go"), []byte(singleFileServerTest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	goRun(t, dir, "vet", "./"+filepath.ToSlash(serverDir))
+	goRun(t, dir, "test", "./"+filepath.ToSlash(serverDir))
+}
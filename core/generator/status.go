@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/tristendillon/conduit/core/cache"
+	cacheModels "github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/config"
+)
+
+// StatusReport summarizes the project's configuration, routes, cache and generated output
+type StatusReport struct {
+	// ConfigFile is the config file in use, empty when the defaults apply
+	ConfigFile string `json:"config_file"`
+	AppName    string `json:"app_name"`
+	Address    string `json:"address"`
+	Mode       string `json:"mode"`
+	Router     string `json:"router"`
+	// GoOutputs are the default Go output directory followed by the route override ones
+	GoOutputs        []string `json:"go_outputs"`
+	TypescriptOutput string   `json:"typescript_output"`
+	Routes           int      `json:"routes"`
+	// CacheStats are the cache layers after walking the routes, keyed by layer
+	CacheStats map[string]*cacheModels.CacheStats `json:"cache_stats"`
+	// LastGeneratedAt is the newest generation recorded in the generation manifest or the
+	// cache state, nil when nothing has been generated
+	LastGeneratedAt *time.Time `json:"last_generated_at"`
+	// Cycles are the dependency cycles, each starting and ending with the same file
+	Cycles [][]string `json:"cycles"`
+	// Conflicts are routes from distinct folders that match the same requests
+	Conflicts []string `json:"conflicts"`
+	// OrphanedOutputs are generated route files and clients whose route no longer exists
+	OrphanedOutputs []string `json:"orphaned_outputs"`
+}
+
+// Healthy reports whether no cycles, conflicts or orphaned outputs were found
+func (r *StatusReport) Healthy() bool {
+	return len(r.Cycles) == 0 && len(r.Conflicts) == 0 && len(r.OrphanedOutputs) == 0
+}
+
+// Status walks the routes and reads the persisted generation records to summarize the
+// project. Nothing is generated or written.
+func (rg *RouteGenerator) Status() (*StatusReport, error) {
+	cfg, err := rg.loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	tree, err := rg.WalkRouteTree()
+	if err != nil {
+		return nil, err
+	}
+
+	configFile, _ := config.FindFile(rg.wd)
+	report := &StatusReport{
+		AppName:          cfg.AppName,
+		Address:          fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
+		Mode:             cfg.Codegen.Go.Mode,
+		Router:           cfg.Codegen.Go.Router,
+		GoOutputs:        cfg.GoOutputDirs(),
+		TypescriptOutput: cfg.Codegen.Typescript.Output,
+		Routes:           len(tree.Routes),
+		CacheStats:       cache.GetGlobalCacheStats(),
+		Cycles:           [][]string{},
+		Conflicts:        []string{},
+		OrphanedOutputs:  []string{},
+	}
+	if configFile != "" {
+		report.ConfigFile = rg.relPath(configFile)
+	}
+	if report.Mode == "" {
+		report.Mode = config.GoModeMultiFile
+	}
+	if report.Router == "" {
+		report.Router = config.RouterStdlib
+	}
+
+	lastGenerated, err := rg.lastGeneratedAt()
+	if err != nil {
+		return nil, err
+	}
+	report.LastGeneratedAt = lastGenerated
+
+	cycles, err := cache.GetCacheManager().GetDependencyGraph().DetectCycles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect dependency cycles: %w", err)
+	}
+	for _, cycle := range cycles {
+		closed := make([]string, 0, len(cycle)+1)
+		for _, node := range cycle {
+			closed = append(closed, rg.relPath(filepath.FromSlash(node)))
+		}
+		report.Cycles = append(report.Cycles, append(closed, closed[0]))
+	}
+
+	conflicts, err := tree.DetectConflicts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect route conflicts: %w", err)
+	}
+	for _, conflict := range conflicts {
+		report.Conflicts = append(report.Conflicts, conflict.String())
+	}
+
+	// Single-file mode writes no per-route files, so only clients can be orphaned
+	if report.Mode == config.GoModeMultiFile {
+		if err := tree.CalculateOutputPaths(cfg, rg.getModuleName()); err != nil {
+			return nil, fmt.Errorf("failed to calculate output paths: %w", err)
+		}
+		orphans, err := findOrphanedOutputs(tree.Routes, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find orphaned route files: %w", err)
+		}
+		for _, orphan := range orphans {
+			report.OrphanedOutputs = append(report.OrphanedOutputs, rg.relPath(orphan))
+		}
+	}
+	outputDir := NewTypescriptGenerator(rg.wd).outputDir(cfg)
+	clients, err := tsRoutes(tree.Routes, outputDir)
+	if err != nil {
+		return nil, err
+	}
+	orphans, err := findOrphanedClients(clients, outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orphaned clients: %w", err)
+	}
+	for _, orphan := range orphans {
+		report.OrphanedOutputs = append(report.OrphanedOutputs, rg.relPath(orphan))
+	}
+	sort.Strings(report.OrphanedOutputs)
+
+	return report, nil
+}
+
+// lastGeneratedAt returns the newest generation time recorded in the cache state every
+// generation saves or in the generation manifest conduit generate --emit-manifest writes,
+// or nil when neither records one
+func (rg *RouteGenerator) lastGeneratedAt() (*time.Time, error) {
+	var state cacheModels.PersistedState
+	if err := readJSON(rg.cacheStatePath(), &state); err != nil {
+		return nil, fmt.Errorf("failed to read cache state: %w", err)
+	}
+	var manifest cacheModels.GenerationManifest
+	if err := readJSON(rg.generationManifestPath(), &manifest); err != nil {
+		return nil, fmt.Errorf("failed to read generation manifest: %w", err)
+	}
+
+	var latest *time.Time
+	for _, info := range append(state.Generations, manifest.Generations...) {
+		if latest == nil || info.GeneratedAt.After(*latest) {
+			latest = &info.GeneratedAt
+		}
+	}
+	return latest, nil
+}
+
+// readJSON decodes the file at path into v, leaving v unchanged when it doesn't exist
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
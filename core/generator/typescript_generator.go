@@ -0,0 +1,429 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/models"
+	"github.com/tristendillon/conduit/core/shared"
+	"github.com/tristendillon/conduit/core/template_engine"
+)
+
+// generatedClientFileName is the name of every per-route generated TypeScript file
+const generatedClientFileName = "route.ts"
+
+// tsIdentifierPattern matches names that can be used as TypeScript property names unquoted
+var tsIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsTemplateLiteral escapes text for use inside a TypeScript template literal
+var tsTemplateLiteral = strings.NewReplacer("\\", "\\\\", "`", "\\`", "${", "\\${")
+
+// TypescriptGenerator writes a typed fetch client for the route tree to
+// codegen.typescript.output: a routes/<folder>/route.ts per route with one function per
+// HTTP method and an interface per exported struct of the route file, a client.ts they send
+// requests through and an index.ts re-exporting them.
+type TypescriptGenerator struct {
+	wd string
+}
+
+type tsFunction struct {
+	Name         string
+	Method       string
+	ResponseType string // TypeScript type of the JSON response, empty when it isn't known
+	// Doc holds the lines of the handler's description for the function's doc comment
+	Doc []string
+}
+
+type tsField struct {
+	Key      string
+	Type     string
+	Optional bool
+}
+
+// tsInterface is the interface generated for a struct declared in a route file
+type tsInterface struct {
+	Name   string
+	Fields []tsField
+}
+
+// tsRoute is the client generated for a route
+type tsRoute struct {
+	Route      models.Route
+	Functions  []tsFunction
+	Interfaces []tsInterface
+	Params     string // TypeScript type of the path parameters, empty without any
+	URL        string // body of the template literal building the request path
+	Output     string
+	Module     string // import path of the output relative to the output directory
+}
+
+// Typed reports whether any function of the route knows its response type
+func (client tsRoute) Typed() bool {
+	return slices.ContainsFunc(client.Functions, func(function tsFunction) bool {
+		return function.ResponseType != ""
+	})
+}
+
+func NewTypescriptGenerator(wd string) *TypescriptGenerator {
+	return &TypescriptGenerator{wd: wd}
+}
+
+// Generate writes the client for every route with methods, skipping routes the
+// regeneration plan doesn't list, and removes the clients of routes that no longer exist
+func (tg *TypescriptGenerator) Generate(routes []models.Route, cfg *config.Config) error {
+	outputDir := tg.outputDir(cfg)
+	clients, err := tsRoutes(routes, outputDir)
+	if err != nil {
+		return err
+	}
+
+	engine, err := newTemplateEngine(tg.wd, cfg)
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now()
+
+	// The barrel lists every route client, so it only changes when clients are added or removed
+	added, generated := 0, 0
+	for _, client := range clients {
+		if _, err := os.Stat(client.Output); os.IsNotExist(err) {
+			added++
+		}
+		if !tg.needsRegeneration(client) {
+			logger.Debug("Skipping unchanged client for route: %s", client.Route.FolderPath)
+			continue
+		}
+
+		clientImport, err := filepath.Rel(filepath.Dir(client.Output), filepath.Join(outputDir, "client"))
+		if err != nil {
+			return fmt.Errorf("failed to resolve client import for %s: %w", client.Output, err)
+		}
+
+		templateData := struct {
+			tsRoute
+			ClientImport string
+			Timestamp    time.Time
+		}{
+			tsRoute:      client,
+			ClientImport: filepath.ToSlash(clientImport),
+			Timestamp:    timestamp,
+		}
+		if err := engine.GenerateFile(template_engine.TEMPLATES.DEV.TS_ROUTE_TS, client.Output, templateData); err != nil {
+			return fmt.Errorf("failed to generate client %s: %w", client.Output, err)
+		}
+		logger.Debug("Generated client for route %s with %d functions", client.Route.FolderPath, len(client.Functions))
+		generated++
+	}
+
+	pruned, err := pruneOrphanedClients(clients, outputDir)
+	if err != nil {
+		return fmt.Errorf("failed to prune orphaned clients: %w", err)
+	}
+
+	indexPath := filepath.Join(outputDir, "index.ts")
+	_, err = os.Stat(indexPath)
+	indexCurrent := err == nil && added == 0 && pruned == 0
+	if indexCurrent && generated == 0 {
+		logger.Debug("TypeScript client is up to date, skipping generation")
+		return nil
+	}
+
+	modules := make([]string, len(clients))
+	for i, client := range clients {
+		modules[i] = client.Module
+	}
+	templateData := struct {
+		Modules   []string
+		Timestamp time.Time
+	}{
+		Modules:   modules,
+		Timestamp: timestamp,
+	}
+	// Route clients import from client.ts, so it's kept in step with them
+	if err := engine.GenerateFile(template_engine.TEMPLATES.DEV.TS_CLIENT_TS, filepath.Join(outputDir, "client.ts"), templateData); err != nil {
+		return fmt.Errorf("failed to generate client.ts: %w", err)
+	}
+	if indexCurrent {
+		logger.Debug("TypeScript client index is up to date, skipping generation")
+		return nil
+	}
+	if err := engine.GenerateFile(template_engine.TEMPLATES.DEV.TS_INDEX_TS, indexPath, templateData); err != nil {
+		return fmt.Errorf("failed to generate index.ts: %w", err)
+	}
+
+	logger.Debug("Generated TypeScript client with %d routes in %s", len(clients), outputDir)
+	return nil
+}
+
+// needsRegeneration reports whether a route's client is missing or its source changed
+// since it was last generated
+func (tg *TypescriptGenerator) needsRegeneration(client tsRoute) bool {
+	if _, err := os.Stat(client.Output); os.IsNotExist(err) {
+		return true
+	}
+
+	// Routes without a source on disk, like the built-in health route, are always written
+	source := client.Route.ParsedFile.Path
+	if _, err := os.Stat(source); err != nil {
+		return true
+	}
+
+	affected, reason, err := planAffects(source)
+	if err != nil {
+		logger.Debug("Failed to get regeneration plan for %s: %v, assuming regeneration needed", source, err)
+		return true
+	}
+	if affected {
+		logger.Debug("Client regeneration needed for route: %s - %s", client.Route.FolderPath, reason)
+	}
+	return affected
+}
+
+// tsRoutes maps each route with methods to its client, sorted by folder path. Routes whose
+// function names collide are rejected since the barrel would export both; interfaces whose
+// names collide are prefixed with their route's name instead, as struct names often repeat.
+func tsRoutes(routes []models.Route, outputDir string) ([]tsRoute, error) {
+	var clients []tsRoute
+	sources := make(map[string]string)
+	structCounts := make(map[string]int)
+	for _, route := range routes {
+		if route.ParsedFile != nil && len(route.Methods) > 0 {
+			for _, decl := range route.ParsedFile.Structs {
+				structCounts[decl.Name]++
+			}
+		}
+	}
+
+	for _, route := range routes {
+		if route.ParsedFile == nil {
+			continue
+		}
+		if len(route.Methods) == 0 {
+			logger.Debug("Skipping client for route %s: it declares no HTTP methods", route.FolderPath)
+			continue
+		}
+
+		var words, params, path []string
+		for _, segment := range route.Segments {
+			if !segment.IsParam && !segment.IsCatchAll {
+				words = append(words, segment.Name)
+				path = append(path, tsTemplateLiteral.Replace(segment.APIName))
+				continue
+			}
+
+			words = append(words, segment.ParamName)
+			key, access := segment.ParamName, "params."+segment.ParamName
+			if !tsIdentifierPattern.MatchString(key) {
+				key = strconv.Quote(key)
+				access = "params[" + key + "]"
+			}
+			params = append(params, key+": string")
+			// A catch-all spans several segments, so its slashes are kept
+			encode := "encodeURIComponent"
+			if segment.IsCatchAll {
+				encode = "encodeURI"
+			}
+			path = append(path, "${"+encode+"("+access+")}")
+		}
+
+		name := strings.Join(words, "/")
+		folder := filepath.ToSlash(route.FolderPath)
+		client := tsRoute{
+			Route:  route,
+			URL:    "/" + strings.Join(path, "/"),
+			Output: filepath.Join(outputDir, "routes", filepath.FromSlash(folder), generatedClientFileName),
+			Module: "routes/" + folder + "/" + strings.TrimSuffix(generatedClientFileName, ".ts"),
+		}
+		if len(params) > 0 {
+			client.Params = "{ " + strings.Join(params, "; ") + " }"
+		}
+
+		interfaceNames := make(map[string]string, len(route.ParsedFile.Structs))
+		for _, decl := range route.ParsedFile.Structs {
+			interfaceNames[decl.Name] = decl.Name
+			if structCounts[decl.Name] > 1 {
+				interfaceNames[decl.Name] = shared.ToPascalCase(name) + decl.Name
+			}
+		}
+		types := &tsTypeMapper{route: route.FolderPath, names: interfaceNames}
+		for _, decl := range route.ParsedFile.Structs {
+			client.Interfaces = append(client.Interfaces, tsInterface{
+				Name:   interfaceNames[decl.Name],
+				Fields: types.fields(decl.Name, decl.Fields),
+			})
+		}
+
+		responseTypes := make(map[string]string)
+		descriptions := make(map[string]string)
+		for _, fn := range route.ParsedFile.Functions {
+			responseTypes[fn.Method] = types.named(fn.ResponseType)
+			descriptions[fn.Method] = fn.Description
+		}
+
+		for _, method := range route.Methods {
+			function := tsFunction{
+				Name:         shared.ToCamelCase(method + "/" + name),
+				Method:       method,
+				ResponseType: responseTypes[method],
+			}
+			if description := descriptions[method]; description != "" {
+				// A "*/" in the description would end the doc comment early
+				function.Doc = strings.Split(strings.ReplaceAll(description, "*/", "*\\/"), "\n")
+			}
+
+			source := method + " /" + route.APIPath
+			if existing, exists := sources[function.Name]; exists {
+				return nil, fmt.Errorf("%s and %s both map to client function %s", existing, source, function.Name)
+			}
+			sources[function.Name] = source
+			client.Functions = append(client.Functions, function)
+		}
+		clients = append(clients, client)
+	}
+
+	sort.Slice(clients, func(i, j int) bool { return clients[i].Route.FolderPath < clients[j].Route.FolderPath })
+	return clients, nil
+}
+
+// tsTypeMapper converts the struct field types of a route file to TypeScript
+type tsTypeMapper struct {
+	route string
+	// names maps the structs of the file to their interface names
+	names map[string]string
+}
+
+// fields returns the interface fields for the fields of a struct, owner naming it in warnings
+func (m *tsTypeMapper) fields(owner string, fields []models.StructField) []tsField {
+	tsFields := make([]tsField, 0, len(fields))
+	for _, field := range fields {
+		key := field.JSONName
+		if !tsIdentifierPattern.MatchString(key) {
+			key = strconv.Quote(key)
+		}
+		tsFields = append(tsFields, tsField{
+			Key:      key,
+			Type:     m.typeOf(owner+"."+field.Name, field.Type),
+			Optional: field.OmitEmpty,
+		})
+	}
+	return tsFields
+}
+
+// typeOf returns the TypeScript type of a field, falling back to unknown with a warning for
+// types JSON can't encode or that aren't declared in the route file
+func (m *tsTypeMapper) typeOf(field string, fieldType models.FieldType) string {
+	switch fieldType.Kind {
+	case models.FieldString:
+		return "string"
+	case models.FieldNumber:
+		return "number"
+	case models.FieldBool:
+		return "boolean"
+	case models.FieldAny:
+		return "unknown"
+	case models.FieldStruct:
+		if name, ok := m.names[fieldType.Name]; ok {
+			return name
+		}
+	case models.FieldObject:
+		var members []string
+		for _, member := range m.fields(field, fieldType.Fields) {
+			optional := ""
+			if member.Optional {
+				optional = "?"
+			}
+			members = append(members, member.Key+optional+": "+member.Type)
+		}
+		if len(members) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(members, "; ") + " }"
+	case models.FieldSlice:
+		elem := m.typeOf(field, *fieldType.Elem)
+		if strings.Contains(elem, " | ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case models.FieldMap:
+		return "Record<string, " + m.typeOf(field, *fieldType.Elem) + ">"
+	case models.FieldPointer:
+		return m.typeOf(field, *fieldType.Elem) + " | null"
+	}
+
+	logger.Warn("%s: %s has type %s, which has no TypeScript equivalent; using unknown", m.route, field, fieldType.GoType)
+	return "unknown"
+}
+
+// named returns the TypeScript type of a handler's response type, such as "User" or
+// "[]User", when it's a struct of the route file, and "" otherwise
+func (m *tsTypeMapper) named(goType string) string {
+	suffix := ""
+	for strings.HasPrefix(goType, "[]") {
+		goType = goType[2:]
+		suffix += "[]"
+	}
+	if name, ok := m.names[goType]; ok {
+		return name + suffix
+	}
+	return ""
+}
+
+// pruneOrphanedClients removes the generated route clients findOrphanedClients reports,
+// returning the number removed
+func pruneOrphanedClients(clients []tsRoute, outputDir string) (int, error) {
+	orphans, err := findOrphanedClients(clients, outputDir)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, orphan := range orphans {
+		logger.Info("Removing orphaned generated client %s", orphan)
+	}
+	return len(orphans), RemoveStaleOutputs(orphans, []string{outputDir})
+}
+
+// outputDir returns the configured client output directory resolved against the project root
+func (tg *TypescriptGenerator) outputDir(cfg *config.Config) string {
+	if filepath.IsAbs(cfg.Codegen.Typescript.Output) {
+		return cfg.Codegen.Typescript.Output
+	}
+	return filepath.Join(tg.wd, cfg.Codegen.Typescript.Output)
+}
+
+// findOrphanedClients returns the generated route clients under outputDir that no longer
+// correspond to a route with methods
+func findOrphanedClients(clients []tsRoute, outputDir string) ([]string, error) {
+	expected := make(map[string]bool, len(clients))
+	for _, client := range clients {
+		expected[filepath.Clean(client.Output)] = true
+	}
+
+	routesDir := filepath.Join(outputDir, "routes")
+	if _, err := os.Stat(routesDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var orphans []string
+	err := filepath.WalkDir(routesDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == generatedClientFileName && !expected[filepath.Clean(path)] {
+			orphans = append(orphans, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orphans, nil
+}
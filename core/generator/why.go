@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tristendillon/conduit/core/cache"
+	cacheModels "github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/dependency"
+	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/models"
+)
+
+// DependencyHop is one edge of a dependency chain: From imports To through Import
+type DependencyHop struct {
+	From   string                 `json:"from"`
+	To     string                 `json:"to"`
+	Import models.LocalDependency `json:"import"`
+}
+
+// WhyDepends returns the shortest chain of imports through which from depends on to, or
+// an empty slice when it doesn't. Each may be a route folder, a file or a package
+// directory relative to the project root, or an import path. The route tree is walked
+// and the dependency graph extended with the imports of every local package the routes
+// reach, as copying them would. Nothing is written.
+func (rg *RouteGenerator) WhyDepends(from, to string) ([]DependencyHop, error) {
+	tree, err := rg.WalkRouteTree()
+	if err != nil {
+		return nil, err
+	}
+	moduleName := rg.getModuleName()
+	graph := cache.GetCacheManager().GetDependencyGraph()
+
+	// The import creating each edge, by dependent and then import path
+	imports := make(map[string]map[string]models.LocalDependency)
+	record := func(dependent string, dep models.LocalDependency) {
+		if imports[dependent] == nil {
+			imports[dependent] = make(map[string]models.LocalDependency)
+		}
+		imports[dependent][dep.ImportPath] = dep
+	}
+
+	var pending []models.LocalDependency
+	for _, route := range tree.Routes {
+		if route.ParsedFile == nil || route.ParsedFile.Dependencies == nil {
+			continue
+		}
+		key := cacheModels.CanonicalPath(route.ParsedFile.Path)
+		for _, dep := range route.ParsedFile.Dependencies.LocalImports {
+			record(key, dep)
+			pending = append(pending, dep)
+		}
+	}
+
+	// The graph only holds the imports of route files, so add the packages they lead to
+	expanded := make(map[string]bool)
+	for len(pending) > 0 {
+		pkg := pending[0]
+		pending = pending[1:]
+		if expanded[pkg.ImportPath] {
+			continue
+		}
+		expanded[pkg.ImportPath] = true
+
+		deps, err := dependency.AnalyzePackageImports(filepath.Join(rg.wd, pkg.RelativePath), moduleName)
+		if err != nil {
+			logger.Debug("Failed to analyze imports of %s: %v", pkg.ImportPath, err)
+			continue
+		}
+		importPaths := make([]string, 0, len(deps))
+		for _, dep := range deps {
+			record(pkg.ImportPath, dep)
+			importPaths = append(importPaths, dep.ImportPath)
+			pending = append(pending, dep)
+		}
+		if err := graph.UpdateNode(pkg.ImportPath, importPaths); err != nil {
+			return nil, fmt.Errorf("failed to add %s to the dependency graph: %w", pkg.ImportPath, err)
+		}
+	}
+
+	path, err := graph.FindPath(rg.graphNode(from, moduleName), rg.graphNode(to, moduleName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dependency path: %w", err)
+	}
+
+	hops := []DependencyHop{}
+	for i := 1; i < len(path); i++ {
+		hops = append(hops, DependencyHop{
+			From:   rg.relPath(filepath.FromSlash(path[i-1])),
+			To:     rg.relPath(filepath.FromSlash(path[i])),
+			Import: imports[path[i-1]][path[i]],
+		})
+	}
+	return hops, nil
+}
+
+// graphNode returns the dependency graph key for a route folder, file or package directory
+// (absolute or relative to the project root), or arg itself taken as an import path
+func (rg *RouteGenerator) graphNode(arg, moduleName string) string {
+	path := arg
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(rg.wd, path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return arg
+	}
+	if !info.IsDir() {
+		return cacheModels.CanonicalPath(path)
+	}
+	if _, err := os.Stat(filepath.Join(path, "route.go")); err == nil {
+		return cacheModels.CanonicalPath(filepath.Join(path, "route.go"))
+	}
+	rel, err := filepath.Rel(rg.wd, path)
+	if err != nil {
+		return arg
+	}
+	return moduleName + "/" + filepath.ToSlash(rel)
+}
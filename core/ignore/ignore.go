@@ -0,0 +1,155 @@
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FileName is the ignore file conduit reads from the project root
+const FileName = ".conduitignore"
+
+type pattern struct {
+	source  string
+	regex   *regexp.Regexp
+	dirOnly bool
+}
+
+// Matcher matches project-relative paths against gitignore-style glob patterns.
+// A nil or empty Matcher matches nothing.
+type Matcher struct {
+	patterns []pattern
+}
+
+// Load reads the .conduitignore in root. A missing file yields an empty matcher.
+func Load(root string) (*Matcher, error) {
+	file, err := os.Open(filepath.Join(root, FileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Matcher{}, nil
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", FileName, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+
+	return New(lines)
+}
+
+// New compiles ignore patterns. Blank lines and lines starting with # are skipped.
+// Patterns support *, ?, ** and a leading slash to anchor them to the project root;
+// patterns without a slash match at any depth and a trailing slash matches only directories.
+func New(lines []string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p, err := compile(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", line, err)
+		}
+		m.patterns = append(m.patterns, p)
+	}
+	return m, nil
+}
+
+func compile(line string) (pattern, error) {
+	p := pattern{source: line}
+	glob := line
+
+	if strings.HasSuffix(glob, "/") {
+		p.dirOnly = true
+		glob = strings.TrimSuffix(glob, "/")
+	}
+
+	// A slash anywhere but the end anchors the pattern to the root, as in gitignore
+	anchored := strings.Contains(glob, "/")
+	glob = strings.TrimPrefix(glob, "/")
+
+	var re strings.Builder
+	re.WriteString("^")
+	if !anchored {
+		re.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			re.WriteString("(?:/.*)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			re.WriteString("[^/]*")
+		case glob[i] == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(glob[i])))
+		}
+	}
+	re.WriteString("$")
+
+	regex, err := regexp.Compile(re.String())
+	if err != nil {
+		return p, err
+	}
+	p.regex = regex
+	return p, nil
+}
+
+// Match reports whether relPath (relative to the project root) is ignored, either
+// directly or because one of its parent directories is
+func (m *Matcher) Match(relPath string, isDir bool) bool {
+	if m == nil || len(m.patterns) == 0 {
+		return false
+	}
+
+	relPath = filepath.ToSlash(filepath.Clean(relPath))
+	if relPath == "." || relPath == "" {
+		return false
+	}
+
+	parts := strings.Split(relPath, "/")
+	for i := 1; i <= len(parts); i++ {
+		candidate := strings.Join(parts[:i], "/")
+		candidateIsDir := i < len(parts) || isDir
+		for _, p := range m.patterns {
+			if p.dirOnly && !candidateIsDir {
+				continue
+			}
+			if p.regex.MatchString(candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Patterns returns the source of every compiled pattern
+func (m *Matcher) Patterns() []string {
+	if m == nil {
+		return nil
+	}
+	sources := make([]string, len(m.patterns))
+	for i, p := range m.patterns {
+		sources[i] = p.source
+	}
+	return sources
+}
@@ -1,10 +1,13 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 )
@@ -48,6 +51,14 @@ func (l LogLevel) String() string {
 	}
 }
 
+// Format selects how log lines are rendered
+type Format int
+
+const (
+	TEXT Format = iota // colored human-readable lines (plain when NO_COLOR is set)
+	JSON               // one {"timestamp","level","message"} object per line
+)
+
 type MultiWriter struct {
 	writers []io.Writer
 }
@@ -69,11 +80,53 @@ func (mw *MultiWriter) Add(writer io.Writer) {
 	mw.writers = append(mw.writers, writer)
 }
 
+// ansiPattern matches the color escape sequences the text formatter emits
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// PlainWriter strips color escape codes before writing, for log files
+type PlainWriter struct {
+	writer io.Writer
+}
+
+func NewPlainWriter(writer io.Writer) *PlainWriter {
+	return &PlainWriter{writer: writer}
+}
+
+func (pw *PlainWriter) Write(p []byte) (n int, err error) {
+	if _, err := pw.writer.Write(ansiPattern.ReplaceAll(p, nil)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ParseLevel maps a level name such as "warn" (case-insensitive) to its LogLevel
+func ParseLevel(name string) (LogLevel, error) {
+	for level := DEBUG; level <= FATAL; level++ {
+		if strings.EqualFold(name, level.String()) {
+			return level, nil
+		}
+	}
+	if strings.EqualFold(name, "warning") {
+		return WARN, nil
+	}
+	return INFO, fmt.Errorf("unknown log level %q (expected debug, info, warn, error or fatal)", name)
+}
+
 type ColoredLogger struct {
-	verbose bool
-	mu      sync.RWMutex
-	writers map[LogLevel]io.Writer
-	loggers map[LogLevel]*log.Logger
+	verbose  bool
+	level    LogLevel
+	jsonMode bool
+	noColor  bool
+	mu       sync.RWMutex
+	writers  map[LogLevel]io.Writer
+	loggers  map[LogLevel]*log.Logger
+}
+
+// jsonLogLine is the shape of a log line in JSON mode
+type jsonLogLine struct {
+	Timestamp string `json:"timestamp"`
+	Level     string `json:"level"`
+	Message   string `json:"message"`
 }
 
 var globalLogger *ColoredLogger
@@ -81,6 +134,8 @@ var globalLogger *ColoredLogger
 func init() {
 	globalLogger = &ColoredLogger{
 		verbose: false,
+		level:   INFO,
+		noColor: os.Getenv("NO_COLOR") != "", // https://no-color.org
 		writers: make(map[LogLevel]io.Writer),
 		loggers: make(map[LogLevel]*log.Logger),
 	}
@@ -97,10 +152,33 @@ func SetVerbose(verbose bool) {
 	globalLogger.verbose = verbose
 }
 
+// SetFormat selects the formatter every log level is rendered with
+func SetFormat(format Format) {
+	globalLogger.mu.Lock()
+	defer globalLogger.mu.Unlock()
+	globalLogger.jsonMode = format == JSON
+}
+
+// SetJSONMode switches log output to one JSON object per line, for log aggregators
+func SetJSONMode(enabled bool) {
+	if enabled {
+		SetFormat(JSON)
+	} else {
+		SetFormat(TEXT)
+	}
+}
+
+// SetLevel drops messages below level. Verbose mode still lowers the threshold to DEBUG.
+func SetLevel(level LogLevel) {
+	globalLogger.mu.Lock()
+	defer globalLogger.mu.Unlock()
+	globalLogger.level = level
+}
+
 func IsVerbose() bool {
 	globalLogger.mu.RLock()
 	defer globalLogger.mu.RUnlock()
-	return globalLogger.verbose
+	return globalLogger.verbose || globalLogger.level == DEBUG
 }
 
 func SetWriter(level LogLevel, writer io.Writer) {
@@ -163,8 +241,27 @@ func (cl *ColoredLogger) getColor(level LogLevel) string {
 }
 
 func (cl *ColoredLogger) formatMessage(level LogLevel, message string) string {
+	cl.mu.RLock()
+	jsonMode, noColor := cl.jsonMode, cl.noColor
+	cl.mu.RUnlock()
+
+	if jsonMode {
+		line, err := json.Marshal(jsonLogLine{
+			Timestamp: time.Now().Format(time.RFC3339Nano),
+			Level:     level.String(),
+			Message:   message,
+		})
+		if err == nil {
+			return string(line)
+		}
+	}
+
 	timestamp := time.Now().Format("06-01-02 15:04:05")
 
+	if noColor {
+		return fmt.Sprintf("[%s] %-5s %s", timestamp, level.String(), message)
+	}
+
 	tsColor := ColorGray
 	bracketColor := ColorGray
 	levelColor := cl.getColor(level)
@@ -180,14 +277,21 @@ func (cl *ColoredLogger) formatMessage(level LogLevel, message string) string {
 
 func (cl *ColoredLogger) log(level LogLevel, format string, args ...interface{}) {
 	cl.mu.RLock()
-	if level == DEBUG && !cl.verbose {
-		cl.mu.RUnlock()
-		return
+	threshold := cl.level
+	if cl.verbose {
+		threshold = DEBUG
 	}
-
 	logger := cl.loggers[level]
 	cl.mu.RUnlock()
 
+	// Warnings are collected for --strict even when they aren't shown
+	if level == WARN {
+		warnings.record(fmt.Sprintf(format, args...))
+	}
+	if level < threshold {
+		return
+	}
+
 	message := fmt.Sprintf(format, args...)
 	formattedMessage := cl.formatMessage(level, message)
 
@@ -223,3 +327,13 @@ func GetLogFromLevel(level LogLevel) func(format string, args ...interface{}) {
 		globalLogger.log(level, format, args...)
 	}
 }
+
+// Colorize wraps text in color for terminal output, unless colors are disabled with NO_COLOR
+func Colorize(color, text string) string {
+	globalLogger.mu.RLock()
+	defer globalLogger.mu.RUnlock()
+	if globalLogger.noColor {
+		return text
+	}
+	return color + text + ColorReset
+}
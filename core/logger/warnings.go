@@ -0,0 +1,36 @@
+package logger
+
+import "sync"
+
+// warningCollector records every warning logged while collection is on, so a caller
+// (strict mode) can act on them after the fact
+type warningCollector struct {
+	mu         sync.Mutex
+	collecting bool
+	messages   []string
+}
+
+var warnings warningCollector
+
+// CollectWarnings starts recording warnings, discarding any recorded earlier
+func CollectWarnings() {
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+	warnings.collecting = true
+	warnings.messages = nil
+}
+
+// Warnings returns the warnings recorded since CollectWarnings was called
+func Warnings() []string {
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+	return append([]string(nil), warnings.messages...)
+}
+
+func (wc *warningCollector) record(message string) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	if wc.collecting {
+		wc.messages = append(wc.messages, message)
+	}
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/tristendillon/conduit/core/cache"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+// debugShutdownTimeout bounds how long Stop waits for in-flight debug requests
+const debugShutdownTimeout = 5 * time.Second
+
+// DebugServer serves cache.DebugHandler for the global cache manager
+type DebugServer struct {
+	server *http.Server
+}
+
+// StartDebugServer serves the cache debug endpoints at addr in the background, resolving
+// relative file paths against root. It returns once addr is bound, so an address in use
+// is reported here.
+func StartDebugServer(addr, root string) (*DebugServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for debug requests on %s: %w", addr, err)
+	}
+
+	server := &http.Server{Handler: cache.DebugHandler(cache.GetCacheManager(), root)}
+	go func() {
+		logger.Info("Serving cache debug endpoints on %s/cache/stats and %s/cache/deps", addr, addr)
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Cache debug server stopped: %v", err)
+		}
+	}()
+	return &DebugServer{server: server}, nil
+}
+
+// Stop shuts the server down, waiting briefly for in-flight requests to finish
+func (s *DebugServer) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), debugShutdownTimeout)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,51 @@
+//go:build prometheus
+
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/tristendillon/conduit/core/cache"
+)
+
+// cacheCollector reports the cache stats as the families in cache.PrometheusMetrics,
+// reading them at scrape time
+type cacheCollector struct {
+	descs []*prometheus.Desc
+}
+
+func newCacheCollector() *cacheCollector {
+	descs := make([]*prometheus.Desc, len(cache.PrometheusMetrics))
+	for i, metric := range cache.PrometheusMetrics {
+		descs[i] = prometheus.NewDesc(metric.Name, metric.Help, []string{"layer"}, nil)
+	}
+	return &cacheCollector{descs: descs}
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := cache.GetCacheManager().GetStats()
+	for i, metric := range cache.PrometheusMetrics {
+		valueType := prometheus.GaugeValue
+		if metric.Kind == "counter" {
+			valueType = prometheus.CounterValue
+		}
+		for layer, layerStats := range stats {
+			ch <- prometheus.MustNewConstMetric(c.descs[i], valueType, metric.Value(layerStats), layer)
+		}
+	}
+}
+
+// handler serves the cache collector through client_golang
+func handler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(newCacheCollector())
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+// StartMetricsServer serves the cache metrics for Prometheus at addr/metrics in the
+// background. It returns once addr is bound, so an address in use is reported here.
+func StartMetricsServer(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for metrics on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler())
+
+	go func() {
+		logger.Info("Serving cache metrics on %s/metrics", addr)
+		if err := http.Serve(listener, mux); err != nil {
+			logger.Error("Cache metrics server stopped: %v", err)
+		}
+	}()
+	return nil
+}
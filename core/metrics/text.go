@@ -0,0 +1,15 @@
+//go:build !prometheus
+
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/tristendillon/conduit/core/cache"
+)
+
+// handler renders the cache stats in the Prometheus text format without the client
+// library; build with -tags prometheus to serve them through client_golang instead
+func handler() http.Handler {
+	return cache.PrometheusHandler()
+}
@@ -4,12 +4,21 @@ type LocalDependency struct {
 	ImportPath    string // Full import path: "my-app/api/v1/users/user_repo"
 	RelativePath  string // Relative path: "api/v1/users/user_repo"
 	Alias         string // Import alias if any
+	Line          int    // Line of the import declaration, zero when unknown
 }
 
 type DependencyAnalysis struct {
 	StandardLibImports []string
 	ExternalImports    []string
 	LocalImports       []LocalDependency
+	// ExternalDecls holds the alias and line of each external import, by import path
+	ExternalDecls      map[string]ImportDecl
+}
+
+// ImportDecl is where and how a file declares an import
+type ImportDecl struct {
+	Alias string // Import alias if any
+	Line  int    // Line of the import declaration
 }
 
 type CopiedDependency struct {
@@ -18,4 +27,5 @@ type CopiedDependency struct {
 	ImportPath     string // New import path for generated code
 	Files          []string // List of copied files
 	Dependencies   []LocalDependency // Transitive dependencies
+	Provided       bool // Referenced at its original import path instead of being copied
 }
\ No newline at end of file
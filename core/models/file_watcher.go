@@ -2,11 +2,13 @@ package models
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/ignore"
 	"github.com/tristendillon/conduit/core/logger"
 )
 
@@ -15,10 +17,20 @@ type FileWatcher struct {
 	RootDir       string
 	ExcludePaths  []string
 	DebounceTimer *time.Timer
-	Mutex         sync.Mutex
-	OnStart       func() error
-	OnChange      func() error
-	OnClose       func() error
+	// DebounceInterval is how long changes must settle before OnChange runs
+	DebounceInterval time.Duration
+	// Mode selects how changes are detected: "fsnotify" or "poll"
+	Mode string
+	// PollInterval is how often the project is rescanned in poll mode
+	PollInterval time.Duration
+	// Ignore holds the patterns from the project's .conduitignore
+	Ignore   *ignore.Matcher
+	Mutex    sync.Mutex
+	OnStart  func() error
+	OnChange func() error
+	OnClose  func() error
+	// OnConfigChange runs with the reloaded config when a conduit config file changes
+	OnConfigChange func(*config.Config) error
 }
 
 func NewFileWatcher(rootDir string, excludePaths []string) (*FileWatcher, error) {
@@ -28,21 +40,52 @@ func NewFileWatcher(rootDir string, excludePaths []string) (*FileWatcher, error)
 	}
 
 	fw := &FileWatcher{
-		Watcher:      watcher,
-		RootDir:      rootDir,
-		OnStart:      func() error { return fmt.Errorf("OnStart not set") },
-		OnChange:     func() error { return fmt.Errorf("OnChange not set") },
-		OnClose:      func() error { return fmt.Errorf("OnClose not set") },
-		ExcludePaths: excludePaths,
+		Watcher:  watcher,
+		RootDir:  rootDir,
+		OnStart:  func() error { return fmt.Errorf("OnStart not set") },
+		OnChange: func() error { return fmt.Errorf("OnChange not set") },
+		OnClose:  func() error { return fmt.Errorf("OnClose not set") },
+		// Reacting to config changes is optional
+		OnConfigChange: func(*config.Config) error { return nil },
+		ExcludePaths:   excludePaths,
 	}
 
-	if err := fw.loadExcludePaths(); err != nil {
-		logger.Debug("Failed to load exclude paths from config: %v", err)
+	fw.LoadIgnore()
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Debug("Failed to load watcher settings from config: %v", err)
+		return fw, nil
+	}
+
+	if cfg.Watcher.Mode != "" && cfg.Watcher.Mode != config.WatcherModeFSNotify && cfg.Watcher.Mode != config.WatcherModePoll {
+		watcher.Close()
+		return nil, fmt.Errorf("invalid watcher.mode %q: must be %q or %q", cfg.Watcher.Mode, config.WatcherModeFSNotify, config.WatcherModePoll)
 	}
+	fw.Mode = cfg.Watcher.Mode
+	fw.ApplyConfig(cfg)
 
 	return fw, nil
 }
 
+// ApplyConfig updates the settings that can change while the watcher runs: excluded
+// output directories, the debounce interval and the poll interval. The mode is fixed
+// once the watcher has started.
+func (fw *FileWatcher) ApplyConfig(cfg *config.Config) {
+	fw.loadExcludePaths(cfg)
+	if cfg.Server.DebounceMs > 0 {
+		fw.DebounceInterval = time.Duration(cfg.Server.DebounceMs) * time.Millisecond
+	}
+	logger.Debug("Debouncing changes for %v", fw.DebounceInterval)
+
+	if cfg.Watcher.PollIntervalMs > 0 {
+		fw.PollInterval = time.Duration(cfg.Watcher.PollIntervalMs) * time.Millisecond
+	}
+	if cfg.Watcher.Mode != "" && fw.Mode != "" && cfg.Watcher.Mode != fw.Mode {
+		logger.Warn("watcher.mode changed to %q; restart conduit dev for it to take effect", cfg.Watcher.Mode)
+	}
+}
+
 func (fw *FileWatcher) AddOnStartFunc(onStart func() error) {
 	fw.OnStart = onStart
 }
@@ -55,21 +98,27 @@ func (fw *FileWatcher) AddOnCloseFunc(onClose func() error) {
 	fw.OnClose = onClose
 }
 
-func (fw *FileWatcher) loadExcludePaths() error {
-	cfg, err := config.Load()
+func (fw *FileWatcher) AddOnConfigChangeFunc(onConfigChange func(*config.Config) error) {
+	fw.OnConfigChange = onConfigChange
+}
+
+// LoadIgnore (re)reads the .conduitignore in the root directory
+func (fw *FileWatcher) LoadIgnore() {
+	matcher, err := ignore.Load(fw.RootDir)
 	if err != nil {
-		return err
+		logger.Warn("Ignoring %s: %v", ignore.FileName, err)
+		matcher = &ignore.Matcher{}
 	}
+	fw.Ignore = matcher
+	logger.Debug("Ignore patterns: %v", matcher.Patterns())
+}
 
-	fw.ExcludePaths = append(fw.ExcludePaths, []string{".git"}...)
-
-	if cfg.Codegen.Go.Output != "" {
-		fw.ExcludePaths = append(fw.ExcludePaths, cfg.Codegen.Go.Output)
-	}
-	if cfg.Codegen.Typescript.Output != "" {
-		fw.ExcludePaths = append(fw.ExcludePaths, cfg.Codegen.Typescript.Output)
+func (fw *FileWatcher) loadExcludePaths(cfg *config.Config) {
+	for _, path := range slices.Concat([]string{".git", cfg.Codegen.Typescript.Output}, cfg.GoOutputDirs()) {
+		if path != "" && !slices.Contains(fw.ExcludePaths, path) {
+			fw.ExcludePaths = append(fw.ExcludePaths, path)
+		}
 	}
 
 	logger.Debug("Excluding paths: %v", fw.ExcludePaths)
-	return nil
 }
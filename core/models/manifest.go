@@ -0,0 +1,99 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ManifestVersion is the manifest format understood by this version of conduit
+const ManifestVersion = 1
+
+// Manifest describes the routes of a project without requiring its source tree.
+// It carries everything the Go registry needs to reference the generated route packages.
+type Manifest struct {
+	Version int             `json:"version"`
+	Module  string          `json:"module"`
+	Routes  []ManifestRoute `json:"routes"`
+}
+
+type ManifestRoute struct {
+	APIPath    string `json:"api_path"`
+	Pattern    string `json:"pattern"`
+	FolderPath string `json:"folder_path"`
+	// ImportPath is the import path of the generated route package that declares SetupRoutes
+	ImportPath   string `json:"import_path"`
+	PackageAlias string `json:"package_alias,omitempty"`
+	// Handlers maps each HTTP method to the handler function that serves it
+	Handlers   map[string]string `json:"handlers"`
+	Parameters []string          `json:"parameters,omitempty"`
+	// Description is the package doc comment of the route file
+	Description string `json:"description,omitempty"`
+}
+
+// LoadManifest reads and validates a manifest from disk
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", path, err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	if manifest.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d (expected %d)", manifest.Version, ManifestVersion)
+	}
+
+	return &manifest, nil
+}
+
+// ToRoutes converts the manifest entries into routes suitable for registry generation
+func (m *Manifest) ToRoutes() ([]Route, error) {
+	rt := &RouteTree{}
+	routes := make([]Route, 0, len(m.Routes))
+
+	for i, entry := range m.Routes {
+		if entry.ImportPath == "" {
+			return nil, fmt.Errorf("manifest route %d (%s) has no import_path", i, entry.APIPath)
+		}
+		if len(entry.Handlers) == 0 {
+			return nil, fmt.Errorf("manifest route %d (%s) has no handlers", i, entry.APIPath)
+		}
+
+		folderPath := strings.Trim(entry.FolderPath, "/")
+		pattern := strings.TrimPrefix(entry.Pattern, "/")
+		if pattern == "" {
+			pattern = folderPath
+		}
+
+		alias := entry.PackageAlias
+		if alias == "" {
+			alias = rt.generatePackageAlias(folderPath)
+		}
+
+		methods := make([]string, 0, len(entry.Handlers))
+		for method := range entry.Handlers {
+			methods = append(methods, strings.ToUpper(method))
+		}
+		sort.Strings(methods)
+
+		routes = append(routes, Route{
+			APIPath:      entry.APIPath,
+			Pattern:      pattern,
+			FolderPath:   folderPath,
+			Parameters:   entry.Parameters,
+			IsLeaf:       true,
+			Methods:      methods,
+			ImportPath:   entry.ImportPath,
+			PackageAlias: alias,
+			Description:  entry.Description,
+		})
+	}
+
+	return routes, nil
+}
@@ -1,18 +1,65 @@
 package models
 
 type ExtractedFunction struct {
-	Name      string
-	Method    string
-	Signature string
-	Body      string
+	Name         string
+	Method       string
+	Signature    string
+	Body         string
+	RequestType  string            // type decoded from the request body as JSON, if inferable
+	ResponseType string            // type encoded to the response as JSON, if inferable
+	DocComment   string            // doc comment above the handler, without comment markers
+	Description  string            // DocComment without its @middleware and @conduit lines
+	Middlewares  []string          // from @middleware annotations in the doc comment, outermost first
+	Annotations  map[string]string // from @conduit key=value lines in the doc comment
 }
 
 type ParsedFile struct {
 	Path         string
 	RelPath      string
 	PackageName  string
+	DocComment   string // package doc comment, without comment markers
 	Methods      []string
 	Functions    []ExtractedFunction
 	Imports      []string
 	Dependencies *DependencyAnalysis
+	Structs      []StructDecl // exported struct types, for clients of the route
+}
+
+// StructDecl is an exported struct type declared in a route file, as encoding/json sees it
+type StructDecl struct {
+	Name   string
+	Fields []StructField
+}
+
+// StructField is a field of a struct as it's encoded to JSON
+type StructField struct {
+	Name      string // Go field name
+	JSONName  string // key the field is encoded under
+	Type      FieldType
+	OmitEmpty bool // left out when empty, from the omitempty or omitzero tag options
+}
+
+// FieldKind is the JSON shape of a Go type
+type FieldKind string
+
+const (
+	FieldString      FieldKind = "string"
+	FieldNumber      FieldKind = "number"
+	FieldBool        FieldKind = "bool"
+	FieldAny         FieldKind = "any"
+	FieldStruct      FieldKind = "struct" // a StructDecl of the same file, by name
+	FieldObject      FieldKind = "object" // an inline struct
+	FieldSlice       FieldKind = "slice"
+	FieldMap         FieldKind = "map" // keys are strings in JSON
+	FieldPointer     FieldKind = "pointer"
+	FieldUnsupported FieldKind = "unsupported" // channels, funcs and types declared elsewhere
+)
+
+// FieldType describes the type of a struct field
+type FieldType struct {
+	Kind   FieldKind
+	Name   string        // struct name, for FieldStruct
+	Elem   *FieldType    // element type, for FieldSlice, FieldMap and FieldPointer
+	Fields []StructField // for FieldObject
+	GoType string        // the Go type expression
 }
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	ConflictDuplicate    = "duplicate"
+	ConflictParamLiteral = "param-literal"
+	ConflictWildcard     = "wildcard-shadow"
+)
+
+type RouteConflict struct {
+	Kind        string
+	Method      string
+	APIPaths    [2]string
+	FolderPaths [2]string
+}
+
+func (rc RouteConflict) String() string {
+	switch rc.Kind {
+	case ConflictParamLiteral:
+		return fmt.Sprintf("%s /%s (%s) overlaps %s /%s (%s): param segment collides with literal segment",
+			rc.Method, rc.APIPaths[0], rc.FolderPaths[0], rc.Method, rc.APIPaths[1], rc.FolderPaths[1])
+	case ConflictWildcard:
+		return fmt.Sprintf("%s /%s (%s) shadows %s /%s (%s): catch-all segment also matches the more specific route",
+			rc.Method, rc.APIPaths[0], rc.FolderPaths[0], rc.Method, rc.APIPaths[1], rc.FolderPaths[1])
+	default:
+		return fmt.Sprintf("%s /%s (%s) and %s /%s (%s) resolve to the same route",
+			rc.Method, rc.APIPaths[0], rc.FolderPaths[0], rc.Method, rc.APIPaths[1], rc.FolderPaths[1])
+	}
+}
+
+// DetectConflicts reports routes from distinct folders that would match the same
+// request for the same HTTP method, including param-vs-literal collisions and catch-all
+// routes that shadow more specific ones under the same parent.
+func (rt *RouteTree) DetectConflicts() ([]RouteConflict, error) {
+	var conflicts []RouteConflict
+
+	routes := make([]Route, len(rt.Routes))
+	copy(routes, rt.Routes)
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].FolderPath < routes[j].FolderPath
+	})
+
+	for i := 0; i < len(routes); i++ {
+		for j := i + 1; j < len(routes); j++ {
+			a, b := routes[i], routes[j]
+			if a.FolderPath == b.FolderPath {
+				continue
+			}
+
+			kind, overlaps := compareSegments(a.Segments, b.Segments)
+			if !overlaps {
+				// The catch-all route is always reported first
+				switch {
+				case shadowsWildcard(a.Segments, b.Segments):
+				case shadowsWildcard(b.Segments, a.Segments):
+					a, b = b, a
+				default:
+					continue
+				}
+				kind = ConflictWildcard
+			}
+
+			for _, method := range sharedMethods(a.Methods, b.Methods) {
+				conflicts = append(conflicts, RouteConflict{
+					Kind:        kind,
+					Method:      method,
+					APIPaths:    [2]string{a.APIPath, b.APIPath},
+					FolderPaths: [2]string{a.FolderPath, b.FolderPath},
+				})
+			}
+		}
+	}
+
+	return conflicts, nil
+}
+
+// compareSegments reports whether two segment lists can match the same request path
+func compareSegments(a, b []RouteSegment) (string, bool) {
+	if len(a) != len(b) {
+		return "", false
+	}
+
+	kind := ConflictDuplicate
+	for i := range a {
+		switch {
+		case a[i].IsCatchAll != b[i].IsCatchAll:
+			// Left to shadowsWildcard
+			return "", false
+		case a[i].IsCatchAll, a[i].IsParam && b[i].IsParam:
+			continue
+		case a[i].IsParam != b[i].IsParam:
+			kind = ConflictParamLiteral
+		case a[i].APIName != b[i].APIName:
+			return "", false
+		}
+	}
+	return kind, true
+}
+
+// shadowsWildcard reports whether the route ending in a catch-all segment also matches
+// every request of the more specific route: one at least as deep below the same parent
+func shadowsWildcard(wildcard, other []RouteSegment) bool {
+	last := len(wildcard) - 1
+	if last < 0 || !wildcard[last].IsCatchAll || len(other) <= last || other[last].IsCatchAll {
+		return false
+	}
+
+	for i := 0; i < last; i++ {
+		literals := !wildcard[i].IsParam && !other[i].IsParam
+		if literals && wildcard[i].APIName != other[i].APIName {
+			return false
+		}
+	}
+	return true
+}
+
+func sharedMethods(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	for _, method := range a {
+		seen[method] = true
+	}
+
+	var shared []string
+	for _, method := range b {
+		if seen[method] {
+			shared = append(shared, method)
+			seen[method] = false
+		}
+	}
+	sort.Strings(shared)
+	return shared
+}
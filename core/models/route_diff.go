@@ -0,0 +1,78 @@
+package models
+
+import (
+	"slices"
+	"sort"
+)
+
+// RouteChange is a route that differs between two route trees
+type RouteChange struct {
+	FolderPath      string   `json:"folder_path"`
+	APIPath         string   `json:"api_path"`
+	Methods         []string `json:"methods"`                    // methods now, empty when removed
+	PreviousMethods []string `json:"previous_methods,omitempty"` // methods before, for modified and removed routes
+}
+
+// RouteDiff lists the routes added, removed or whose method set changed, sorted by folder path
+type RouteDiff struct {
+	Added    []RouteChange `json:"added"`
+	Removed  []RouteChange `json:"removed"`
+	Modified []RouteChange `json:"modified"`
+}
+
+// Empty reports whether the trees had the same routes and methods
+func (d RouteDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// Diff compares the routes of rt against an earlier tree, matching routes by folder path
+func (rt *RouteTree) Diff(other *RouteTree) RouteDiff {
+	diff := RouteDiff{Added: []RouteChange{}, Removed: []RouteChange{}, Modified: []RouteChange{}}
+
+	previous := make(map[string]Route, len(other.Routes))
+	for _, route := range other.Routes {
+		previous[route.FolderPath] = route
+	}
+	current := make(map[string]bool, len(rt.Routes))
+
+	for _, route := range rt.Routes {
+		current[route.FolderPath] = true
+		methods := sortedMethods(route.Methods)
+		before, existed := previous[route.FolderPath]
+		switch {
+		case !existed:
+			diff.Added = append(diff.Added, RouteChange{FolderPath: route.FolderPath, APIPath: route.APIPath, Methods: methods})
+		case !slices.Equal(methods, sortedMethods(before.Methods)):
+			diff.Modified = append(diff.Modified, RouteChange{
+				FolderPath:      route.FolderPath,
+				APIPath:         route.APIPath,
+				Methods:         methods,
+				PreviousMethods: sortedMethods(before.Methods),
+			})
+		}
+	}
+	for _, route := range other.Routes {
+		if !current[route.FolderPath] {
+			diff.Removed = append(diff.Removed, RouteChange{
+				FolderPath:      route.FolderPath,
+				APIPath:         route.APIPath,
+				Methods:         []string{},
+				PreviousMethods: sortedMethods(route.Methods),
+			})
+		}
+	}
+
+	for _, changes := range [][]RouteChange{diff.Added, diff.Removed, diff.Modified} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].FolderPath < changes[j].FolderPath })
+	}
+	return diff
+}
+
+func sortedMethods(methods []string) []string {
+	sorted := slices.Clone(methods)
+	if sorted == nil {
+		sorted = []string{}
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -8,15 +8,20 @@ import (
 
 	"github.com/tristendillon/conduit/core/config"
 	"github.com/tristendillon/conduit/core/logger"
+	"github.com/tristendillon/conduit/core/shared"
 )
 
 type RouteSegment struct {
-	Name      string
-	APIName   string
-	IsParam   bool
-	ParamName string
+	Name       string
+	APIName    string
+	IsParam    bool
+	ParamName  string
+	IsCatchAll bool
 }
 
+// CatchAllParamName is the parameter that receives the remaining path of a catch-all segment
+const CatchAllParamName = "path"
+
 type RouteNode struct {
 	Segment    RouteSegment
 	Children   map[string]*RouteNode
@@ -26,16 +31,22 @@ type RouteNode struct {
 	Depth      int
 	Methods    []string
 	ParsedFile *ParsedFile
+	Middleware *ParsedFile // the folder's _middleware.go, if any
 }
 
 type Route struct {
 	APIPath    string
+	Pattern    string // route pattern for the configured router, e.g. "api/users/{id}"
 	FolderPath string
 	Segments   []RouteSegment
 	Parameters []string
 	IsLeaf     bool
 	Methods    []string
-	ParsedFile *ParsedFile
+	// Description is the package doc comment of the route file
+	Description string
+	ParsedFile  *ParsedFile
+	// Middleware are the _middleware.go files of the route's folder and its parents, outermost first
+	Middleware []*ParsedFile
 
 	OutputPath     string
 	ImportPath     string
@@ -46,8 +57,18 @@ type Route struct {
 type RouteTree struct {
 	Root   *RouteNode
 	Routes []Route
+
+	// MaxDepth is the folder nesting above which routes are reported; DefaultMaxDepth when zero
+	MaxDepth int
+	// DepthViolations lists the folder paths of routes nested deeper than MaxDepth
+	DepthViolations []string
+	// Middleware holds the parsed _middleware.go files by folder path
+	Middleware map[string]*ParsedFile
 }
 
+// DefaultMaxDepth is high enough that only accidental structures exceed it
+const DefaultMaxDepth = 32
+
 func NewRouteTree() *RouteTree {
 	return &RouteTree{
 		Root: &RouteNode{
@@ -74,44 +95,144 @@ func (rt *RouteTree) Reset() {
 		ParsedFile: nil,
 	}
 	rt.Routes = []Route{}
+	rt.DepthViolations = nil
+	rt.Middleware = nil
+}
+
+// AddMiddleware records a folder's parsed _middleware.go. It applies to routes added
+// afterwards in that folder and below, even when the folder has no route.go itself.
+func (rt *RouteTree) AddMiddleware(parsed *ParsedFile) {
+	if rt.Middleware == nil {
+		rt.Middleware = make(map[string]*ParsedFile)
+	}
+	rt.Middleware[normalizeRelPath(parsed.RelPath)] = parsed
 }
 
 func ParseSegment(folderName string) RouteSegment {
 	segment := RouteSegment{Name: folderName}
-	if strings.HasSuffix(folderName, "_") {
+	switch {
+	case isCatchAllFolder(folderName):
+		segment.IsCatchAll = true
+		segment.ParamName = CatchAllParamName
+		if name := catchAllName(folderName); name != "" {
+			segment.ParamName = name
+		}
+		segment.APIName = "*" + segment.ParamName
+	case strings.HasSuffix(folderName, "_"):
 		segment.IsParam = true
 		segment.ParamName = strings.TrimSuffix(folderName, "_")
 		segment.APIName = ":" + segment.ParamName
-	} else {
+	default:
 		segment.APIName = folderName
 	}
 	return segment
 }
 
-func (rt *RouteTree) AddRoute(parsed *ParsedFile) {
-	cleanPath := filepath.Clean(parsed.RelPath)
-	parts := strings.Split(cleanPath, string(filepath.Separator))
+// isCatchAllFolder reports whether a folder is a catch-all segment: "___" and "catchall_"
+// store the remaining path under CatchAllParamName, and a name ending in "__" such as
+// "rest__" stores it under the name before the underscores
+func isCatchAllFolder(folderName string) bool {
+	return folderName == "catchall_" || strings.HasSuffix(folderName, "__")
+}
 
-	var validParts []string
-	for _, part := range parts {
-		if part != "" && part != "." {
-			validParts = append(validParts, part)
+// catchAllName returns the parameter name spelled out by a named catch-all folder, or ""
+// for the unnamed "___", "__" and "catchall_"
+func catchAllName(folderName string) string {
+	if folderName == "catchall_" {
+		return ""
+	}
+	return strings.Trim(folderName, "_")
+}
+
+// IdentifierPath spells out the dynamic folders of a folder path so that identifiers built
+// from it with the shared case helpers don't collide with static folders of the same name,
+// e.g. "api/files/rest__" becomes "api/files/rest/catchAll" and "users/id_" "users/id/param"
+func IdentifierPath(folderPath string) string {
+	parts := strings.Split(filepath.ToSlash(folderPath), "/")
+	for i, part := range parts {
+		switch segment := ParseSegment(part); {
+		case segment.IsCatchAll:
+			parts[i] = strings.TrimPrefix(catchAllName(part)+"/catchAll", "/")
+		case segment.IsParam:
+			parts[i] = segment.ParamName + "/param"
 		}
 	}
-	logger.Debug("Valid parts: %v", validParts)
-	if len(validParts) == 0 {
+	return strings.Join(parts, "/")
+}
+
+// ChiCatchAllParam is the key chi stores the remainder of a wildcard route under
+const ChiCatchAllParam = "*"
+
+// ChiPatternName returns the segment in chi route pattern syntax
+func (s RouteSegment) ChiPatternName() string {
+	switch {
+	case s.IsCatchAll:
+		return ChiCatchAllParam
+	case s.IsParam:
+		return "{" + s.ParamName + "}"
+	default:
+		return s.APIName
+	}
+}
+
+// PatternName returns the segment in net/http ServeMux pattern syntax
+func (s RouteSegment) PatternName() string {
+	switch {
+	case s.IsCatchAll:
+		return "{" + s.ParamName + "...}"
+	case s.IsParam:
+		return "{" + s.ParamName + "}"
+	default:
+		return s.APIName
+	}
+}
+
+// normalizeRelPath cleans a route folder path into slash-separated form without leading,
+// trailing or repeated separators, so equivalent spellings add the same route
+func normalizeRelPath(path string) string {
+	path = filepath.ToSlash(filepath.Clean(path))
+	for strings.Contains(path, "//") {
+		path = strings.ReplaceAll(path, "//", "/")
+	}
+	path = strings.Trim(path, "/")
+	if path == "." {
+		return ""
+	}
+	return path
+}
+
+func (rt *RouteTree) AddRoute(parsed *ParsedFile) {
+	cleanPath := normalizeRelPath(parsed.RelPath)
+	if cleanPath == "" {
 		return
 	}
+	validParts := strings.Split(cleanPath, "/")
+	logger.Debug("Valid parts: %v", validParts)
 
 	current := rt.Root
 	var apiParts []RouteSegment
 	var parameters []string
+	var middleware []*ParsedFile
+
+	// The project root's middleware wraps every route
+	if rootMiddleware, exists := rt.Middleware[""]; exists {
+		current.Middleware = rootMiddleware
+		middleware = append(middleware, rootMiddleware)
+	}
+
+	// A catch-all consumes the rest of the path, so nothing can be routed below it
+	for _, part := range validParts[:len(validParts)-1] {
+		if isCatchAllFolder(part) {
+			logger.Warn("Skipping route %s: catch-all folder %s must be the last folder of a route", cleanPath, part)
+			return
+		}
+	}
 
 	for i, part := range validParts {
 		segment := ParseSegment(part)
 		apiParts = append(apiParts, segment)
 
-		if segment.IsParam {
+		if segment.IsParam || segment.IsCatchAll {
 			parameters = append(parameters, segment.ParamName)
 		}
 
@@ -136,30 +257,66 @@ func (rt *RouteTree) AddRoute(parsed *ParsedFile) {
 			current.Children[part] = newNode
 			current = newNode
 		}
+
+		if folderMiddleware, exists := rt.Middleware[current.FolderPath]; exists {
+			current.Middleware = folderMiddleware
+			middleware = append(middleware, folderMiddleware)
+		}
 	}
 
 	current.ParsedFile = parsed
 	current.Methods = append(current.Methods, parsed.Methods...)
 
+	maxDepth := rt.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if current.Depth > maxDepth {
+		logger.Warn("Route %s is nested %d folders deep, more than codegen.max_depth (%d)", current.FolderPath, current.Depth, maxDepth)
+		rt.DepthViolations = append(rt.DepthViolations, current.FolderPath)
+	}
+
+	patternParts := make([]string, len(apiParts))
+	for i, segment := range apiParts {
+		patternParts[i] = segment.PatternName()
+	}
+
 	route := Route{
-		APIPath:    current.FullPath,
-		FolderPath: parsed.RelPath,
-		Segments:   apiParts,
-		Parameters: parameters,
-		IsLeaf:     len(current.Children) == 0,
-		Methods:    parsed.Methods,
-		ParsedFile: parsed,
+		APIPath:     current.FullPath,
+		Pattern:     strings.Join(patternParts, "/"),
+		FolderPath:  cleanPath,
+		Segments:    apiParts,
+		Parameters:  parameters,
+		IsLeaf:      len(current.Children) == 0,
+		Methods:     parsed.Methods,
+		Description: parsed.DocComment,
+		ParsedFile:  parsed,
+		Middleware:  middleware,
 	}
 
 	rt.Routes = append(rt.Routes, route)
 }
 
 func (rt *RouteTree) CalculateOutputPaths(cfg *config.Config, moduleName string) error {
+	router := cfg.Codegen.Go.Router
+	if router != "" && router != config.RouterStdlib && router != config.RouterChi {
+		return fmt.Errorf("unsupported router %q (expected %q or %q)", router, config.RouterStdlib, config.RouterChi)
+	}
+
 	for i, route := range rt.Routes {
+		if router == config.RouterChi {
+			patternParts := make([]string, len(route.Segments))
+			for j, segment := range route.Segments {
+				patternParts[j] = segment.ChiPatternName()
+			}
+			rt.Routes[i].Pattern = strings.Join(patternParts, "/")
+		}
+
+		output := routeOutputDir(cfg, route.FolderPath)
 		rt.Routes[i].RelativeOutput = filepath.Join("routes", route.FolderPath, "gen_route.go")
-		rt.Routes[i].OutputPath = filepath.Join(cfg.Codegen.Go.Output, rt.Routes[i].RelativeOutput)
+		rt.Routes[i].OutputPath = filepath.Join(output, rt.Routes[i].RelativeOutput)
 
-		cleanOutput := filepath.Clean(cfg.Codegen.Go.Output)
+		cleanOutput := filepath.ToSlash(filepath.Clean(output))
 		if cleanOutput == "." {
 			cleanOutput = ""
 		}
@@ -175,13 +332,55 @@ func (rt *RouteTree) CalculateOutputPaths(cfg *config.Config, moduleName string)
 	return nil
 }
 
+// routeOutputDir returns the output directory for the route in folderPath: the override
+// for the longest route prefix in codegen.go.route_overrides that contains it, or the
+// default output directory
+func routeOutputDir(cfg *config.Config, folderPath string) string {
+	folderPath = filepath.ToSlash(folderPath)
+	output, longest := cfg.Codegen.Go.Output, -1
+	for prefix, dir := range cfg.Codegen.Go.RouteOverrides {
+		prefix = strings.Trim(filepath.ToSlash(prefix), "/")
+		matches := prefix == "" || folderPath == prefix || strings.HasPrefix(folderPath, prefix+"/")
+		if matches && len(prefix) > longest {
+			output, longest = dir, len(prefix)
+		}
+	}
+	return output
+}
+
 func (rt *RouteTree) generatePackageAlias(folderPath string) string {
-	// Convert "api/v1/users" to "api_v1_users_route"
-	// Replace slashes and other problematic characters with underscores
-	alias := strings.ReplaceAll(folderPath, "/", "_")
-	alias = strings.ReplaceAll(alias, "-", "_")
-	alias = strings.ReplaceAll(alias, " ", "_")
-	return alias + "_route"
+	// Convert "api/v1/users" to "api_v1_users_route", "api/files/___" to
+	// "api_files_catch_all_route" and "users/id_" to "users_id_param_route"
+	return shared.ToSnakeCase(IdentifierPath(folderPath)) + "_route"
+}
+
+// SubtreesAtDepth returns the nodes at the given folder depth, sorted by folder path
+func (rt *RouteTree) SubtreesAtDepth(depth int) []*RouteNode {
+	var nodes []*RouteNode
+	var collect func(node *RouteNode)
+	collect = func(node *RouteNode) {
+		if node.Depth == depth {
+			nodes = append(nodes, node)
+			return
+		}
+		for _, child := range node.Children {
+			collect(child)
+		}
+	}
+	collect(rt.Root)
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].FolderPath < nodes[j].FolderPath })
+	return nodes
+}
+
+// GroupName returns an exported identifier for the subtree rooted at the node,
+// e.g. "api/v1" becomes "ApiV1", "api/files/___" becomes "ApiFilesCatchAll" and
+// "api/files/rest__" becomes "ApiFilesRestCatchAll"
+func (n *RouteNode) GroupName() string {
+	if name := shared.ToPascalCase(IdentifierPath(n.FolderPath)); name != "" {
+		return name
+	}
+	return "Group"
 }
 
 func (rt *RouteTree) PrintTree(level logger.LogLevel) {
@@ -193,6 +392,8 @@ func (rt *RouteTree) printNode(node *RouteNode, prefix string, level logger.LogL
 		paramInfo := ""
 		if node.Segment.IsParam {
 			paramInfo = fmt.Sprintf(" (param: %s)", node.Segment.ParamName)
+		} else if node.Segment.IsCatchAll {
+			paramInfo = fmt.Sprintf(" (catch-all: %s)", node.Segment.ParamName)
 		}
 		methodsInfo := ""
 		if len(node.Methods) > 0 {
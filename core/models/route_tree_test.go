@@ -0,0 +1,20 @@
+package models
+
+import "testing"
+
+func TestAddRouteAppliesRootMiddleware(t *testing.T) {
+	rt := NewRouteTree()
+	root := &ParsedFile{RelPath: "."}
+	api := &ParsedFile{RelPath: "api"}
+	rt.AddMiddleware(root)
+	rt.AddMiddleware(api)
+	rt.AddRoute(&ParsedFile{RelPath: "api/users", Methods: []string{"GET"}})
+
+	if len(rt.Routes) != 1 {
+		t.Fatalf("got %d routes, want 1", len(rt.Routes))
+	}
+	middleware := rt.Routes[0].Middleware
+	if len(middleware) != 2 || middleware[0] != root || middleware[1] != api {
+		t.Fatalf("middleware = %v, want the root's then api's", middleware)
+	}
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+// stopTimeout is how long a stopped server gets to shut down before it is killed
+const stopTimeout = 5 * time.Second
+
+// Process runs a generated server with `go run`, forwarding stdin, stdout and stderr.
+// It can be stopped and restarted, e.g. after every regeneration.
+type Process struct {
+	// MainPath is the main.go to run, relative to the working directory
+	MainPath string
+	// Env is added to the environment of the server
+	Env []string
+
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+	done   chan error
+}
+
+func NewProcess(mainPath string, env ...string) *Process {
+	return &Process{MainPath: mainPath, Env: env}
+}
+
+// Start runs the server in the background. Cancelling ctx stops it like Stop does.
+func (p *Process) Start(ctx context.Context) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.done != nil {
+		return fmt.Errorf("server %s is already running", p.MainPath)
+	}
+	if _, err := os.Stat(p.MainPath); err != nil {
+		return fmt.Errorf("no server to run: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cmd := exec.CommandContext(ctx, "go", "run", p.MainPath)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), p.Env...)
+	// go run starts the server as a child of its own, so both are signalled together
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return terminate(cmd) }
+	cmd.WaitDelay = stopTimeout
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return fmt.Errorf("failed to start server %s: %w", p.MainPath, err)
+	}
+	logger.Debug("Started server %s (pid %d)", p.MainPath, cmd.Process.Pid)
+
+	done := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		// Exiting because it was told to stop isn't a failure
+		if ctx.Err() != nil {
+			err = nil
+		}
+		done <- err
+		close(done)
+	}()
+
+	p.cancel = cancel
+	p.done = done
+	return nil
+}
+
+// Wait blocks until the running server exits and returns why it did; a server that was
+// stopped returns nil
+func (p *Process) Wait() error {
+	p.mutex.Lock()
+	done := p.done
+	p.mutex.Unlock()
+
+	if done == nil {
+		return nil
+	}
+	return <-done
+}
+
+// Stop sends the server SIGTERM, killing it if it hasn't exited after a few seconds, and
+// waits for it to exit. Stopping a server that isn't running does nothing.
+func (p *Process) Stop() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.done == nil {
+		return nil
+	}
+	p.cancel()
+	err := <-p.done
+	p.cancel, p.done = nil, nil
+	if err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+		return err
+	}
+	return nil
+}
+
+// Restart stops the server if it's running and starts it again
+func (p *Process) Restart(ctx context.Context) error {
+	if err := p.Stop(); err != nil {
+		logger.Debug("Server %s exited with error: %v", p.MainPath, err)
+	}
+	return p.Start(ctx)
+}
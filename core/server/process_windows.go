@@ -0,0 +1,13 @@
+//go:build windows
+
+package server
+
+import "os/exec"
+
+// setProcessGroup does nothing; Windows has no process groups to signal
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminate kills cmd, since Windows processes can't be sent SIGTERM
+func terminate(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -1,9 +1,95 @@
 package shared
 
-import "strings"
+import (
+	"strings"
+	"unicode"
+)
 
 func ToTitle(s string) string {
 	first := strings.ToUpper(s[:1])
 	rest := s[1:]
 	return first + rest
 }
+
+// ToCamelCase joins the words of s as camelCase, e.g. "api/v1/users" becomes "apiV1Users".
+// A leading digit is prefixed with an underscore so the result is a valid identifier.
+func ToCamelCase(s string) string {
+	words := splitWords(s)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word)
+		} else {
+			words[i] = capitalize(word)
+		}
+	}
+	return identifier(strings.Join(words, ""))
+}
+
+// ToPascalCase joins the words of s as PascalCase, e.g. "api/v1/users" becomes "ApiV1Users".
+// A leading digit is prefixed with an underscore so the result is a valid identifier.
+func ToPascalCase(s string) string {
+	words := splitWords(s)
+	for i, word := range words {
+		words[i] = capitalize(word)
+	}
+	return identifier(strings.Join(words, ""))
+}
+
+// ToSnakeCase joins the words of s as snake_case, e.g. "api/v1/users" becomes "api_v1_users".
+// A leading digit is prefixed with an underscore so the result is a valid identifier.
+func ToSnakeCase(s string) string {
+	return identifier(strings.ToLower(strings.Join(splitWords(s), "_")))
+}
+
+// ToKebabCase joins the words of s as kebab-case, e.g. "api/v1/users" becomes "api-v1-users"
+func ToKebabCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "-"))
+}
+
+// splitWords splits s at every run of characters other than letters and digits and at
+// case changes, keeping acronyms together: "userID", "user_id" and "UserId" all split into
+// "user" and "ID" or "id". Digits stay with the word they follow.
+func splitWords(s string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+		if len(word) > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// "aB" and "1B" start a word, as does the last capital of an acronym before a
+			// lowercase letter, like the S in "HTTPServer"
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
+// capitalize upper-cases the first letter of a word and lower-cases the rest
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// identifier prefixes s with an underscore when it starts with a digit
+func identifier(s string) string {
+	if s != "" && unicode.IsDigit([]rune(s)[0]) {
+		return "_" + s
+	}
+	return s
+}
@@ -0,0 +1,42 @@
+package shared
+
+import "testing"
+
+func TestCaseHelpers(t *testing.T) {
+	tests := []struct {
+		in     string
+		camel  string
+		pascal string
+		snake  string
+		kebab  string
+	}{
+		{"api/v1/users", "apiV1Users", "ApiV1Users", "api_v1_users", "api-v1-users"},
+		{"", "", "", "", ""},
+		{"user__id--name", "userIdName", "UserIdName", "user_id_name", "user-id-name"},
+		{"/leading//and/trailing/", "leadingAndTrailing", "LeadingAndTrailing", "leading_and_trailing", "leading-and-trailing"},
+		{"2fa/setup", "_2faSetup", "_2faSetup", "_2fa_setup", "2fa-setup"},
+		{"v1/2fa", "v12fa", "V12fa", "v1_2fa", "v1-2fa"},
+		{"userID", "userId", "UserId", "user_id", "user-id"},
+		{"HTTPServer", "httpServer", "HttpServer", "http_server", "http-server"},
+		{"apiV1Users", "apiV1Users", "ApiV1Users", "api_v1_users", "api-v1-users"},
+		{"ApiV1Users", "apiV1Users", "ApiV1Users", "api_v1_users", "api-v1-users"},
+		{"api_v1_users", "apiV1Users", "ApiV1Users", "api_v1_users", "api-v1-users"},
+		{"api-v1-users", "apiV1Users", "ApiV1Users", "api_v1_users", "api-v1-users"},
+		{"users/[id]", "usersId", "UsersId", "users_id", "users-id"},
+	}
+
+	for _, tt := range tests {
+		if got := ToCamelCase(tt.in); got != tt.camel {
+			t.Errorf("ToCamelCase(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := ToPascalCase(tt.in); got != tt.pascal {
+			t.Errorf("ToPascalCase(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+		if got := ToSnakeCase(tt.in); got != tt.snake {
+			t.Errorf("ToSnakeCase(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+		if got := ToKebabCase(tt.in); got != tt.kebab {
+			t.Errorf("ToKebabCase(%q) = %q, want %q", tt.in, got, tt.kebab)
+		}
+	}
+}
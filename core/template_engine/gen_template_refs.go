@@ -8,10 +8,18 @@ var TemplateFS embed.FS
 
 type DevTemplates struct {
 	Ref TemplateRef
+	FULL_GEN_ROUTE_GO TemplateRef
 	GEN_ROUTES_GO TemplateRef
 	GEN_ROUTE_GO TemplateRef
-	FULL_GEN_ROUTE_GO TemplateRef
+	METRICS_GO TemplateRef
+	PROTO TemplateRef
 	ROUTES_REGISTRY_GO TemplateRef
+	ROUTES_REGISTRY_GROUP_GO TemplateRef
+	ROUTES_REGISTRY_PART_GO TemplateRef
+	SINGLE_FILE_SERVER_GO TemplateRef
+	TS_CLIENT_TS TemplateRef
+	TS_INDEX_TS TemplateRef
+	TS_ROUTE_TS TemplateRef
 }
 
 type InitApiTemplates struct {
@@ -71,10 +79,18 @@ var TEMPLATES = TemplateRefs{
 	Ref: TemplateRef{Path: "", IsDir: true},
 	DEV: DevTemplates{
 	Ref: TemplateRef{Path: "dev", IsDir: true},
+	FULL_GEN_ROUTE_GO: TemplateRef{Path: "dev/full_gen_route.go.tmpl", IsDir: false},
 	GEN_ROUTES_GO: TemplateRef{Path: "dev/gen_routes.go.tmpl", IsDir: false},
 	GEN_ROUTE_GO: TemplateRef{Path: "dev/gen_route.go.tmpl", IsDir: false},
-	FULL_GEN_ROUTE_GO: TemplateRef{Path: "dev/full_gen_route.go.tmpl", IsDir: false},
+	METRICS_GO: TemplateRef{Path: "dev/metrics.go.tmpl", IsDir: false},
+	PROTO: TemplateRef{Path: "dev/proto.tmpl", IsDir: false},
 	ROUTES_REGISTRY_GO: TemplateRef{Path: "dev/routes_registry.go.tmpl", IsDir: false},
+	ROUTES_REGISTRY_GROUP_GO: TemplateRef{Path: "dev/routes_registry_group.go.tmpl", IsDir: false},
+	ROUTES_REGISTRY_PART_GO: TemplateRef{Path: "dev/routes_registry_part.go.tmpl", IsDir: false},
+	SINGLE_FILE_SERVER_GO: TemplateRef{Path: "dev/single_file_server.go.tmpl", IsDir: false},
+	TS_CLIENT_TS: TemplateRef{Path: "dev/ts_client.ts.tmpl", IsDir: false},
+	TS_INDEX_TS: TemplateRef{Path: "dev/ts_index.ts.tmpl", IsDir: false},
+	TS_ROUTE_TS: TemplateRef{Path: "dev/ts_route.ts.tmpl", IsDir: false},
 	},
 	INIT: InitTemplates{
 	Ref: TemplateRef{Path: "init", IsDir: true},
@@ -0,0 +1,176 @@
+package template_engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+// TemplatesDirEnv names a directory whose files replace the embedded templates. Files are
+// looked up by their path under templates/, e.g. $CONDUIT_TEMPLATES_DIR/dev/route.go.tmpl.
+const TemplatesDirEnv = "CONDUIT_TEMPLATES_DIR"
+
+// overrideFS serves files from override when present there and everything else,
+// including directory listings, from base
+type overrideFS struct {
+	override fs.FS
+	base     fs.FS
+}
+
+func (o *overrideFS) Open(name string) (fs.File, error) {
+	if rel, ok := strings.CutPrefix(name, "templates/"); ok {
+		if info, err := fs.Stat(o.override, rel); err == nil && !info.IsDir() {
+			logger.Debug("Using template override for %s", rel)
+			return o.override.Open(rel)
+		}
+	}
+	return o.base.Open(name)
+}
+
+// validatedDir is the outcome of validating an override directory with the given contents
+type validatedDir struct {
+	fingerprint string
+	err         error
+}
+
+// Override directories are validated and warned about once per fingerprint, not on every
+// engine build
+var (
+	validatedDirs   = make(map[string]validatedDir)
+	validatedDirsMu sync.Mutex
+)
+
+// defaultTemplateFS is the embedded templates, overlaid by CONDUIT_TEMPLATES_DIR when set
+func defaultTemplateFS() fs.FS {
+	dir := os.Getenv(TemplatesDirEnv)
+	if dir == "" {
+		return TemplateFS
+	}
+	fingerprint, err := DirFingerprint(dir)
+	if err != nil {
+		err = fmt.Errorf("%s=%s is not a directory, using the built-in templates", TemplatesDirEnv, dir)
+	}
+	if fresh, _ := validateOnce(TemplatesDirEnv+"="+dir, fingerprint, func() error { return err }); fresh && err != nil {
+		logger.Warn("%v", err)
+	}
+	if err != nil {
+		return TemplateFS
+	}
+	return &overrideFS{override: os.DirFS(dir), base: TemplateFS}
+}
+
+// DirFingerprint identifies the contents of an override directory by the path, size and
+// modification time of every file in it, so editing an override changes it
+func DirFingerprint(dir string) (string, error) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("template directory %s is not a directory", dir)
+	}
+
+	hash := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		fmt.Fprintf(hash, "%s %d %d\n", filepath.ToSlash(rel), info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read template directory %s: %w", dir, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// OverridesFingerprint identifies the contents of every template override in effect when
+// templateDir, which may be empty, is set on an engine. It's empty without overrides.
+func OverridesFingerprint(templateDir string) string {
+	var parts []string
+	for _, dir := range []string{os.Getenv(TemplatesDirEnv), templateDir} {
+		if dir == "" {
+			continue
+		}
+		fingerprint, _ := DirFingerprint(dir)
+		parts = append(parts, dir+"@"+fingerprint)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// validateOnce runs validate for the directory under key unless it already ran for the
+// same fingerprint, returning whether it ran now and its error
+func validateOnce(key, fingerprint string, validate func() error) (bool, error) {
+	validatedDirsMu.Lock()
+	defer validatedDirsMu.Unlock()
+
+	if cached, ok := validatedDirs[key]; ok && cached.fingerprint == fingerprint {
+		return false, cached.err
+	}
+	err := validate()
+	validatedDirs[key] = validatedDir{fingerprint: fingerprint, err: err}
+	return true, err
+}
+
+// SetTemplateDir overlays dir on the templates read so far, so that dir/dev/proto.tmpl
+// replaces the template TEMPLATES.DEV.PROTO refers to. Every template in dir must parse;
+// files that don't replace a built-in template are warned about. A directory is only
+// validated again once its files change.
+func (te *TemplateEngine) SetTemplateDir(dir string) error {
+	fingerprint, err := DirFingerprint(dir)
+	if err != nil {
+		return err
+	}
+	if _, err := validateOnce(dir, fingerprint, func() error { return te.validateTemplateDir(dir) }); err != nil {
+		return err
+	}
+
+	te.templates = &overrideFS{override: os.DirFS(dir), base: te.templates}
+	return nil
+}
+
+// validateTemplateDir parses every template in dir and warns about files that don't
+// replace a built-in template
+func (te *TemplateEngine) validateTemplateDir(dir string) error {
+	override := os.DirFS(dir)
+	var errs []error
+	err := fs.WalkDir(override, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if _, err := fs.Stat(TemplateFS, "templates/"+path); err != nil {
+			logger.Warn("Template override %s doesn't replace a built-in template", filepath.Join(dir, path))
+			return nil
+		}
+		if !strings.HasSuffix(path, ".tmpl") {
+			return nil
+		}
+
+		content, err := fs.ReadFile(override, path)
+		if err != nil {
+			return err
+		}
+		if _, err := template.New(filepath.Base(path)).Funcs(te.funcMap).Parse(string(content)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid template override %s: %w", filepath.Join(dir, path), err))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read template directory %s: %w", dir, err)
+	}
+	return errors.Join(errs...)
+}
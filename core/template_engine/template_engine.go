@@ -1,6 +1,7 @@
 package template_engine
 
 import (
+	"bytes"
 	"fmt"
 	"io/fs"
 	"os"
@@ -28,7 +29,10 @@ func (tr TemplateRef) IsDirectory() bool {
 }
 
 type TemplateEngine struct {
-	funcMap template.FuncMap
+	funcMap      template.FuncMap
+	maxFileBytes int
+	// templates is read for every template, rooted like TemplateFS (paths start with templates/)
+	templates fs.FS
 }
 
 var GlobalFuncMap = template.FuncMap{}
@@ -56,6 +60,11 @@ func getDefaultFuncMap() template.FuncMap {
 		"split":     strings.Split,
 		"join":      strings.Join,
 
+		"camelCase":  shared.ToCamelCase,
+		"pascalCase": shared.ToPascalCase,
+		"snakeCase":  shared.ToSnakeCase,
+		"kebabCase":  shared.ToKebabCase,
+
 		"now":        time.Now,
 		"formatTime": func(layout string, t time.Time) string { return t.Format(layout) },
 		"date":       func(t time.Time) string { return t.Format("2006-01-02") },
@@ -107,7 +116,8 @@ func NewTemplateEngine() *TemplateEngine {
 	}
 
 	return &TemplateEngine{
-		funcMap: funcMap,
+		funcMap:   funcMap,
+		templates: defaultTemplateFS(),
 	}
 }
 
@@ -130,34 +140,67 @@ func (te *TemplateEngine) AddFuncs(funcs template.FuncMap) {
 	}
 }
 
+// SetTemplateFS replaces the filesystem templates are read from. It must be laid out like
+// TemplateFS, with every template under templates/.
+func (te *TemplateEngine) SetTemplateFS(templates fs.FS) {
+	te.templates = templates
+}
+
+// SetMaxFileBytes sets the size above which generated files are reported; zero disables the check
+func (te *TemplateEngine) SetMaxFileBytes(maxFileBytes int) {
+	te.maxFileBytes = maxFileBytes
+}
+
+func (te *TemplateEngine) MaxFileBytes() int {
+	return te.maxFileBytes
+}
+
 func (te *TemplateEngine) GenerateFile(templateRef TemplateRef, outputPath string, data interface{}) error {
+	content, err := te.RenderFile(templateRef, data)
+	if err != nil {
+		return err
+	}
+
+	return te.WriteFile(outputPath, content)
+}
+
+// RenderFile executes a file template and returns the output without writing it
+func (te *TemplateEngine) RenderFile(templateRef TemplateRef, data interface{}) ([]byte, error) {
 	if templateRef.IsDirectory() {
-		return fmt.Errorf("cannot generate file from directory reference: %s", templateRef.Path)
+		return nil, fmt.Errorf("cannot generate file from directory reference: %s", templateRef.Path)
 	}
 
 	templatePath := filepath.Join("templates", templateRef.Path)
-	content, err := TemplateFS.ReadFile(templatePath)
+	content, err := fs.ReadFile(te.templates, templatePath)
 	if err != nil {
-		return fmt.Errorf("failed to read template file %s: %w", templatePath, err)
+		return nil, fmt.Errorf("failed to read template file %s: %w", templatePath, err)
 	}
 
 	tmpl, err := template.New(filepath.Base(templateRef.Path)).Funcs(te.funcMap).Parse(string(content))
 	if err != nil {
-		return fmt.Errorf("failed to parse template %s: %w", templateRef.Path, err)
+		return nil, fmt.Errorf("failed to parse template %s: %w", templateRef.Path, err)
 	}
 
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", templateRef.Path, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// WriteFile writes generated content to disk, warning when it exceeds the configured size limit
+func (te *TemplateEngine) WriteFile(outputPath string, content []byte) error {
 	if err := os.MkdirAll(filepath.Dir(outputPath), os.ModePerm); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	outputFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file %s: %w", outputPath, err)
+	if err := writeFileAtomic(outputPath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write output file %s: %w", outputPath, err)
 	}
-	defer outputFile.Close()
 
-	if err := tmpl.Execute(outputFile, data); err != nil {
-		return fmt.Errorf("failed to execute template %s: %w", templateRef.Path, err)
+	if te.maxFileBytes > 0 && len(content) > te.maxFileBytes {
+		logger.Warn("Generated file %s is %d bytes, exceeding codegen.max_file_bytes (%d)", outputPath, len(content), te.maxFileBytes)
 	}
 
 	return nil
@@ -171,7 +214,7 @@ func (te *TemplateEngine) GenerateFolder(templateRef TemplateRef, outputDir stri
 	templateDir := filepath.Join("templates", templateRef.Path)
 	logger.Debug("Generating folder from template reference: %s", templateDir)
 
-	return fs.WalkDir(TemplateFS, templateDir, func(path string, d fs.DirEntry, err error) error {
+	return fs.WalkDir(te.templates, templateDir, func(path string, d fs.DirEntry, err error) error {
 		logger.Debug("Generating file from path: %s", path)
 		if err != nil {
 			return err
@@ -197,7 +240,7 @@ func (te *TemplateEngine) GenerateFolder(templateRef TemplateRef, outputDir stri
 }
 
 func (te *TemplateEngine) generateFileFromPath(templatePath, outputPath string, data interface{}) error {
-	content, err := TemplateFS.ReadFile(templatePath)
+	content, err := fs.ReadFile(te.templates, templatePath)
 	if err != nil {
 		return fmt.Errorf("failed to read template file %s: %w", templatePath, err)
 	}
@@ -207,7 +250,7 @@ func (te *TemplateEngine) generateFileFromPath(templatePath, outputPath string,
 			return fmt.Errorf("failed to create output directory: %w", err)
 		}
 
-		return os.WriteFile(outputPath, content, 0644)
+		return writeFileAtomic(outputPath, content, 0644)
 	}
 
 	outputPath = strings.TrimSuffix(outputPath, ".tmpl")
@@ -221,14 +264,14 @@ func (te *TemplateEngine) generateFileFromPath(templatePath, outputPath string,
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	outputFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file %s: %w", outputPath, err)
+	// Render fully before touching the output so a failing template leaves the old file intact
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template %s: %w", templatePath, err)
 	}
-	defer outputFile.Close()
 
-	if err := tmpl.Execute(outputFile, data); err != nil {
-		return fmt.Errorf("failed to execute template %s: %w", templatePath, err)
+	if err := writeFileAtomic(outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write output file %s: %w", outputPath, err)
 	}
 
 	return nil
@@ -242,7 +285,7 @@ func (te *TemplateEngine) ListTemplates(templateRef TemplateRef) ([]string, erro
 	var templates []string
 	templateDir := filepath.Join("templates", templateRef.Path)
 
-	err := fs.WalkDir(TemplateFS, templateDir, func(path string, d fs.DirEntry, err error) error {
+	err := fs.WalkDir(te.templates, templateDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -264,7 +307,7 @@ func (te *TemplateEngine) ListTemplates(templateRef TemplateRef) ([]string, erro
 func (te *TemplateEngine) ValidateTemplate(templateRef TemplateRef) error {
 	templatePath := filepath.Join("templates", templateRef.Path)
 
-	info, err := fs.Stat(TemplateFS, templatePath)
+	info, err := fs.Stat(te.templates, templatePath)
 	if err != nil {
 		return fmt.Errorf("template not found: %s", templateRef.Path)
 	}
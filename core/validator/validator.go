@@ -0,0 +1,171 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"go/scanner"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tristendillon/conduit/core/ast"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/ignore"
+)
+
+const (
+	CheckSyntax     = "syntax"
+	CheckConflict   = "conflict"
+	CheckDependency = "dependency"
+	CheckSignature  = "signature"
+	CheckNaming     = "naming"
+	CheckMiddleware = "middleware"
+)
+
+// Issue is a single problem found while validating a project
+type Issue struct {
+	Check   string `json:"check"`
+	File    string `json:"file"`
+	Message string `json:"message"`
+}
+
+// Validate checks the project rooted at wd for syntax errors in route files, conflicting
+// routes, missing local dependencies, malformed handler signatures, misnamed handlers and
+// @middleware annotations that don't resolve
+func Validate(wd string) ([]Issue, error) {
+	rg := generator.NewRouteGenerator(wd)
+	tree, err := rg.WalkRouteTree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk route tree: %w", err)
+	}
+
+	var issues []Issue
+
+	routeFiles, err := findRouteFiles(wd, rg.Walker.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find route files: %w", err)
+	}
+
+	for _, routeFile := range routeFiles {
+		relPath := relativeTo(wd, routeFile)
+
+		if err := ast.CheckSyntax(routeFile); err != nil {
+			var errorList scanner.ErrorList
+			if errors.As(err, &errorList) {
+				for _, syntaxErr := range errorList {
+					issues = append(issues, Issue{
+						Check:   CheckSyntax,
+						File:    relPath,
+						Message: fmt.Sprintf("%d:%d: %s", syntaxErr.Pos.Line, syntaxErr.Pos.Column, syntaxErr.Msg),
+					})
+				}
+			} else {
+				issues = append(issues, Issue{Check: CheckSyntax, File: relPath, Message: err.Error()})
+			}
+			continue
+		}
+
+		problems, err := ast.CheckHandlerSignatures(routeFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check handler signatures in %s: %w", relPath, err)
+		}
+		for _, problem := range problems {
+			issues = append(issues, Issue{Check: CheckSignature, File: relPath, Message: problem})
+		}
+
+		violations, err := ast.CheckHandlerNames(routeFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check handler names in %s: %w", relPath, err)
+		}
+		for _, violation := range violations {
+			issues = append(issues, Issue{Check: CheckNaming, File: relPath, Message: violation.Issue})
+		}
+	}
+
+	conflicts, err := tree.DetectConflicts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect route conflicts: %w", err)
+	}
+	for _, conflict := range conflicts {
+		issues = append(issues, Issue{
+			Check:   CheckConflict,
+			File:    filepath.Join(conflict.FolderPaths[0], "route.go"),
+			Message: conflict.String(),
+		})
+	}
+
+	for _, route := range tree.Routes {
+		if route.ParsedFile == nil || route.ParsedFile.Dependencies == nil {
+			continue
+		}
+		for _, dep := range route.ParsedFile.Dependencies.LocalImports {
+			if _, err := os.Stat(filepath.Join(wd, dep.RelativePath)); err != nil {
+				issues = append(issues, Issue{
+					Check:   CheckDependency,
+					File:    relativeTo(wd, route.ParsedFile.Path),
+					Message: fmt.Sprintf("local dependency %s not found at %s", dep.ImportPath, dep.RelativePath),
+				})
+			}
+		}
+	}
+
+	for _, route := range tree.Routes {
+		if route.ParsedFile == nil {
+			continue
+		}
+		problems, err := ast.CheckMiddlewares(wd, route.ParsedFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check middleware in %s: %w", relativeTo(wd, route.ParsedFile.Path), err)
+		}
+		for _, problem := range problems {
+			issues = append(issues, Issue{Check: CheckMiddleware, File: relativeTo(wd, route.ParsedFile.Path), Message: problem})
+		}
+	}
+
+	return issues, nil
+}
+
+// findRouteFiles returns every route.go under wd outside the excluded directories
+func findRouteFiles(wd string, exclude []string) ([]string, error) {
+	var files []string
+
+	ignoreMatcher, err := ignore.Load(wd)
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.WalkDir(wd, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			relPath := relativeTo(wd, path)
+			for _, ex := range exclude {
+				if ex != "" && relPath != "." && strings.Contains(relPath, ex) {
+					return filepath.SkipDir
+				}
+			}
+			if ignoreMatcher.Match(relPath, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.Name() == "route.go" {
+			files = append(files, path)
+		}
+		return nil
+	})
+
+	sort.Strings(files)
+	return files, err
+}
+
+func relativeTo(wd, path string) string {
+	if rel, err := filepath.Rel(wd, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -3,12 +3,15 @@ package walker
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/tristendillon/conduit/core/ast"
 	"github.com/tristendillon/conduit/core/cache"
 	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/ignore"
 	"github.com/tristendillon/conduit/core/logger"
 	"github.com/tristendillon/conduit/core/models"
 )
@@ -22,24 +25,45 @@ type RouteWalkerImpl struct {
 	Exclude   []string
 }
 
+func getDefaultExcludePaths() []string {
+	return []string{
+		".git", "node_modules", "vendor", ".next",
+		"build", "dist", "__pycache__", ".DS_Store",
+		".conduit", // default output directory for conduit
+	}
+}
+
 func getExcludePaths() []string {
+	exclude := getDefaultExcludePaths()
 	cfg, err := config.Load()
 	if err != nil {
 		logger.Debug("Failed to load config: %v", err)
+		return exclude
 	}
-	return []string{
-		".git", "node_modules", "vendor", ".next",
-		"build", "dist", "__pycache__", ".DS_Store",
-		".conduit", // default output directory for conduit
-		cfg.Codegen.Go.Output,
-		cfg.Codegen.Typescript.Output,
+	return append(append(exclude, cfg.Codegen.Typescript.Output), cfg.GoOutputDirs()...)
+}
+
+// ApplyConfig updates the excluded output directories and the depth limit from cfg
+func (w *RouteWalkerImpl) ApplyConfig(cfg *config.Config) {
+	w.Exclude = append(append(getDefaultExcludePaths(), cfg.Codegen.Typescript.Output), cfg.GoOutputDirs()...)
+	w.RouteTree.MaxDepth = cfg.Codegen.MaxDepth
+}
+
+func getMaxDepth() int {
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Debug("Failed to load config: %v", err)
+		return models.DefaultMaxDepth
 	}
+	return cfg.Codegen.MaxDepth
 }
 
 func NewRouteWalker() *RouteWalkerImpl {
 	exclude := getExcludePaths()
+	routeTree := models.NewRouteTree()
+	routeTree.MaxDepth = getMaxDepth()
 	return &RouteWalkerImpl{
-		RouteTree: models.NewRouteTree(),
+		RouteTree: routeTree,
 		Exclude:   exclude,
 	}
 }
@@ -53,13 +77,20 @@ func (w *RouteWalkerImpl) Walk(root string, moduleName string) ([]models.Discove
 	cacheManager := cache.GetCacheManager()
 
 	// Warm the cache if this is the first run
-	if err := cacheManager.WarmCache(root, w.Exclude); err != nil {
+	if report, err := cacheManager.WarmCache(root, w.Exclude, moduleName); err != nil {
 		logger.Debug("Failed to warm cache: %v", err)
+	} else {
+		logger.Debug("Warmed cache in %v: %d hashed, %d parsed, %d reused", report.Duration, report.FilesHashed, report.FilesParsed, report.FilesSkipped)
 	}
 
-	var cacheHits, cacheMisses int
+	ignoreMatcher, err := ignore.Load(root)
+	if err != nil {
+		logger.Warn("Ignoring %s: %v", ignore.FileName, err)
+	}
 
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+	// Directories are enumerated serially so routes are added to the tree in a stable order
+	var jobs []routeJob
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -73,6 +104,10 @@ func (w *RouteWalkerImpl) Walk(root string, moduleName string) ([]models.Discove
 			return err
 		}
 		if relPath == "." {
+			// The project root isn't a route, but its middleware wraps every route
+			if _, err := os.Stat(filepath.Join(path, ast.MiddlewareFileName)); err == nil {
+				w.addMiddleware(filepath.Join(path, ast.MiddlewareFileName), relPath, moduleName)
+			}
 			return nil
 		}
 
@@ -81,40 +116,47 @@ func (w *RouteWalkerImpl) Walk(root string, moduleName string) ([]models.Discove
 				return nil
 			}
 		}
+		if ignoreMatcher.Match(relPath, true) {
+			logger.Debug("Skipping %s (matched %s)", relPath, ignore.FileName)
+			return filepath.SkipDir
+		}
+
+		// Middleware is recorded before any route so every route below the folder picks it up
+		middlewareFile := filepath.Join(path, ast.MiddlewareFileName)
+		if _, err := os.Stat(middlewareFile); err == nil {
+			w.addMiddleware(middlewareFile, relPath, moduleName)
+		}
 
 		routeFile := filepath.Join(path, "route.go")
 		if _, err := os.Stat(routeFile); err == nil {
-			// Try to get from cache first
-			if cachedParsed, found, err := cacheManager.GetParsedFile(routeFile); err == nil && found {
-				w.RouteTree.AddRoute(cachedParsed)
-				logger.Debug("Using cached route: %s (methods: %v)", relPath, cachedParsed.Methods)
-				cacheHits++
-			} else {
-				// Parse the file
-				parsed, err := ast.ParseRouteWithFunctions(routeFile, relPath, moduleName)
-				if err != nil {
-					logger.Debug("Failed to parse route %s: %v, skipping", routeFile, err)
-					return nil
-				}
-
-				// Store in cache using new cache manager
-				if err := cacheManager.SetParsedFile(routeFile, parsed); err != nil {
-					logger.Debug("Failed to cache parsed route %s: %v", routeFile, err)
-				}
-
-				w.RouteTree.AddRoute(parsed)
-				if len(parsed.Methods) > 0 {
-					logger.Debug("Parsed and registered route: %s (methods: %v)", relPath, parsed.Methods)
-				} else {
-					logger.Debug("Parsed route: %s (no methods found - may be empty or incomplete)", relPath)
-				}
-				cacheMisses++
-			}
+			jobs = append(jobs, routeJob{index: len(jobs), routeFile: routeFile, relPath: relPath})
 		}
 
 		return nil
 	})
 
+	var cacheHits, cacheMisses int
+	for _, result := range parseRoutes(jobs, moduleName) {
+		if result.err != nil {
+			logger.Debug("Failed to parse route %s: %v, skipping", result.job.routeFile, result.err)
+			continue
+		}
+
+		w.RouteTree.AddRoute(result.parsed)
+		if result.cached {
+			logger.Debug("Using cached route: %s (methods: %v)", result.job.relPath, result.parsed.Methods)
+			cacheHits++
+			continue
+		}
+
+		if len(result.parsed.Methods) > 0 {
+			logger.Debug("Parsed and registered route: %s (methods: %v)", result.job.relPath, result.parsed.Methods)
+		} else {
+			logger.Debug("Parsed route: %s (no methods found - may be empty or incomplete)", result.job.relPath)
+		}
+		cacheMisses++
+	}
+
 	walkDuration := time.Since(startTime)
 	totalRoutes := cacheHits + cacheMisses
 
@@ -134,3 +176,95 @@ func (w *RouteWalkerImpl) Walk(root string, moduleName string) ([]models.Discove
 
 	return discovered, err
 }
+
+// addMiddleware parses a folder's _middleware.go into the route tree, skipping it with a
+// warning when it can't be parsed
+func (w *RouteWalkerImpl) addMiddleware(middlewareFile, relPath, moduleName string) {
+	src, err := cache.GetCacheManager().ReadSource(middlewareFile)
+	if err != nil {
+		logger.Warn("Skipping %s: %v", filepath.Join(relPath, ast.MiddlewareFileName), err)
+		return
+	}
+	parsed, err := ast.ParseMiddlewareSource(middlewareFile, relPath, moduleName, src)
+	if err != nil {
+		logger.Warn("Skipping %s: %v", filepath.Join(relPath, ast.MiddlewareFileName), err)
+		return
+	}
+
+	logger.Debug("Found %d middleware in %s", len(parsed.Functions), relPath)
+	w.RouteTree.AddMiddleware(parsed)
+}
+
+type routeJob struct {
+	index     int
+	routeFile string
+	relPath   string
+}
+
+type routeResult struct {
+	job    routeJob
+	parsed *models.ParsedFile
+	cached bool
+	err    error
+}
+
+// parseRoutes loads every route file from the cache or parses it on a pool of
+// runtime.NumCPU() workers, returning the results in job order
+func parseRoutes(jobs []routeJob, moduleName string) []routeResult {
+	results := make([]routeResult, len(jobs))
+	if len(jobs) == 0 {
+		return results
+	}
+
+	jobCh := make(chan routeJob)
+	resultCh := make(chan routeResult, len(jobs))
+	workers := min(runtime.NumCPU(), len(jobs))
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobCh {
+				resultCh <- parseRoute(job, moduleName)
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		jobCh <- job
+	}
+	close(jobCh)
+	wg.Wait()
+	close(resultCh)
+
+	for result := range resultCh {
+		results[result.job.index] = result
+	}
+	return results
+}
+
+// parseRoute returns the cached parse of a route file, parsing and caching it on a miss
+func parseRoute(job routeJob, moduleName string) routeResult {
+	cacheManager := cache.GetCacheManager()
+
+	if cachedParsed, found, err := cacheManager.GetParsedFile(job.routeFile); err == nil && found {
+		return routeResult{job: job, parsed: cachedParsed, cached: true}
+	}
+
+	src, err := cacheManager.ReadSource(job.routeFile)
+	if err != nil {
+		return routeResult{job: job, err: err}
+	}
+	parsed, err := ast.ParseRouteSource(job.routeFile, job.relPath, moduleName, src)
+	if err != nil {
+		return routeResult{job: job, err: err}
+	}
+
+	// Store in cache using new cache manager
+	if err := cacheManager.SetParsedFile(job.routeFile, parsed); err != nil {
+		logger.Debug("Failed to cache parsed route %s: %v", job.routeFile, err)
+	}
+
+	return routeResult{job: job, parsed: parsed}
+}
@@ -0,0 +1,183 @@
+package watcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tristendillon/conduit/core/cache/layers"
+	cacheModels "github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/ignore"
+	"github.com/tristendillon/conduit/core/logger"
+)
+
+// POLL_INTERVAL is used when the poll watcher has no configured interval
+var POLL_INTERVAL = time.Second
+
+// PollingWatcherImpl detects route changes by rescanning the project on an interval,
+// for filesystems where fsnotify never delivers events (network mounts, some bind mounts)
+type PollingWatcherImpl struct {
+	*FileWatcherImpl
+
+	// content holds the hashes seen by the last scan, separate from the shared cache
+	// so the cache manager still sees the change when the events are handled
+	content *layers.ContentCache
+	// settings holds the hashes of the config and ignore files seen by the last scan,
+	// empty for files that don't exist
+	settings  map[string]string
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewPollingWatcher(fw *FileWatcherImpl) *PollingWatcherImpl {
+	return &PollingWatcherImpl{
+		FileWatcherImpl: fw,
+		content:         layers.NewContentCache(),
+		settings:        make(map[string]string),
+		stop:            make(chan struct{}),
+	}
+}
+
+// ForMode returns the watcher implementation selected by watcher.mode
+func ForMode(fw *FileWatcherImpl) FileWatcher {
+	if fw.FileWatcher.Mode == config.WatcherModePoll {
+		return NewPollingWatcher(fw)
+	}
+	return fw
+}
+
+func (pw *PollingWatcherImpl) Watch() error {
+	interval := pw.FileWatcher.PollInterval
+	if interval <= 0 {
+		interval = POLL_INTERVAL
+	}
+
+	// The first scan records the baseline; only later differences are changes
+	pw.scan()
+	pw.scanSettings()
+	logger.Debug("Polling %s for route changes every %v", pw.FileWatcher.RootDir, interval)
+
+	if err := pw.FileWatcher.OnStart(); err != nil {
+		logger.Error("Watcher.OnStart failed: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pw.stop:
+			return fmt.Errorf("watcher closed")
+		case <-ticker.C:
+			pw.reloadSettings(pw.scanSettings())
+			events := pw.scan()
+			if len(events) == 0 {
+				continue
+			}
+			for _, event := range events {
+				logger.Debug("File event: %s %s", event.EventType, event.FilePath)
+				pw.queueChangeEvent(event)
+			}
+			pw.debounceGenerate()
+		}
+	}
+}
+
+// scan walks the project and returns change events for route files that were created,
+// modified or deleted since the previous scan
+func (pw *PollingWatcherImpl) scan() []*cacheModels.ChangeEvent {
+	var events []*cacheModels.ChangeEvent
+	seen := make(map[string]bool)
+
+	filepath.WalkDir(pw.FileWatcher.RootDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != pw.FileWatcher.RootDir && pw.shouldExcludePath(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != "route.go" {
+			return nil
+		}
+
+		seen[path] = true
+		entry, known := pw.content.GetContent(path)
+		known = known && entry.Exists
+		_, changed, err := pw.content.UpdateContent(path)
+		if err != nil {
+			logger.Debug("Failed to hash %s: %v", path, err)
+			return nil
+		}
+
+		switch {
+		case !known:
+			events = append(events, &cacheModels.ChangeEvent{FilePath: path, EventType: "create", Timestamp: time.Now()})
+		case changed:
+			events = append(events, &cacheModels.ChangeEvent{FilePath: path, EventType: "write", Timestamp: time.Now()})
+		}
+		return nil
+	})
+
+	var deleted []string
+	for _, path := range pw.content.ListFiles() {
+		if !seen[path] {
+			deleted = append(deleted, path)
+		}
+	}
+	sort.Strings(deleted)
+	for _, path := range deleted {
+		pw.content.RemoveContent(path)
+		events = append(events, &cacheModels.ChangeEvent{FilePath: path, EventType: "delete", Timestamp: time.Now()})
+	}
+
+	return events
+}
+
+// scanSettings returns the config and ignore files in the project root that were created,
+// modified or deleted since the previous scan
+func (pw *PollingWatcherImpl) scanSettings() []string {
+	var changed []string
+	for _, name := range append([]string{ignore.FileName}, config.FileNames...) {
+		path := filepath.Join(pw.FileWatcher.RootDir, name)
+		hash, err := cacheModels.HashFile(path)
+		if err != nil {
+			hash = ""
+		}
+		if previous, seen := pw.settings[path]; seen && previous != hash {
+			changed = append(changed, path)
+		}
+		pw.settings[path] = hash
+	}
+	return changed
+}
+
+// reloadSettings reloads the ignore patterns and config like the fsnotify watcher does
+// when their files change
+func (pw *PollingWatcherImpl) reloadSettings(changed []string) {
+	configChanged := ""
+	for _, path := range changed {
+		if filepath.Base(path) == ignore.FileName {
+			logger.Debug("%s changed, reloading ignore patterns", ignore.FileName)
+			pw.FileWatcher.LoadIgnore()
+			pw.debounceGenerate()
+		} else if configChanged == "" {
+			configChanged = path
+		}
+	}
+	// Config.Load picks the file itself, so one reload covers every changed config file
+	if configChanged != "" {
+		pw.reloadConfig(configChanged)
+	}
+}
+
+func (pw *PollingWatcherImpl) Close() error {
+	pw.closeOnce.Do(func() { close(pw.stop) })
+	return pw.FileWatcherImpl.Close()
+}
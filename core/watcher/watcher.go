@@ -1,20 +1,33 @@
 package watcher
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/tristendillon/conduit/core/cache"
 	cacheModels "github.com/tristendillon/conduit/core/cache/models"
+	"github.com/tristendillon/conduit/core/config"
+	"github.com/tristendillon/conduit/core/generator"
+	"github.com/tristendillon/conduit/core/ignore"
 	"github.com/tristendillon/conduit/core/logger"
 	"github.com/tristendillon/conduit/core/models"
 )
 
-var DEBOUNCE_TIME = 300 * time.Millisecond
+// DEBOUNCE_TIME is used when the watcher has no configured debounce interval
+var DEBOUNCE_TIME = 500 * time.Millisecond
+
+// The delay before recreating the watcher after a critical error doubles from
+// reconnectInitialDelay with every failed attempt, up to reconnectMaxDelay
+const (
+	reconnectInitialDelay = 100 * time.Millisecond
+	reconnectMaxDelay     = 30 * time.Second
+)
 
 type FileWatcher interface {
 	Watch() error
@@ -22,11 +35,13 @@ type FileWatcher interface {
 	Close() error
 	shouldExcludePath(path string) bool
 	addWatchersRecursively(root string) error
-	loadExcludePaths() error
 }
 
 type FileWatcherImpl struct {
 	FileWatcher *models.FileWatcher
+
+	// pendingEvents accumulates route changes between debounce ticks, guarded by FileWatcher.Mutex
+	pendingEvents []*cacheModels.ChangeEvent
 }
 
 func NewFileWatcher(rootDir string, excludePaths []string) (*FileWatcherImpl, error) {
@@ -41,7 +56,10 @@ func NewFileWatcher(rootDir string, excludePaths []string) (*FileWatcherImpl, er
 
 func (fw *FileWatcherImpl) Watch() error {
 	if err := fw.addWatchersRecursively(fw.FileWatcher.RootDir); err != nil {
-		return fmt.Errorf("failed to add watchers: %w", err)
+		if !isCriticalWatchError(err) {
+			return fmt.Errorf("failed to add watchers: %w", err)
+		}
+		fw.reconnect(err)
 	}
 
 	if err := fw.FileWatcher.OnStart(); err != nil {
@@ -55,6 +73,20 @@ func (fw *FileWatcherImpl) Watch() error {
 				return fmt.Errorf("watcher events channel closed")
 			}
 
+			if filepath.Clean(event.Name) == filepath.Join(fw.FileWatcher.RootDir, ignore.FileName) {
+				logger.Debug("%s changed, reloading ignore patterns", ignore.FileName)
+				fw.FileWatcher.LoadIgnore()
+				fw.debounceGenerate()
+				continue
+			}
+
+			if filepath.Dir(filepath.Clean(event.Name)) == filepath.Clean(fw.FileWatcher.RootDir) && config.IsConfigFile(filepath.Base(event.Name)) {
+				if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+					fw.reloadConfig(event.Name)
+				}
+				continue
+			}
+
 			if fw.shouldExcludePath(event.Name) {
 				continue
 			}
@@ -62,45 +94,41 @@ func (fw *FileWatcherImpl) Watch() error {
 			logger.Debug("File event: %s %s", event.Op, event.Name)
 
 			if strings.HasSuffix(event.Name, "route.go") {
-				cacheManager := cache.GetCacheManager()
-
-				// Create change event for the cache manager
+				// Create change event for the cache manager. A rename reports the old path,
+				// which the cache manager pairs with the create of the new one.
 				var eventType string
 				if event.Has(fsnotify.Write) {
 					eventType = "write"
-				} else if event.Has(fsnotify.Remove) {
+				} else if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
 					eventType = "delete"
 				} else if event.Has(fsnotify.Create) {
 					eventType = "create"
 				}
 
 				if eventType != "" {
-					changeEvent := &cacheModels.ChangeEvent{
+					fw.queueChangeEvent(&cacheModels.ChangeEvent{
 						FilePath:  event.Name,
 						EventType: eventType,
 						Timestamp: time.Now(),
-					}
-
-					// Handle the file change through new cache system
-					plan, err := cacheManager.HandleFileChange(changeEvent)
-					if err != nil {
-						logger.Debug("Failed to handle file change for %s: %v", event.Name, err)
-					} else if len(plan.AffectedFiles) > 0 {
-						logger.Debug("File change detected: %s affects %d files", event.Name, len(plan.AffectedFiles))
-						for _, affected := range plan.AffectedFiles {
-							logger.Debug("  Affected: %s (%s)", affected, plan.Reasons[affected])
-						}
-					} else {
-						logger.Debug("File modified but no regeneration needed: %s", event.Name)
-					}
+					})
 				}
+			} else if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				// A removed or renamed directory takes every cached file under it with it
+				fw.invalidateDirectory(event.Name)
 			}
 
 			if event.Has(fsnotify.Create) {
 				if stat, err := os.Stat(event.Name); err == nil && stat.IsDir() {
 					if !fw.shouldExcludePath(event.Name) {
-						logger.Debug("Adding watcher for new directory: %s", event.Name)
-						fw.FileWatcher.Watcher.Add(event.Name)
+						logger.Debug("Adding watchers for new directory: %s", event.Name)
+						if err := fw.addWatchersRecursively(event.Name); err != nil {
+							if isCriticalWatchError(err) {
+								fw.reconnect(err)
+							} else {
+								logger.Debug("Failed to watch %s: %v", event.Name, err)
+							}
+						}
+						fw.queueRouteFilesUnder(event.Name)
 					}
 				}
 			}
@@ -111,11 +139,124 @@ func (fw *FileWatcherImpl) Watch() error {
 			if !ok {
 				return fmt.Errorf("watcher errors channel closed")
 			}
+			if isCriticalWatchError(err) {
+				fw.reconnect(err)
+				// Changes made while no watcher was running produced no events
+				fw.debounceGenerate()
+				continue
+			}
 			logger.Error("Watcher error: %v", err)
 		}
 	}
 }
 
+// isCriticalWatchError reports whether err leaves the watcher unable to track the project,
+// such as running out of file descriptors or inotify watches
+func isCriticalWatchError(err error) bool {
+	return errors.Is(err, syscall.EMFILE) || errors.Is(err, syscall.ENFILE) || errors.Is(err, syscall.ENOSPC)
+}
+
+// reconnect closes the fsnotify watcher after a critical error, then recreates it and
+// watches the project again, backing off exponentially until that succeeds
+func (fw *FileWatcherImpl) reconnect(cause error) {
+	delay := reconnectInitialDelay
+	for attempt := 1; ; attempt++ {
+		logger.Warn("Watcher error: %v; reconnecting in %v (attempt %d)", cause, delay, attempt)
+
+		fw.FileWatcher.Mutex.Lock()
+		if err := fw.FileWatcher.Watcher.Close(); err != nil {
+			logger.Debug("Failed to close watcher: %v", err)
+		}
+		fw.FileWatcher.Mutex.Unlock()
+
+		time.Sleep(delay)
+		delay = min(delay*2, reconnectMaxDelay)
+
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			cause = fmt.Errorf("failed to create file watcher: %w", err)
+			continue
+		}
+		fw.FileWatcher.Mutex.Lock()
+		fw.FileWatcher.Watcher = watcher
+		fw.FileWatcher.Mutex.Unlock()
+
+		if err := fw.addWatchersRecursively(fw.FileWatcher.RootDir); err != nil {
+			cause = fmt.Errorf("failed to add watchers: %w", err)
+			continue
+		}
+		logger.Info("Watcher reconnected after %d attempt(s)", attempt)
+		return
+	}
+}
+
+// reloadConfig loads the changed config and hands it to the watcher settings and the
+// OnConfigChange callback before regenerating. An invalid config keeps the previous one.
+func (fw *FileWatcherImpl) reloadConfig(path string) {
+	logger.Info("%s changed, reloading config", filepath.Base(path))
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Error("Keeping the previous config: %v", err)
+		return
+	}
+
+	fw.FileWatcher.Mutex.Lock()
+	fw.FileWatcher.ApplyConfig(cfg)
+	fw.FileWatcher.Mutex.Unlock()
+
+	if err := fw.FileWatcher.OnConfigChange(cfg); err != nil {
+		logger.Error("Watcher.OnConfigChange failed: %v", err)
+		return
+	}
+	fw.debounceGenerate()
+}
+
+// invalidateDirectory drops a removed directory's files from the cache. Paths that aren't
+// cached directories (such as removed non-route files) invalidate nothing.
+func (fw *FileWatcherImpl) invalidateDirectory(dir string) {
+	plan, err := cache.GetCacheManager().InvalidateSubtree(dir)
+	if err != nil {
+		logger.Debug("Failed to invalidate cache under %s: %v", dir, err)
+		return
+	}
+	if len(plan.ChangedFiles) == 0 {
+		return
+	}
+
+	logger.Debug("Directory %s removed: %d cached files invalidated, %d files affected", dir, len(plan.ChangedFiles), len(plan.AffectedFiles))
+	for _, affected := range plan.AffectedFiles {
+		logger.Debug("  Affected: %s (%s)", affected, plan.Reasons[affected])
+	}
+}
+
+// queueRouteFilesUnder queues create events for route files in a newly created (or moved in) directory
+func (fw *FileWatcherImpl) queueRouteFilesUnder(dir string) {
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && fw.shouldExcludePath(path) {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && d.Name() == "route.go" {
+			fw.queueChangeEvent(&cacheModels.ChangeEvent{
+				FilePath:  path,
+				EventType: "create",
+				Timestamp: time.Now(),
+			})
+		}
+		return nil
+	})
+}
+
+// queueChangeEvent holds a change event until the debounce timer fires
+func (fw *FileWatcherImpl) queueChangeEvent(event *cacheModels.ChangeEvent) {
+	fw.FileWatcher.Mutex.Lock()
+	defer fw.FileWatcher.Mutex.Unlock()
+
+	fw.pendingEvents = append(fw.pendingEvents, event)
+}
+
 func (fw *FileWatcherImpl) debounceGenerate() {
 	fw.FileWatcher.Mutex.Lock()
 	defer fw.FileWatcher.Mutex.Unlock()
@@ -124,7 +265,19 @@ func (fw *FileWatcherImpl) debounceGenerate() {
 		fw.FileWatcher.DebounceTimer.Stop()
 	}
 
-	fw.FileWatcher.DebounceTimer = time.AfterFunc(DEBOUNCE_TIME, func() {
+	interval := fw.FileWatcher.DebounceInterval
+	if interval <= 0 {
+		interval = DEBOUNCE_TIME
+	}
+
+	fw.FileWatcher.DebounceTimer = time.AfterFunc(interval, func() {
+		fw.FileWatcher.Mutex.Lock()
+		events := fw.pendingEvents
+		fw.pendingEvents = nil
+		fw.FileWatcher.Mutex.Unlock()
+
+		fw.handleChangeEvents(events)
+
 		logger.Debug("File changes detected, regenerating...")
 		if err := fw.FileWatcher.OnChange(); err != nil {
 			logger.Error("Watcher.OnChange failed: %v", err)
@@ -132,6 +285,43 @@ func (fw *FileWatcherImpl) debounceGenerate() {
 	})
 }
 
+// handleChangeEvents passes the accumulated events to the cache manager in a single batch
+func (fw *FileWatcherImpl) handleChangeEvents(events []*cacheModels.ChangeEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	cacheManager := cache.GetCacheManager()
+	plan, err := cacheManager.HandleFileChanges(events)
+	if err != nil {
+		logger.Debug("Failed to handle some of %d file changes: %v", len(events), err)
+	}
+	if plan == nil {
+		return
+	}
+
+	if len(plan.StaleOutputs) > 0 {
+		var roots []string
+		if cfg, err := config.Load(); err == nil {
+			roots = cfg.OutputDirs()
+		} else {
+			logger.Debug("Failed to load config, keeping directories emptied by stale outputs: %v", err)
+		}
+		if err := generator.RemoveStaleOutputs(plan.StaleOutputs, roots); err != nil {
+			logger.Warn("Failed to remove stale generated files: %v", err)
+		}
+	}
+
+	if len(plan.AffectedFiles) > 0 {
+		logger.Debug("%d file changes affect %d files", len(plan.ChangedFiles), len(plan.AffectedFiles))
+		for _, affected := range plan.AffectedFiles {
+			logger.Debug("  Affected: %s (%s)", affected, plan.Reasons[affected])
+		}
+	} else {
+		logger.Debug("Files modified but no regeneration needed: %v", plan.ChangedFiles)
+	}
+}
+
 func (fw *FileWatcherImpl) Close() error {
 	fw.FileWatcher.Mutex.Lock()
 	defer fw.FileWatcher.Mutex.Unlock()
@@ -166,7 +356,11 @@ func (fw *FileWatcherImpl) shouldExcludePath(path string) bool {
 		}
 	}
 
-	return false
+	isDir := false
+	if stat, err := os.Stat(path); err == nil {
+		isDir = stat.IsDir()
+	}
+	return fw.FileWatcher.Ignore.Match(relPath, isDir)
 }
 
 func (fw *FileWatcherImpl) addWatchersRecursively(root string) error {
@@ -1,4 +1,4 @@
-// Code generated by conduit at 2025-09-15 09:00:01. DO NOT EDIT.
+// Code generated by conduit at 2026-10-17 01:53:44. DO NOT EDIT.
 // Auto-aggregates all generated route handlers
 
 package generated
@@ -6,12 +6,12 @@ package generated
 import (
 	"net/http"
 
-__conduit_health_route "my-app/.conduit/go/routes/__conduit/health"
+conduit_health_route "my-app/.conduit/go/routes/__conduit/health"
 api_v1_orgs_route "my-app/.conduit/go/routes/api/v1/orgs"
 api_v1_profiles_route "my-app/.conduit/go/routes/api/v1/profiles"
-api_v1_profiles_id__route "my-app/.conduit/go/routes/api/v1/profiles/id_"
+api_v1_profiles_id_param_route "my-app/.conduit/go/routes/api/v1/profiles/id_"
 api_v1_users_route "my-app/.conduit/go/routes/api/v1/users"
-api_v1_users_id__route "my-app/.conduit/go/routes/api/v1/users/id_"
+api_v1_users_id_param_route "my-app/.conduit/go/routes/api/v1/users/id_"
 
 )
 
@@ -22,52 +22,58 @@ func GetConfiguredRouter() *http.ServeMux {
 }
 
 func RegisterRoutes(mux *http.ServeMux) {
-__conduit_health_route.SetupRoutes(mux, "/__conduit/health")
+conduit_health_route.SetupRoutes(mux, "/__conduit/health")
 api_v1_orgs_route.SetupRoutes(mux, "/api/v1/orgs")
 api_v1_profiles_route.SetupRoutes(mux, "/api/v1/profiles")
-api_v1_profiles_id__route.SetupRoutes(mux, "/api/v1/profiles/:id")
+api_v1_profiles_id_param_route.SetupRoutes(mux, "/api/v1/profiles/{id}")
 api_v1_users_route.SetupRoutes(mux, "/api/v1/users")
-api_v1_users_id__route.SetupRoutes(mux, "/api/v1/users/:id")
+api_v1_users_id_param_route.SetupRoutes(mux, "/api/v1/users/{id}")
 
 }
 
 func GetAllRoutes() []RouteInfo {
 	return []RouteInfo{
 {
-			APIPath:    "__conduit/health",
-			FolderPath: "__conduit/health",
-			Methods:    []string{ "GET" },
-			Parameters: []string{  },
+			APIPath:     "__conduit/health",
+			FolderPath:  "__conduit/health",
+			Methods:     []string{ "GET" },
+			Parameters:  []string{  },
+			Description: "",
 		},
 {
-			APIPath:    "api/v1/orgs",
-			FolderPath: "api/v1/orgs",
-			Methods:    []string{ "GET" },
-			Parameters: []string{  },
+			APIPath:     "api/v1/orgs",
+			FolderPath:  "api/v1/orgs",
+			Methods:     []string{ "GET" },
+			Parameters:  []string{  },
+			Description: "",
 		},
 {
-			APIPath:    "api/v1/profiles",
-			FolderPath: "api/v1/profiles",
-			Methods:    []string{ "GET" },
-			Parameters: []string{  },
+			APIPath:     "api/v1/profiles",
+			FolderPath:  "api/v1/profiles",
+			Methods:     []string{ "GET" },
+			Parameters:  []string{  },
+			Description: "",
 		},
 {
-			APIPath:    "api/v1/profiles/:id",
-			FolderPath: "api/v1/profiles/id_",
-			Methods:    []string{ "GET", "DELETE" },
-			Parameters: []string{ "id" },
+			APIPath:     "api/v1/profiles/:id",
+			FolderPath:  "api/v1/profiles/id_",
+			Methods:     []string{ "GET", "DELETE" },
+			Parameters:  []string{ "id" },
+			Description: "",
 		},
 {
-			APIPath:    "api/v1/users",
-			FolderPath: "api/v1/users",
-			Methods:    []string{ "GET" },
-			Parameters: []string{  },
+			APIPath:     "api/v1/users",
+			FolderPath:  "api/v1/users",
+			Methods:     []string{ "GET" },
+			Parameters:  []string{  },
+			Description: "",
 		},
 {
-			APIPath:    "api/v1/users/:id",
-			FolderPath: "api/v1/users/id_",
-			Methods:    []string{ "GET", "DELETE" },
-			Parameters: []string{ "id" },
+			APIPath:     "api/v1/users/:id",
+			FolderPath:  "api/v1/users/id_",
+			Methods:     []string{ "GET", "DELETE" },
+			Parameters:  []string{ "id" },
+			Description: "",
 		},
 
 	}
@@ -93,8 +99,9 @@ func GetAllAPIPaths() []string {
 }
 
 type RouteInfo struct {
-	APIPath    string
-	FolderPath string
-	Methods    []string
-	Parameters []string
+	APIPath     string
+	FolderPath  string
+	Methods     []string
+	Parameters  []string
+	Description string
 }
\ No newline at end of file
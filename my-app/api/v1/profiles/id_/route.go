@@ -6,7 +6,7 @@ import (
 )
 
 func GET(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Query().Get("id")
+	id := r.PathValue("id")
 	// profile := profile_repo.FindProfile(id)
 	// if profile == nil {
 	// 	http.Error(w, "Profile not found", http.StatusNotFound)
@@ -22,7 +22,7 @@ func GET(w http.ResponseWriter, r *http.Request) {
 }
 
 func DELETE(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Query().Get("id")
+	id := r.PathValue("id")
 	fmt.Println(id)
 	// profile := profile_repo.DeleteProfile(id)
 	// if profile == -1 {
@@ -13,7 +13,7 @@ type User struct {
 }
 
 func GET(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Query().Get("id")
+	id := r.PathValue("id")
 	user := user_repo.FindUser(id)
 	if user == nil {
 		http.Error(w, "The user you are looking for does not exist", http.StatusNotFound)
@@ -29,7 +29,7 @@ func GET(w http.ResponseWriter, r *http.Request) {
 }
 
 func DELETE(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Query().Get("id")
+	id := r.PathValue("id")
 	user := user_repo.DeleteUser(id)
 	if user == -1 {
 		http.Error(w, "The user you are looking for does not exist", http.StatusNotFound)